@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	apiApp "github.com/plinkplenk/test-vortex/internal/app"
+)
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// runCommand runs subcommand instead of the server, args[0] is the command name
+func runCommand(params apiApp.Params, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(params, args[1:])
+	case "config":
+		return runConfig(params, args[1:])
+	case "export":
+		return runExport(params, args[1:])
+	case "import":
+		return runImport(params, args[1:])
+	case "replay":
+		return runReplay(params, args[1:])
+	case "loadgen":
+		return runLoadgen(params, args[1:])
+	default:
+		return fmt.Errorf("%w %q, available commands: migrate, config, export, import, replay, loadgen", ErrUnknownCommand, args[0])
+	}
+}
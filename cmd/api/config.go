@@ -0,0 +1,18 @@
+package main
+
+import (
+	"errors"
+	apiApp "github.com/plinkplenk/test-vortex/internal/app"
+	"github.com/plinkplenk/test-vortex/internal/config"
+	"os"
+)
+
+var ErrConfigUsage = errors.New("usage: config print")
+
+// runConfig prints effective config, it is validated before any command runs
+func runConfig(params apiApp.Params, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return ErrConfigUsage
+	}
+	return config.Print(os.Stdout, params.Config)
+}
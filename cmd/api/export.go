@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	apiApp "github.com/plinkplenk/test-vortex/internal/app"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	"io"
+	"os"
+	"time"
+)
+
+// parseTime parses optional RFC3339 flag value
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// runExport writes order history matching the flags to a file or stdout
+func runExport(params apiApp.Params, args []string) (err error) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", export.FormatCSV, "csv or parquet")
+	columns := flags.String("columns", "", "comma separated columns, every column by default")
+	compress := flags.Bool("gzip", false, "gzip csv, compress parquet columns with gzip instead of snappy")
+	out := flags.String("out", "", "output file, stdout by default")
+	from := flags.String("from", "", "inclusive RFC3339 lower bound of time placed")
+	to := flags.String("to", "", "exclusive RFC3339 upper bound of time placed")
+	var query export.Query
+	flags.StringVar(&query.ClientName, "client", "", "client name")
+	flags.StringVar(&query.ExchangeName, "exchange", "", "exchange name")
+	flags.StringVar(&query.Label, "label", "", "label")
+	flags.StringVar(&query.Pair, "pair", "", "pair")
+	flags.StringVar(&query.Side, "side", "", "buy or sell")
+	flags.StringVar(&query.Algorithm, "algorithm", "", "algorithm name placed")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if query.Columns, err = export.ParseColumns(*columns); err != nil {
+		return err
+	}
+	if query.From, err = parseTime(*from); err != nil {
+		return err
+	}
+	if query.To, err = parseTime(*to); err != nil {
+		return err
+	}
+	if err := validators.ValidateExportQuery(query); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, file.Close())
+		}()
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+	writer, err := export.NewWriter(buffered, *format, query.Columns, *compress)
+	if err != nil {
+		return err
+	}
+	exporter, closeConn, err := apiApp.NewExporter(params)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, closeConn())
+	}()
+	rows, err := exporter.Export(context.Background(), query, writer)
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	// logs are written to stdout with the file, so the summary goes to stderr
+	_, _ = fmt.Fprintf(os.Stderr, "exported %d rows\n", rows)
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	apiApp "github.com/plinkplenk/test-vortex/internal/app"
+	"github.com/plinkplenk/test-vortex/internal/importer"
+	"os"
+	"strconv"
+)
+
+var ErrImportUsage = errors.New("usage: import -kind history|depth [-format csv|ndjson] [-mapping file] <file>")
+
+// runImport inserts orders or order book levels of the file in batches, rejected rows are logged
+// or written to the rejects file as CSV of line and reason
+func runImport(params apiApp.Params, args []string) (err error) {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	kind := flags.String("kind", "", "history or depth")
+	format := flags.String("format", importer.FormatCSV, "csv or ndjson")
+	mappingFile := flags.String("mapping", "", "YAML or JSON file mapping fields to columns and their defaults")
+	batchSize := flags.Int("batch", importer.DefaultBatchSize, "rows inserted at once")
+	rejectsFile := flags.String("rejects", "", "CSV file of rejected rows, they are logged by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *kind == "" || flags.NArg() != 1 {
+		return ErrImportUsage
+	}
+	var mapping importer.Mapping
+	if *mappingFile != "" {
+		if mapping, err = importer.LoadMapping(*mappingFile); err != nil {
+			return err
+		}
+	}
+	rejected := func(line int, reason error) {
+		params.Logger.Warn("Rejected row", "line", line, "error", reason)
+	}
+	if *rejectsFile != "" {
+		file, createErr := os.Create(*rejectsFile)
+		if createErr != nil {
+			return createErr
+		}
+		rejects := csv.NewWriter(file)
+		defer func() {
+			rejects.Flush()
+			err = errors.Join(err, rejects.Error(), file.Close())
+		}()
+		_ = rejects.Write([]string{"line", "error"})
+		rejected = func(line int, reason error) {
+			_ = rejects.Write([]string{strconv.Itoa(line), reason.Error()})
+		}
+	}
+
+	input, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, input.Close())
+	}()
+	repository, closeConn, err := apiApp.NewOrdersRepository(params)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, closeConn())
+	}()
+	im, err := importer.New(
+		repository,
+		importer.Params{
+			Kind:      *kind,
+			Format:    *format,
+			Mapping:   mapping,
+			BatchSize: *batchSize,
+			Rejected:  rejected,
+			Logger:    params.Logger,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	report, err := im.Import(context.Background(), bufio.NewReaderSize(input, 1024*1024))
+	params.Logger.Info(
+		"Import finished",
+		"rows", report.Rows,
+		"imported", report.Imported,
+		"rejected", report.Rejected,
+	)
+	if err != nil {
+		return fmt.Errorf("importing %s: %w", flags.Arg(0), err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/api/middleware"
+	apiApp "github.com/plinkplenk/test-vortex/internal/app"
+	"github.com/plinkplenk/test-vortex/internal/loadgen"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+// runLoadgen sends synthetic order books and orders to the api at the target rate and prints latency and errors
+func runLoadgen(params apiApp.Params, args []string) error {
+	flags := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	run := loadgen.RunParams{}
+	flags.StringVar(&run.URL, "url", "http://localhost:"+params.Config.Server.Port+"/v1", "api url with version prefix")
+	flags.Float64Var(&run.RPS, "rps", 100, "target requests per second")
+	flags.DurationVar(&run.Duration, "duration", time.Minute, "duration of the run")
+	flags.Float64Var(&run.ReadRatio, "read", 0.8, "share of read requests")
+	flags.IntVar(&run.Workers, "workers", 64, "requests in flight, requests due when every worker is busy are dropped")
+	market := loadgen.MarketParams{}
+	flags.StringVar(&market.Exchange, "exchange", "loadgen", "exchange name of generated data")
+	flags.IntVar(&market.Clients, "clients", 100, "number of clients")
+	flags.IntVar(&market.Labels, "labels", 5, "number of labels of every client")
+	flags.IntVar(&market.Pairs, "pairs", 10, "number of pairs")
+	flags.IntVar(&market.Depth, "depth", 20, "levels of an order book")
+	flags.Float64Var(&market.SpreadBps, "spread", 10, "spread of order books in basis points")
+	flags.Float64Var(&market.VolatilityBps, "volatility", 5, "standard deviation of a mid step in basis points")
+	flags.Float64Var(&market.StartMid, "mid", 100, "starting mid price of pairs")
+	flags.Int64Var(&market.Seed, "seed", time.Now().UnixNano(), "seed of generated data")
+	apiKey := flags.String("api-key", "", "api key sent in the "+middleware.APIKeyHeader+" header")
+	token := flags.String("token", "", "bearer token sent in the Authorization header")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	generator, err := loadgen.NewGenerator(market)
+	if err != nil {
+		return err
+	}
+	run.Header = http.Header{}
+	if *apiKey != "" {
+		run.Header.Set(middleware.APIKeyHeader, *apiKey)
+	}
+	if *token != "" {
+		run.Header.Set("Authorization", "Bearer "+*token)
+	}
+	runner, err := loadgen.NewRunner(generator, run)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := runner.Seed(ctx); err != nil {
+		return err
+	}
+	params.Logger.Info("load started", "url", run.URL, "rps", run.RPS, "duration", run.Duration, "seed", market.Seed)
+	report := runner.Run(ctx)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "OPERATION\tREQUESTS\tERRORS\tERROR RATE\tP50\tP90\tP99\tMAX")
+	for _, operation := range append(report.Operations, report.Total) {
+		_, _ = fmt.Fprintf(
+			w,
+			"%s\t%d\t%d\t%.2f%%\t%s\t%s\t%s\t%s\n",
+			operation.Operation,
+			operation.Requests,
+			operation.Errors,
+			operation.ErrorRate()*100,
+			operation.P50.Round(time.Microsecond),
+			operation.P90.Round(time.Microsecond),
+			operation.P99.Round(time.Microsecond),
+			operation.Max.Round(time.Microsecond),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(
+		os.Stdout,
+		"\n%.1f of %.1f target requests/s over %s, %d dropped, statuses %v\n",
+		report.RPS(),
+		report.Target,
+		report.Elapsed.Round(time.Millisecond),
+		report.Dropped,
+		report.Total.Statuses,
+	)
+	return err
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
+	"fmt"
 	apiApp "github.com/plinkplenk/test-vortex/internal/app"
 	"github.com/plinkplenk/test-vortex/internal/config"
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"github.com/plinkplenk/test-vortex/internal/tracing"
 	"log"
 	"log/slog"
 	"net/http"
@@ -13,17 +17,35 @@ import (
 	"syscall"
 )
 
-var debug = flag.Bool("debug", false, "-debug")
+var (
+	debug       = flag.Bool("debug", false, "-debug")
+	configFile  = flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file, CONFIG_FILE by default")
+	configFlags = config.Flags(flag.CommandLine)
+)
 
 func main() {
 	flag.Parse()
-	cfg := config.Setup()
-	loggingLevel := slog.LevelInfo
 	if *debug {
-		loggingLevel = slog.LevelDebug
+		configFlags["log.level"] = "debug"
+	}
+	sources := config.Sources{File: *configFile, Flags: configFlags}
+	cfg, err := config.Load(sources)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
+	loggingLevel := new(slog.LevelVar)
+	loggingLevel.Set(cfg.Log.SlogLevel())
 	logger := setupLogger(loggingLevel)
-	app, err := apiApp.New(apiApp.Params{Config: cfg, Logger: logger, Debug: *debug})
+	params := apiApp.Params{Config: cfg, Logger: logger, LogLevel: loggingLevel, Debug: *debug}
+	if flag.NArg() > 0 {
+		if err := runCommand(params, flag.Args()); err != nil {
+			logger.Error("Command failed", "command", flag.Arg(0), "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+	app, err := apiApp.New(params)
 	if err != nil {
 		panic(err)
 	}
@@ -33,19 +55,57 @@ func main() {
 			log.Fatal("Error while running app", "error:", err)
 		}
 	}()
-	exit := make(chan os.Signal)
+	exit := make(chan os.Signal, 1)
 	signal.Notify(exit, syscall.SIGINT, syscall.SIGTERM)
-	<-exit
-	logger.Info("Shutting down server...")
-	app.Stop()
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	fileChanged := make(chan struct{}, 1)
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	if sources.File != "" && cfg.Server.ConfigWatchInterval > 0 {
+		go config.Watch(
+			watchCtx, sources.File, cfg.Server.ConfigWatchInterval, func() {
+				select {
+				case fileChanged <- struct{}{}:
+				default:
+				}
+			},
+		)
+	}
+	for {
+		select {
+		case <-exit:
+			logger.Info("Shutting down server...")
+			app.Stop()
+			return
+		case <-reload:
+			reloadConfig(app, sources, logger)
+		case <-fileChanged:
+			reloadConfig(app, sources, logger)
+		}
+	}
 }
 
-func setupLogger(level slog.Level) *slog.Logger {
+// reloadConfig loads config from the same sources as on startup, running config is kept if it is invalid
+func reloadConfig(app *apiApp.App, sources config.Sources, logger *slog.Logger) {
+	cfg, err := config.Load(sources)
+	if err != nil {
+		logger.Error("Config is not reloaded", "error", err)
+		return
+	}
+	app.Reload(context.Background(), cfg)
+}
+
+func setupLogger(level slog.Leveler) *slog.Logger {
 	return slog.New(
-		slog.NewTextHandler(
-			os.Stdout, &slog.HandlerOptions{
-				Level: level,
-			},
+		requestid.NewLogHandler(
+			tracing.NewLogHandler(
+				slog.NewTextHandler(
+					os.Stdout, &slog.HandlerOptions{
+						Level: level,
+					},
+				),
+			),
 		),
 	)
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	apiApp "github.com/plinkplenk/test-vortex/internal/app"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+var ErrMigrateUsage = errors.New("usage: migrate up|down|status|to <version>")
+
+func runMigrate(params apiApp.Params, args []string) (err error) {
+	if len(args) == 0 {
+		return ErrMigrateUsage
+	}
+	migrator, closeConn, err := apiApp.NewMigrator(params)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, closeConn())
+	}()
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		return migrator.Down(ctx)
+	case "to":
+		if len(args) < 2 {
+			return ErrMigrateUsage
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return ErrMigrateUsage
+		}
+		return migrator.To(ctx, version)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format(time.DateTime)
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return ErrMigrateUsage
+	}
+}
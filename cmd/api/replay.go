@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	apiApp "github.com/plinkplenk/test-vortex/internal/app"
+	"github.com/plinkplenk/test-vortex/internal/replay"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+var ErrReplayUsage = errors.New("usage: replay -exchange <exchange> -pair <pair> -from <time> -to <time> [-speed 1]")
+
+// runReplay writes events of the pair as JSON lines when they are due, like the replay endpoint
+func runReplay(params apiApp.Params, args []string) (err error) {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	query := replay.Query{}
+	flags.StringVar(&query.ExchangeName, "exchange", "", "exchange name")
+	flags.StringVar(&query.Pair, "pair", "", "pair")
+	flags.StringVar(&query.ClientName, "client", "", "replays orders of the client only")
+	flags.Float64Var(&query.Speed, "speed", 1, "divides time between events, 0 writes events without waiting")
+	from := flags.String("from", "", "inclusive RFC3339 start of the window")
+	to := flags.String("to", "", "exclusive RFC3339 end of the window")
+	out := flags.String("out", "", "output file, stdout by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *from == "" || *to == "" {
+		return ErrReplayUsage
+	}
+	if query.From, err = parseTime(*from); err != nil {
+		return err
+	}
+	if query.To, err = parseTime(*to); err != nil {
+		return err
+	}
+	if err := validators.ValidateReplayQuery(query); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			err = errors.Join(err, file.Close())
+		}()
+		w = file
+	}
+	buffered := bufio.NewWriter(w)
+	replayer, closeConn, err := apiApp.NewReplayer(params)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, closeConn())
+	}()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var events int
+	encoder := json.NewEncoder(buffered)
+	err = replayer.Replay(
+		ctx, query, func(event replay.Event) error {
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+			events++
+			// paced events are written as they are due, so consumers of the output see them like live data
+			if query.Speed > 0 {
+				return buffered.Flush()
+			}
+			return nil
+		},
+	)
+	if errors.Is(err, context.Canceled) {
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	// logs are written to stdout with the events, so the summary goes to stderr
+	_, _ = fmt.Fprintf(os.Stderr, "replayed %d events\n", events)
+	return nil
+}
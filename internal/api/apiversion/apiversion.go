@@ -0,0 +1,52 @@
+package apiversion
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Header is set on responses of versioned routes to the version serving the request
+const Header = "API-Version"
+
+const (
+	// V1 payloads are frozen, unversioned routes are served by v1
+	V1 = "1"
+	// V2 payloads may change independently of v1
+	V2 = "2"
+)
+
+// Prefix returns the path prefix routes of the version are mounted at
+func Prefix(version string) string {
+	return "/v" + version
+}
+
+type ErrorDetails struct {
+	// Code is derived from the status like "not_found" or "too_many_requests"
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// ErrorBody returns the body of an error response of the version.
+// V1 responds with the message in "error" and the request id in "requestId", v2 with ErrorDetails in "error"
+func ErrorBody(version string, code int, message, requestID string) map[string]any {
+	if version == V2 {
+		return map[string]any{
+			"error": ErrorDetails{Code: ErrorCode(code), Message: message, RequestID: requestID},
+		}
+	}
+	body := map[string]any{"error": message}
+	if requestID != "" {
+		body["requestId"] = requestID
+	}
+	return body
+}
+
+// ErrorCode returns snake case status text of the code
+func ErrorCode(code int) string {
+	text := http.StatusText(code)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(text, "-", " ")), " ", "_")
+}
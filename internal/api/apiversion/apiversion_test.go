@@ -0,0 +1,28 @@
+package apiversion
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "not_found", ErrorCode(http.StatusNotFound))
+	assert.Equal(t, "too_many_requests", ErrorCode(http.StatusTooManyRequests))
+	assert.Equal(t, "multi_status", ErrorCode(http.StatusMultiStatus))
+	assert.Equal(t, "error", ErrorCode(599))
+}
+
+func TestErrorBody(t *testing.T) {
+	assert.Equal(
+		t,
+		map[string]any{"error": "order not found", "requestId": "req-1"},
+		ErrorBody(V1, http.StatusNotFound, "order not found", "req-1"),
+	)
+	assert.Equal(t, map[string]any{"error": "order not found"}, ErrorBody("", http.StatusNotFound, "order not found", ""))
+	assert.Equal(
+		t,
+		map[string]any{"error": ErrorDetails{Code: "conflict", Message: "invalid order status transition"}},
+		ErrorBody(V2, http.StatusConflict, "invalid order status transition", ""),
+	)
+}
@@ -0,0 +1,159 @@
+package codec
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Codec encodes bodies of a media type. Values are the ones handlers encode as JSON,
+// so codecs other than JSON follow json struct tags
+type Codec interface {
+	// ContentType is the media type of encoded bodies
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	mu sync.RWMutex
+	// codecs are in the order of preference, the first one is used for wildcards
+	codecs = []Codec{JSON{}, MessagePack{}, Protobuf{}}
+	// byType has codecs by content type and aliases
+	byType = map[string]Codec{
+		JSONType:                JSON{},
+		MessagePackType:         MessagePack{},
+		"application/x-msgpack": MessagePack{},
+		ProtobufType:            Protobuf{},
+		"application/protobuf":  Protobuf{},
+	}
+)
+
+// Register adds codec of its content type and aliases, a codec registered for the same type is replaced.
+// It must be called before the server starts
+func Register(c Codec, aliases ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	registered := make([]Codec, 0, len(codecs)+1)
+	for _, existing := range codecs {
+		if existing.ContentType() != c.ContentType() {
+			registered = append(registered, existing)
+		}
+	}
+	codecs = append(registered, c)
+	for mediaType, existing := range byType {
+		if existing.ContentType() == c.ContentType() {
+			byType[mediaType] = c
+		}
+	}
+	for _, mediaType := range append([]string{c.ContentType()}, aliases...) {
+		byType[mediaType] = c
+	}
+}
+
+// Lookup returns codec of the content type, parameters like charset are ignored
+func Lookup(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := byType[mediaType]
+	return c, ok
+}
+
+// Negotiate returns codec of the most preferred media type of Accept header, wildcards match the first
+// registered codec. JSON is returned if no codec is accepted, so routes serving other media types
+// and clients sending Accept of other formats keep working
+func Negotiate(accept string) Codec {
+	mu.RLock()
+	defer mu.RUnlock()
+	if c, ok := negotiate(accept, func(Codec) bool { return true }); ok {
+		return c
+	}
+	return JSON{}
+}
+
+// NegotiateUntyped is Negotiate of routes without protobuf message types, codecs of any value are preferred
+// to Protobuf. False is returned if Protobuf is the only accepted codec
+func NegotiateUntyped(accept string) (Codec, bool) {
+	c := Negotiate(accept)
+	if c.ContentType() != ProtobufType {
+		return c, true
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return negotiate(accept, func(c Codec) bool { return c.ContentType() != ProtobufType })
+}
+
+// negotiate returns the most preferred allowed codec of Accept header, mu must be held
+func negotiate(accept string, allowed func(Codec) bool) (Codec, bool) {
+	var (
+		best  Codec
+		bestQ float64
+	)
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		// equal quality keeps the earlier media type
+		if q <= 0 || (best != nil && q <= bestQ) {
+			continue
+		}
+		if c, ok := match(mediaType, allowed); ok {
+			best, bestQ = c, q
+		}
+	}
+	return best, best != nil
+}
+
+// match returns allowed codec of media range, mu must be held
+func match(mediaRange string, allowed func(Codec) bool) (Codec, bool) {
+	if c, ok := byType[mediaRange]; ok {
+		return c, allowed(c)
+	}
+	prefix, ok := strings.CutSuffix(mediaRange, "*")
+	if mediaRange == "*/*" {
+		prefix = ""
+	}
+	if !ok || (prefix != "" && !strings.HasSuffix(prefix, "/")) {
+		return nil, false
+	}
+	for _, c := range codecs {
+		if strings.HasPrefix(c.ContentType(), prefix) && allowed(c) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Marshal encodes v with c. Values without protobuf message type, like error bodies, are encoded as JSON,
+// the codec of the body is returned with it
+func Marshal(c Codec, v any) (Codec, []byte, error) {
+	b, err := c.Marshal(v)
+	if errors.Is(err, ErrNoMessageType) {
+		c = JSON{}
+		b, err = c.Marshal(v)
+	}
+	return c, b, err
+}
+
+// FromHeader returns codec of Content-Type header, or JSON if the header has no registered codec,
+// because bodies were JSON regardless of their content type before other codecs were added
+func FromHeader(header http.Header) Codec {
+	if c, ok := Lookup(header.Get("Content-Type")); ok {
+		return c
+	}
+	return JSON{}
+}
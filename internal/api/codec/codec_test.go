@@ -0,0 +1,152 @@
+package codec
+
+import (
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNegotiate(t *testing.T) {
+	testTable := []struct {
+		name     string
+		accept   string
+		expected string
+	}{
+		{name: "EMPTY", accept: "", expected: JSONType},
+		{name: "JSON", accept: "application/json", expected: JSONType},
+		{name: "MESSAGEPACK", accept: "application/msgpack", expected: MessagePackType},
+		{name: "ALIAS", accept: "application/x-msgpack", expected: MessagePackType},
+		{name: "PROTOBUF", accept: "application/x-protobuf", expected: ProtobufType},
+		{name: "QUALITY", accept: "application/json;q=0.5, application/msgpack", expected: MessagePackType},
+		{name: "EQUAL QUALITY", accept: "application/x-protobuf, application/msgpack", expected: ProtobufType},
+		{name: "ZERO QUALITY", accept: "application/msgpack;q=0, */*;q=0.1", expected: JSONType},
+		{name: "WILDCARD", accept: "*/*", expected: JSONType},
+		{name: "TYPE WILDCARD", accept: "text/html, application/*;q=0.9", expected: JSONType},
+		{name: "UNSUPPORTED", accept: "text/csv", expected: JSONType},
+		{name: "INVALID", accept: "application/msgpack;q=high, ;;", expected: JSONType},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				assert.Equal(t, test.expected, Negotiate(test.accept).ContentType())
+			},
+		)
+	}
+}
+
+type payload struct {
+	Name   string    `json:"name"`
+	Price  float64   `json:"price"`
+	Levels []float64 `json:"levels"`
+	Time   time.Time `json:"time"`
+	Skip   string    `json:"-"`
+	Empty  string    `json:"empty,omitempty"`
+}
+
+func TestCodecs(t *testing.T) {
+	value := payload{
+		Name:   "BTC_USDT",
+		Price:  100.5,
+		Levels: []float64{1, 2.5},
+		Time:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Skip:   "skipped",
+	}
+	for _, c := range []Codec{JSON{}, MessagePack{}} {
+		t.Run(
+			c.ContentType(), func(t *testing.T) {
+				b, err := c.Marshal(value)
+				assert.NoError(t, err)
+				var decoded payload
+				assert.NoError(t, c.Unmarshal(b, &decoded))
+				assert.Equal(t, value.Name, decoded.Name)
+				assert.Equal(t, value.Price, decoded.Price)
+				assert.Equal(t, value.Levels, decoded.Levels)
+				assert.True(t, value.Time.Equal(decoded.Time))
+				assert.Empty(t, decoded.Skip)
+
+				var generic map[string]any
+				assert.NoError(t, c.Unmarshal(b, &generic))
+				assert.Contains(t, generic, "name", "fields are named by json tags")
+				assert.NotContains(t, generic, "empty")
+			},
+		)
+	}
+}
+
+func TestProtobuf(t *testing.T) {
+	message := &structpb.Value{Kind: &structpb.Value_StringValue{StringValue: "BTC_USDT"}}
+	b, err := Protobuf{}.Marshal(message)
+	assert.NoError(t, err)
+	var decoded structpb.Value
+	assert.NoError(t, Protobuf{}.Unmarshal(b, &decoded))
+	assert.Equal(t, "BTC_USDT", decoded.GetStringValue())
+
+	_, err = Protobuf{}.Marshal(payload{Name: "BTC_USDT"})
+	assert.ErrorIs(t, err, ErrNoMessageType, "values without message type have no protobuf encoding")
+	assert.ErrorIs(t, Protobuf{}.Unmarshal(b, &payload{}), ErrNoMessageType)
+
+	c, b, err := Marshal(Protobuf{}, map[string]any{"error": "not found"})
+	assert.NoError(t, err)
+	assert.Equal(t, JSONType, c.ContentType(), "bodies without message type fall back to JSON")
+	assert.JSONEq(t, `{"error": "not found"}`, string(b))
+}
+
+func TestNegotiateUntyped(t *testing.T) {
+	testTable := []struct {
+		name       string
+		accept     string
+		expected   string
+		acceptable bool
+	}{
+		{name: "EMPTY", accept: "", expected: JSONType, acceptable: true},
+		{name: "MESSAGEPACK", accept: "application/msgpack", expected: MessagePackType, acceptable: true},
+		{name: "UNSUPPORTED", accept: "text/csv", expected: JSONType, acceptable: true},
+		{
+			name:       "PROTOBUF PREFERRED",
+			accept:     "application/x-protobuf, application/msgpack;q=0.5",
+			expected:   MessagePackType,
+			acceptable: true,
+		},
+		{name: "PROTOBUF WILDCARD", accept: "application/x-protobuf, */*;q=0.1", expected: JSONType, acceptable: true},
+		{name: "PROTOBUF ONLY", accept: "application/x-protobuf", acceptable: false},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c, ok := NegotiateUntyped(test.accept)
+				assert.Equal(t, test.acceptable, ok)
+				if ok {
+					assert.Equal(t, test.expected, c.ContentType())
+				}
+			},
+		)
+	}
+}
+
+func TestFromHeader(t *testing.T) {
+	header := http.Header{}
+	assert.Equal(t, JSONType, FromHeader(header).ContentType())
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	assert.Equal(t, JSONType, FromHeader(header).ContentType(), "bodies of other types are decoded as JSON")
+	header.Set("Content-Type", "application/msgpack; charset=utf-8")
+	assert.Equal(t, MessagePackType, FromHeader(header).ContentType())
+}
+
+type textCodec struct {
+	JSON
+}
+
+func (textCodec) ContentType() string {
+	return "application/vnd.test+json"
+}
+
+func TestRegister(t *testing.T) {
+	Register(textCodec{}, "application/vnd.test")
+	c, ok := Lookup("application/vnd.test")
+	assert.True(t, ok)
+	assert.Equal(t, "application/vnd.test+json", c.ContentType())
+	assert.Equal(t, "application/vnd.test+json", Negotiate("application/vnd.test+json").ContentType())
+	assert.Equal(t, JSONType, Negotiate("*/*").ContentType(), "registered codecs are after the built in ones")
+}
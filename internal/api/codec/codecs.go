@@ -0,0 +1,78 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrNoMessageType = errors.New("value has no protobuf message type")
+
+const (
+	JSONType        = "application/json"
+	MessagePackType = "application/msgpack"
+	ProtobufType    = "application/x-protobuf"
+)
+
+type JSON struct{}
+
+func (JSON) ContentType() string {
+	return JSONType
+}
+
+func (JSON) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSON) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// MessagePack encodes fields by json struct tags, time.Time is encoded as MessagePack timestamp
+type MessagePack struct{}
+
+func (MessagePack) ContentType() string {
+	return MessagePackType
+}
+
+func (MessagePack) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MessagePack) Unmarshal(data []byte, v any) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(v)
+}
+
+// Protobuf encodes proto messages, other values have no schema of their own and are rejected with ErrNoMessageType.
+// Routes serving Protobuf exchange the messages of their gRPC methods
+type Protobuf struct{}
+
+func (Protobuf) ContentType() string {
+	return ProtobufType
+}
+
+func (Protobuf) Marshal(v any) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, ErrNoMessageType
+	}
+	return proto.Marshal(message)
+}
+
+func (Protobuf) Unmarshal(data []byte, v any) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return ErrNoMessageType
+	}
+	return proto.Unmarshal(data, message)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/candles"
+	candle "github.com/plinkplenk/test-vortex/internal/candles/service"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultCandleInterval = time.Minute
+	// defaultCandlesCount is used to compute "from" when it is not provided
+	defaultCandlesCount = 500
+)
+
+type CandlesHandler struct {
+	candleService candle.CandlesService
+	logger        *slog.Logger
+	now           func() time.Time
+}
+
+func NewCandlesHandler(candleService candle.CandlesService, logger *slog.Logger) *CandlesHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CandlesHandler{candleService: candleService, logger: logger, now: time.Now}
+}
+
+// parseCandlesQuery reads interval, from and to query params.
+// to defaults to current time and from defaults to defaultCandlesCount intervals before to
+func (ch *CandlesHandler) parseCandlesQuery(r *http.Request) (candles.Query, error) {
+	query := candles.Query{
+		ExchangeName: chi.URLParam(r, "exchange_name"),
+		Pair:         chi.URLParam(r, "pair"),
+		Interval:     defaultCandleInterval,
+		To:           ch.now().UTC(),
+	}
+	params := r.URL.Query()
+	if interval := params.Get("interval"); interval != "" {
+		parsed, err := candles.ParseInterval(interval)
+		if err != nil {
+			return query, validators.ErrInvalidInterval
+		}
+		query.Interval = parsed
+	}
+	to, err := parseTimeParam(r, "to", query.To)
+	if err != nil {
+		return query, err
+	}
+	query.To = to
+	from, err := parseTimeParam(r, "from", query.To.Add(-defaultCandlesCount*query.Interval))
+	if err != nil {
+		return query, err
+	}
+	query.From = from
+	return query, nil
+}
+
+func (ch *CandlesHandler) GetCandles(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		query, err := ch.parseCandlesQuery(r)
+		if err == nil {
+			err = validators.ValidateCandlesQuery(query)
+		}
+		if err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+				logError(ch.logger, r, err)
+			}
+			return
+		}
+		result, err := ch.candleService.GetCandles(ctx, query)
+		if err != nil {
+			ch.logger.DebugContext(
+				r.Context(),
+				"error while trying to get candles",
+				"query", query,
+				"error", err,
+			)
+			if err := response(j{"error": "something went wrong"}, http.StatusInternalServerError, w); err != nil {
+				logError(ch.logger, r, err)
+			}
+			return
+		}
+		if result == nil {
+			result = []candles.Candle{}
+		}
+		if err := response(j{"candles": result}, http.StatusOK, w); err != nil {
+			logError(ch.logger, r, err)
+		}
+	}
+}
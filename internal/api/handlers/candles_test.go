@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/candles"
+	mock_service "github.com/plinkplenk/test-vortex/internal/candles/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	invalidIntervalResponse, _  = json.Marshal(j{"error": validators.ErrInvalidInterval.Error()})
+	invalidTimeRangeResponse, _ = json.Marshal(j{"error": validators.ErrInvalidTimeRange.Error()})
+	invalidTimeResponse, _      = json.Marshal(j{"error": ErrInvalidTime.Error()})
+	tooManyCandlesResponse, _   = json.Marshal(j{"error": validators.ErrTooManyCandles.Error()})
+)
+
+func TestCandlesHandler_GetCandles(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	type mockBehavior func(s *mock_service.MockCandlesService, query candles.Query)
+	testTable := []struct {
+		name               string
+		params             string
+		inputQuery         candles.Query
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "SUCCESS",
+			params: "?interval=1h&from=2024-01-01T00:00:00Z&to=2024-01-01T02:00:00Z",
+			inputQuery: candles.Query{
+				ExchangeName: "some-exchange",
+				Pair:         "A_B",
+				Interval:     time.Hour,
+				From:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:           time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC),
+			},
+			mockBehavior: func(s *mock_service.MockCandlesService, query candles.Query) {
+				s.EXPECT().GetCandles(context.Background(), query).Return(
+					[]candles.Candle{
+						{
+							Time:        time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+							Open:        1,
+							High:        2,
+							Low:         0.5,
+							Close:       1.5,
+							BaseVolume:  10,
+							QuoteVolume: 12,
+							Trades:      3,
+						},
+					},
+					nil,
+				)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"candles":[{"time":"2024-01-01T00:00:00Z","open":1,"high":2,"low":0.5,"close":1.5,"baseVolume":10,"quoteVolume":12,"trades":3}]}`,
+		},
+		{
+			name:   "DEFAULTS",
+			params: "",
+			inputQuery: candles.Query{
+				ExchangeName: "some-exchange",
+				Pair:         "A_B",
+				Interval:     time.Minute,
+				From:         now.Add(-defaultCandlesCount * time.Minute),
+				To:           now,
+			},
+			mockBehavior: func(s *mock_service.MockCandlesService, query candles.Query) {
+				s.EXPECT().GetCandles(context.Background(), query).Return(nil, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"candles":[]}`,
+		},
+		{
+			name:   "INVALID INPUT (BAD INTERVAL)",
+			params: "?interval=1ms",
+			mockBehavior: func(s *mock_service.MockCandlesService, query candles.Query) {
+				s.EXPECT().GetCandles(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidIntervalResponse),
+		},
+		{
+			name:   "INVALID INPUT (BAD TIME)",
+			params: "?from=yesterday",
+			mockBehavior: func(s *mock_service.MockCandlesService, query candles.Query) {
+				s.EXPECT().GetCandles(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidTimeResponse),
+		},
+		{
+			name:   "INVALID INPUT (FROM AFTER TO)",
+			params: "?from=2024-01-01T02:00:00Z&to=2024-01-01T00:00:00Z",
+			mockBehavior: func(s *mock_service.MockCandlesService, query candles.Query) {
+				s.EXPECT().GetCandles(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidTimeRangeResponse),
+		},
+		{
+			name:   "INVALID INPUT (TOO MANY CANDLES)",
+			params: "?interval=1s&from=2023-01-01T00:00:00Z",
+			mockBehavior: func(s *mock_service.MockCandlesService, query candles.Query) {
+				s.EXPECT().GetCandles(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(tooManyCandlesResponse),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				candleService := mock_service.NewMockCandlesService(c)
+				test.mockBehavior(candleService, test.inputQuery)
+
+				handler := NewCandlesHandler(candleService, loggerStub)
+				handler.now = func() time.Time { return now }
+				router := chi.NewRouter()
+				router.Get("/{exchange_name}/{pair}", handler.GetCandles(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/some-exchange/A_B"+test.params, nil)
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/candles/{exchange_name}/{pair}", w)
+			},
+		)
+	}
+}
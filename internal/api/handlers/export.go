@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	exportService "github.com/plinkplenk/test-vortex/internal/export/service"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+var ErrInvalidGzip = errors.New("gzip must be true or false")
+
+type ExportHandler struct {
+	exportService exportService.ExportService
+	// newWriter is export.NewWriter, it is replaced by tests
+	newWriter func(w io.Writer, format string, columnNames []string, compress bool) (export.Writer, error)
+	logger    *slog.Logger
+}
+
+func NewExportHandler(exportService exportService.ExportService, logger *slog.Logger) *ExportHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ExportHandler{exportService: exportService, newWriter: export.NewWriter, logger: logger}
+}
+
+// fileWriter sends headers of exported file with its first bytes,
+// so errors before the first row are still responded with json
+type fileWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	fileName    string
+	written     bool
+}
+
+func (fw *fileWriter) Write(b []byte) (int, error) {
+	if !fw.written {
+		fw.written = true
+		fw.w.Header().Set("Content-Type", fw.contentType)
+		fw.w.Header().Set("Content-Disposition", `attachment; filename="`+fw.fileName+`"`)
+		fw.w.WriteHeader(http.StatusOK)
+	}
+	return fw.w.Write(b)
+}
+
+func parseExportQuery(r *http.Request) (export.Query, string, bool, error) {
+	params := r.URL.Query()
+	query := export.Query{
+		ClientName:   params.Get("client_name"),
+		ExchangeName: params.Get("exchange_name"),
+		Label:        params.Get("label"),
+		Pair:         params.Get("pair"),
+		Side:         params.Get("side"),
+		Algorithm:    params.Get("algorithm"),
+	}
+	format := export.FormatCSV
+	if f := params.Get("format"); f != "" {
+		format = f
+	}
+	if format != export.FormatCSV && format != export.FormatParquet {
+		return query, "", false, export.ErrUnknownFormat
+	}
+	var compress bool
+	if g := params.Get("gzip"); g != "" {
+		parsed, err := strconv.ParseBool(g)
+		if err != nil {
+			return query, "", false, ErrInvalidGzip
+		}
+		compress = parsed
+	}
+	columns, err := export.ParseColumns(params.Get("columns"))
+	if err != nil {
+		return query, "", false, err
+	}
+	query.Columns = columns
+	// export is not bounded by default, rows of the whole history are exported without from and to
+	if query.From, err = parseTimeParam(r, "from", query.From); err != nil {
+		return query, "", false, err
+	}
+	if query.To, err = parseTimeParam(r, "to", query.To); err != nil {
+		return query, "", false, err
+	}
+	return query, format, compress, nil
+}
+
+// ExportHistory streams order history rows matching the query as CSV or Parquet file.
+// If export fails after the file is started the connection is aborted, so clients don't take truncated file as complete
+func (eh *ExportHandler) ExportHistory(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		query, format, compress, err := parseExportQuery(r)
+		if err == nil {
+			err = validators.ValidateExportQuery(query)
+		}
+		if err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+				logError(eh.logger, r, err)
+			}
+			return
+		}
+		if err := auth.Authorize(r.Context(), query.ClientName, query.ExchangeName); err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusForbidden, w); err != nil {
+				logError(eh.logger, r, err)
+			}
+			return
+		}
+		file := &fileWriter{
+			w:           w,
+			contentType: export.ContentType(format, compress),
+			fileName:    export.FileName(format, compress),
+		}
+		writer, err := eh.newWriter(file, format, query.Columns, compress)
+		if err != nil {
+			logError(eh.logger, r, err)
+			if err := response(j{"error": "something went wrong"}, http.StatusInternalServerError, w); err != nil {
+				logError(eh.logger, r, err)
+			}
+			return
+		}
+		rows, err := eh.exportService.Export(ctx, query, writer)
+		if err == nil {
+			err = writer.Close()
+		}
+		if err != nil {
+			eh.logger.DebugContext(
+				r.Context(),
+				"error while trying to export order history",
+				"query", query,
+				"rows", rows,
+				"error", err,
+			)
+			if file.written {
+				panic(http.ErrAbortHandler)
+			}
+			if err := response(j{"error": "something went wrong"}, http.StatusInternalServerError, w); err != nil {
+				logError(eh.logger, r, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	mock_service "github.com/plinkplenk/test-vortex/internal/export/service/mocks"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExportHandler_ExportHistory(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	history := &orders.History{
+		Client:     orders.Client{ClientName: "client", ExchangeName: "exchange", Label: "label", Pair: "A_B"},
+		Side:       orders.SideBuy,
+		Price:      0.1,
+		BaseQty:    2,
+		TimePlaced: from,
+	}
+	writeRows := func(_ context.Context, _ export.Query, writer export.Writer) (uint64, error) {
+		return 1, writer.Write(history)
+	}
+	_, unknownColumnErr := export.ParseColumns("secret")
+	unknownColumnResponse, _ := json.Marshal(j{"error": unknownColumnErr.Error()})
+	unknownFormatResponse, _ := json.Marshal(j{"error": export.ErrUnknownFormat.Error()})
+	invalidGzipResponse, _ := json.Marshal(j{"error": ErrInvalidGzip.Error()})
+	invalidSideResponse, _ := json.Marshal(j{"error": validators.ErrInvalidSide.Error()})
+	forbiddenResponse, _ := json.Marshal(j{"error": auth.ErrForbidden.Error()})
+	type mockBehavior func(s *mock_service.MockExportService)
+	testTable := []struct {
+		name                string
+		params              string
+		identity            *auth.Identity
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedDisposition string
+		expectedBody        string
+	}{
+		{
+			name:   "SUCCESS",
+			params: "?client_name=client&exchange_name=exchange&from=2024-01-01T00:00:00Z&to=2024-01-02T00:00:00Z&columns=time_placed,side,price",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(
+					context.Background(),
+					export.Query{
+						ClientName:   "client",
+						ExchangeName: "exchange",
+						From:         from,
+						To:           to,
+						Columns:      []string{"time_placed", "side", "price"},
+					},
+					gomock.Any(),
+				).DoAndReturn(writeRows)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedDisposition: `attachment; filename="order_history.csv"`,
+			expectedBody:        "time_placed,side,price\n2024-01-01T00:00:00Z,buy,0.1\n",
+		},
+		{
+			name:   "EMPTY",
+			params: "?columns=pair",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), export.Query{Columns: []string{"pair"}}, gomock.Any()).Return(uint64(0), nil)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedDisposition: `attachment; filename="order_history.csv"`,
+			expectedBody:        "pair\n",
+		},
+		{
+			name:   "PARQUET",
+			params: "?format=parquet&gzip=true",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), export.Query{Columns: export.Columns()}, gomock.Any()).DoAndReturn(writeRows)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: "application/vnd.apache.parquet",
+			expectedDisposition: `attachment; filename="order_history.parquet"`,
+		},
+		{
+			name:   "SERVICE ERROR",
+			params: "",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Return(uint64(0), errors.New("db is down"))
+			},
+			expectedStatusCode:  http.StatusInternalServerError,
+			expectedContentType: "application/json",
+			expectedBody:        `{"error":"something went wrong"}`,
+		},
+		{
+			name:     "FORBIDDEN",
+			params:   "?exchange_name=exchange",
+			identity: &auth.Identity{Name: "desk", Clients: []string{"client"}, Exchanges: []string{auth.Wildcard}},
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/json",
+			expectedBody:        string(forbiddenResponse),
+		},
+		{
+			name:   "INVALID INPUT (UNKNOWN COLUMN)",
+			params: "?columns=secret",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        string(unknownColumnResponse),
+		},
+		{
+			name:   "INVALID INPUT (UNKNOWN FORMAT)",
+			params: "?format=xlsx",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        string(unknownFormatResponse),
+		},
+		{
+			name:   "INVALID INPUT (GZIP)",
+			params: "?gzip=maybe",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        string(invalidGzipResponse),
+		},
+		{
+			name:   "INVALID INPUT (SIDE)",
+			params: "?side=long",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        string(invalidSideResponse),
+		},
+		{
+			name:   "INVALID INPUT (FROM AFTER TO)",
+			params: "?from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z",
+			mockBehavior: func(s *mock_service.MockExportService) {
+				s.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        string(invalidTimeRangeResponse),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				exportService := mock_service.NewMockExportService(c)
+				test.mockBehavior(exportService)
+
+				handler := NewExportHandler(exportService, loggerStub)
+				router := chi.NewRouter()
+				router.Get("/export/orders/history", handler.ExportHistory(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/export/orders/history"+test.params, nil)
+				if test.identity != nil {
+					r = r.WithContext(auth.WithIdentity(r.Context(), *test.identity))
+				}
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, test.expectedDisposition, w.Header().Get("Content-Disposition"))
+				if test.expectedBody != "" {
+					assert.Equal(t, test.expectedBody, w.Body.String())
+				} else {
+					assert.NotEmpty(t, w.Body.Bytes())
+				}
+				assertDocumented(t, http.MethodGet, "/export/orders/history", w)
+			},
+		)
+	}
+}
+
+func TestExportHandler_ExportHistory_WriterError(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	exportService := mock_service.NewMockExportService(c)
+	exportService.EXPECT().Export(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	handler := NewExportHandler(exportService, loggerStub)
+	handler.newWriter = func(io.Writer, string, []string, bool) (export.Writer, error) {
+		return nil, errors.New("writer can't be created")
+	}
+	router := chi.NewRouter()
+	router.Get("/export/orders/history", handler.ExportHistory(context.Background()))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export/orders/history", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+	assert.JSONEq(t, `{"error": "something went wrong"}`, w.Body.String())
+	assertDocumented(t, http.MethodGet, "/export/orders/history", w)
+}
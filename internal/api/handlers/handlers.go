@@ -1,33 +1,119 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/api/apiversion"
+	"github.com/plinkplenk/test-vortex/internal/api/codec"
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
 	"log/slog"
 	"net/http"
+	"time"
+)
+
+var (
+	ErrInvalidTime = errors.New("from and to must be RFC3339 timestamps")
+	ErrInvalidBody = errors.New("request body is not valid json")
 )
 
 type j = map[string]any
 
 func response(message j, code int, w http.ResponseWriter) error {
-	w.WriteHeader(code)
+	// request id is set by middleware.RequestID, it is added to error bodies so clients can report it.
+	// Version is set by middleware.APIVersion, error bodies of every version have their own shape
+	if text, ok := message["error"].(string); ok && code >= http.StatusBadRequest {
+		message = apiversion.ErrorBody(
+			w.Header().Get(apiversion.Header), code, text, w.Header().Get(requestid.Header),
+		)
+	}
 	if message == nil {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(code)
 		return nil
 	}
-	b, err := json.Marshal(message)
+	// codec is selected by middleware.Negotiate, responses are JSON without it and with codecs of message types only
+	c, b, err := codec.Marshal(codec.FromHeader(w.Header()), message)
 	if err != nil {
 		return err
 	}
+	w.Header().Set("Content-Type", c.ContentType())
+	w.WriteHeader(code)
 	if _, err = w.Write(b); err != nil {
 		return err
 	}
 	return nil
 }
 
+// messageResponse responds with typed message if Protobuf is negotiated and with message otherwise,
+// typed is the message of the same gRPC method
+func messageResponse(message j, typed func() proto.Message, code int, w http.ResponseWriter) error {
+	c := codec.FromHeader(w.Header())
+	if c.ContentType() != codec.ProtobufType {
+		return response(message, code, w)
+	}
+	b, err := c.Marshal(typed())
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(code)
+	if _, err = w.Write(b); err != nil {
+		return err
+	}
+	return nil
+}
+
+// decode decodes request body with the codec of its content type
+func decode(r *http.Request, body []byte, v any) error {
+	return codec.FromHeader(r.Header).Unmarshal(body, v)
+}
+
+// decodeMessage is decode of routes with protobuf messages, Protobuf bodies are decoded into message
+// of the same gRPC method and set to v by fromMessage
+func decodeMessage[M proto.Message](r *http.Request, body []byte, v any, message M, fromMessage func(M)) error {
+	c := codec.FromHeader(r.Header)
+	if c.ContentType() != codec.ProtobufType {
+		return c.Unmarshal(body, v)
+	}
+	if err := c.Unmarshal(body, message); err != nil {
+		return err
+	}
+	fromMessage(message)
+	return nil
+}
+
+// requestContext returns ctx carrying the span and the id of the request,
+// so service and repository spans join the request trace and their logs have the request id
+func requestContext(ctx context.Context, r *http.Request) context.Context {
+	if span := trace.SpanFromContext(r.Context()); span.SpanContext().IsValid() {
+		ctx = trace.ContextWithSpan(ctx, span)
+	}
+	if id := requestid.FromContext(r.Context()); id != "" {
+		ctx = requestid.WithID(ctx, id)
+	}
+	return ctx
+}
+
 func logError(logger *slog.Logger, r *http.Request, err error) {
-	logger.Error(
+	logger.ErrorContext(
+		r.Context(),
 		"Error while responding",
 		"URL", r.URL,
 		"METHOD", r.Method,
 		"error", err,
 	)
 }
+
+// parseTimeParam parses RFC3339 query param, defaultValue is returned if param is not provided
+func parseTimeParam(r *http.Request, name string, defaultValue time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidTime
+	}
+	return parsed, nil
+}
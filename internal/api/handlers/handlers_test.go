@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/api/apiversion"
+	"github.com/plinkplenk/test-vortex/internal/api/openapi"
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var apiDocument = func() *openapi.Document {
+	document, err := openapi.Load()
+	if err != nil {
+		panic(err)
+	}
+	return document
+}()
+
+// assertDocumented checks that the response conforms to the operation documented at pattern
+func assertDocumented(t *testing.T, method, pattern string, w *httptest.ResponseRecorder) {
+	t.Helper()
+	assert.NoError(t, apiDocument.ValidateResponse(method, pattern, w.Code, w.Header(), w.Body.Bytes()))
+}
+
+func TestResponse_RequestID(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set(requestid.Header, "req-1")
+	assert.NoError(t, response(j{"error": "db is down"}, http.StatusInternalServerError, w))
+	assert.Equal(t, `{"error":"db is down","requestId":"req-1"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	w.Header().Set(requestid.Header, "req-2")
+	assert.NoError(t, response(j{"orderId": "1"}, http.StatusCreated, w))
+	assert.Equal(t, `{"orderId":"1"}`, w.Body.String(), "request id is added only to errors")
+
+	w = httptest.NewRecorder()
+	w.Header().Set(requestid.Header, "req-3")
+	w.Header().Set(apiversion.Header, apiversion.V2)
+	assert.NoError(t, response(j{"error": "order not found"}, http.StatusNotFound, w))
+	assert.Equal(t, `{"error":{"code":"not_found","message":"order not found","requestId":"req-3"}}`, w.Body.String())
+	assertDocumented(t, http.MethodGet, "/orders/lifecycle/{order_id}", w)
+}
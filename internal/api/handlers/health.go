@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/health"
+	"log/slog"
+	"net/http"
+)
+
+type HealthHandler struct {
+	health *health.Health
+	logger *slog.Logger
+}
+
+func NewHealthHandler(health *health.Health, logger *slog.Logger) *HealthHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &HealthHandler{health: health, logger: logger}
+}
+
+// Live responds while the process is able to serve requests, dependencies are not checked
+func (hh *HealthHandler) Live(_ context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := response(j{"status": health.StatusOK}, http.StatusOK, w); err != nil {
+			logError(hh.logger, r, err)
+		}
+	}
+}
+
+// Ready responds with 503 if any dependency check fails or the server is shutting down
+func (hh *HealthHandler) Ready(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		report := hh.health.Ready(ctx)
+		code := http.StatusOK
+		if report.Status != health.StatusOK {
+			code = http.StatusServiceUnavailable
+			hh.logger.DebugContext(r.Context(), "Service is not ready", "checks", report.Checks)
+		}
+		if err := response(j{"status": report.Status, "checks": report.Checks}, code, w); err != nil {
+			logError(hh.logger, r, err)
+		}
+	}
+}
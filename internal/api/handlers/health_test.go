@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/health"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHealthHandler_Ready(t *testing.T) {
+	testTable := []struct {
+		name               string
+		check              health.Check
+		draining           bool
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "READY",
+			check:              func(context.Context) (any, error) { return nil, nil },
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"checks":{"clickhouse":{"status":"ok"}},"status":"ok"}`,
+		},
+		{
+			name:               "DEPENDENCY DOWN",
+			check:              func(context.Context) (any, error) { return nil, errors.New("connection refused") },
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       `{"checks":{"clickhouse":{"status":"fail","error":"connection refused"}},"status":"fail"}`,
+		},
+		{
+			name:               "DRAINING",
+			check:              func(context.Context) (any, error) { return nil, nil },
+			draining:           true,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody: `{"checks":{"clickhouse":{"status":"ok"},` +
+				`"draining":{"status":"fail","error":"server is shutting down"}},"status":"fail"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				h := health.New(time.Second)
+				h.Register("clickhouse", test.check)
+				if test.draining {
+					h.SetDraining()
+				}
+				handler := NewHealthHandler(h, loggerStub)
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+				handler.Ready(context.Background()).ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/readyz", w)
+
+				w = httptest.NewRecorder()
+				handler.Live(context.Background()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+				assert.Equal(t, http.StatusOK, w.Code)
+				assertDocumented(t, http.MethodGet, "/healthz", w)
+			},
+		)
+	}
+}
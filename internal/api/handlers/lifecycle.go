@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/plinkplenk/test-vortex/internal/api/schemas"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	order "github.com/plinkplenk/test-vortex/internal/orders/service"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+var ErrInvalidOrderID = errors.New("order id must be a valid uuid")
+
+type LifecycleHandler struct {
+	lifecycleService order.LifecycleService
+	logger           *slog.Logger
+}
+
+func NewLifecycleHandler(lifecycleService order.LifecycleService, logger *slog.Logger) *LifecycleHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LifecycleHandler{lifecycleService: lifecycleService, logger: logger}
+}
+
+// lifecycleErrorResponse responds with status matching lifecycle service error
+func (lh *LifecycleHandler) lifecycleErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	code := http.StatusInternalServerError
+	message := "something went wrong"
+	switch {
+	case errors.Is(err, orders.ErrOrderNotFound):
+		code, message = http.StatusNotFound, err.Error()
+	case errors.Is(err, orders.ErrInvalidTransition), errors.Is(err, orders.ErrOverfill),
+		errors.Is(err, orders.ErrOrderExists):
+		code, message = http.StatusConflict, err.Error()
+	default:
+		lh.logger.DebugContext(
+			r.Context(),
+			"error while processing order lifecycle",
+			"URL", r.URL,
+			"error", err,
+		)
+	}
+	if err := response(j{"error": message}, code, w); err != nil {
+		logError(lh.logger, r, err)
+	}
+}
+
+// authorizeOrder returns orders.ErrOrderNotFound if identity of the request is not allowed to access
+// client of the order, so ids of orders of other clients are not revealed
+func (lh *LifecycleHandler) authorizeOrder(ctx context.Context, r *http.Request, orderID uuid.UUID) error {
+	// without identity every order is allowed, so the order is not loaded
+	if _, ok := auth.IdentityFromContext(r.Context()); !ok {
+		return nil
+	}
+	order, err := lh.lifecycleService.GetOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if err := auth.Authorize(r.Context(), order.ClientName, order.ExchangeName); err != nil {
+		return orders.ErrOrderNotFound
+	}
+	return nil
+}
+
+func (lh *LifecycleHandler) badRequest(w http.ResponseWriter, r *http.Request, err error) {
+	if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+		logError(lh.logger, r, err)
+	}
+}
+
+func (lh *LifecycleHandler) CreateOrder(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			logError(lh.logger, r, err)
+			return
+		}
+		var orderToCreate schemas.LifecycleOrderCreate
+		if err := decode(r, body, &orderToCreate); err != nil {
+			lh.badRequest(w, r, ErrInvalidBody)
+			return
+		}
+		if err := validators.ValidateLifecycleOrder(orderToCreate); err != nil {
+			lh.badRequest(w, r, err)
+			return
+		}
+		if err := auth.Authorize(
+			r.Context(),
+			orderToCreate.Client.ClientName,
+			orderToCreate.Client.ExchangeName,
+		); err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusForbidden, w); err != nil {
+				logError(lh.logger, r, err)
+			}
+			return
+		}
+		orderID, err := lh.lifecycleService.CreateOrder(
+			ctx, orders.Order{
+				ID:      orderToCreate.OrderID,
+				Client:  orderToCreate.Client,
+				Side:    orderToCreate.Side,
+				Type:    orderToCreate.Type,
+				BaseQty: orderToCreate.BaseQty,
+				Price:   orderToCreate.Price,
+			},
+		)
+		if err != nil {
+			lh.lifecycleErrorResponse(w, r, err)
+			return
+		}
+		if err := response(j{"orderId": orderID}, http.StatusCreated, w); err != nil {
+			logError(lh.logger, r, err)
+		}
+	}
+}
+
+func (lh *LifecycleHandler) GetOrderState(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		orderID, err := uuid.Parse(chi.URLParam(r, "order_id"))
+		if err != nil {
+			lh.badRequest(w, r, ErrInvalidOrderID)
+			return
+		}
+		state, err := lh.lifecycleService.GetOrderState(ctx, orderID)
+		if err == nil && auth.Authorize(r.Context(), state.ClientName, state.ExchangeName) != nil {
+			err = orders.ErrOrderNotFound
+		}
+		if err != nil {
+			lh.lifecycleErrorResponse(w, r, err)
+			return
+		}
+		if err := response(j{"order": state}, http.StatusOK, w); err != nil {
+			logError(lh.logger, r, err)
+		}
+	}
+}
+
+func (lh *LifecycleHandler) AppendEvent(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		orderID, err := uuid.Parse(chi.URLParam(r, "order_id"))
+		if err != nil {
+			lh.badRequest(w, r, ErrInvalidOrderID)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			logError(lh.logger, r, err)
+			return
+		}
+		var eventToCreate schemas.EventCreate
+		if err := decode(r, body, &eventToCreate); err != nil {
+			lh.badRequest(w, r, ErrInvalidBody)
+			return
+		}
+		if err := validators.ValidateEvent(eventToCreate); err != nil {
+			lh.badRequest(w, r, err)
+			return
+		}
+		if err := lh.authorizeOrder(ctx, r, orderID); err != nil {
+			lh.lifecycleErrorResponse(w, r, err)
+			return
+		}
+		state, err := lh.lifecycleService.AppendEvent(
+			ctx, orders.Event{
+				OrderID: orderID,
+				Status:  eventToCreate.Status,
+				Reason:  eventToCreate.Reason,
+				Time:    eventToCreate.Time,
+			},
+		)
+		if err != nil {
+			lh.lifecycleErrorResponse(w, r, err)
+			return
+		}
+		if err := response(j{"order": state}, http.StatusCreated, w); err != nil {
+			logError(lh.logger, r, err)
+		}
+	}
+}
+
+func (lh *LifecycleHandler) AppendFill(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		orderID, err := uuid.Parse(chi.URLParam(r, "order_id"))
+		if err != nil {
+			lh.badRequest(w, r, ErrInvalidOrderID)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			logError(lh.logger, r, err)
+			return
+		}
+		var fillToCreate schemas.FillCreate
+		if err := decode(r, body, &fillToCreate); err != nil {
+			lh.badRequest(w, r, ErrInvalidBody)
+			return
+		}
+		if err := validators.ValidateFill(fillToCreate); err != nil {
+			lh.badRequest(w, r, err)
+			return
+		}
+		if err := lh.authorizeOrder(ctx, r, orderID); err != nil {
+			lh.lifecycleErrorResponse(w, r, err)
+			return
+		}
+		state, err := lh.lifecycleService.AppendFill(
+			ctx, orders.Fill{
+				ID:                 fillToCreate.FillID,
+				OrderID:            orderID,
+				BaseQty:            fillToCreate.BaseQty,
+				Price:              fillToCreate.Price,
+				CommissionQuoteQty: fillToCreate.CommissionQuoteQty,
+				Time:               fillToCreate.Time,
+			},
+		)
+		if err != nil {
+			lh.lifecycleErrorResponse(w, r, err)
+			return
+		}
+		if err := response(j{"order": state}, http.StatusCreated, w); err != nil {
+			logError(lh.logger, r, err)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	mock_service "github.com/plinkplenk/test-vortex/internal/orders/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	invalidOrderIDResponse, _     = json.Marshal(j{"error": ErrInvalidOrderID.Error()})
+	invalidSideResponse, _        = json.Marshal(j{"error": validators.ErrInvalidSide.Error()})
+	invalidEventStatusResponse, _ = json.Marshal(j{"error": validators.ErrInvalidEventStatus.Error()})
+	invalidQtyResponse, _         = json.Marshal(j{"error": validators.ErrInvalidQty.Error()})
+	orderNotFoundResponse, _      = json.Marshal(j{"error": orders.ErrOrderNotFound.Error()})
+	invalidTransitionResponse, _  = json.Marshal(j{"error": orders.ErrInvalidTransition.Error()})
+	overfillResponse, _           = json.Marshal(j{"error": orders.ErrOverfill.Error()})
+	orderExistsResponse, _        = json.Marshal(j{"error": orders.ErrOrderExists.Error()})
+)
+
+var (
+	lifecycleOrderID = uuid.MustParse("6f1c2a1e-9a4e-4c4e-8f57-6c3b0d8f2a11")
+	lifecycleClient  = orders.Client{ClientName: "client", ExchangeName: "exchange", Label: "label", Pair: "A_B"}
+)
+
+func TestLifecycleHandler_CreateOrder(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLifecycleService)
+	testTable := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "SUCCESS",
+			inputBody: `{
+				"client": {"clientName": "client", "exchangeName": "exchange", "label": "label", "pair": "A_B"},
+				"side": "buy",
+				"type": "limit",
+				"baseQty": 2,
+				"price": 10
+			}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().CreateOrder(
+					context.Background(),
+					orders.Order{Client: lifecycleClient, Side: "buy", Type: "limit", BaseQty: 2, Price: 10},
+				).Return(lifecycleOrderID, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedBody:       `{"orderId":"6f1c2a1e-9a4e-4c4e-8f57-6c3b0d8f2a11"}`,
+		},
+		{
+			name: "ORDER EXISTS",
+			inputBody: `{
+				"orderId": "6f1c2a1e-9a4e-4c4e-8f57-6c3b0d8f2a11",
+				"client": {"clientName": "client", "exchangeName": "exchange", "label": "label", "pair": "A_B"},
+				"side": "buy",
+				"type": "limit",
+				"baseQty": 2,
+				"price": 10
+			}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().CreateOrder(
+					context.Background(),
+					orders.Order{
+						ID:      lifecycleOrderID,
+						Client:  lifecycleClient,
+						Side:    "buy",
+						Type:    "limit",
+						BaseQty: 2,
+						Price:   10,
+					},
+				).Return(uuid.Nil, orders.ErrOrderExists)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       string(orderExistsResponse),
+		},
+		{
+			name: "INVALID INPUT (NO SIDE)",
+			inputBody: `{
+				"client": {"clientName": "client", "exchangeName": "exchange", "label": "label", "pair": "A_B"},
+				"baseQty": 2
+			}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidSideResponse),
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				lifecycleService := mock_service.NewMockLifecycleService(c)
+				test.mockBehavior(lifecycleService)
+
+				handler := NewLifecycleHandler(lifecycleService, loggerStub)
+				router := chi.NewRouter()
+				router.Post("/lifecycle", handler.CreateOrder(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/lifecycle", bytes.NewBufferString(test.inputBody))
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodPost, "/orders/lifecycle", w)
+			},
+		)
+	}
+}
+
+func TestLifecycleHandler_GetOrderState(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	type mockBehavior func(s *mock_service.MockLifecycleService)
+	testTable := []struct {
+		name               string
+		orderID            string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:    "SUCCESS",
+			orderID: lifecycleOrderID.String(),
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().GetOrderState(context.Background(), lifecycleOrderID).Return(
+					orders.ComputeState(
+						orders.Order{
+							ID:          lifecycleOrderID,
+							Client:      lifecycleClient,
+							Side:        "buy",
+							Type:        "limit",
+							BaseQty:     2,
+							Price:       10,
+							TimeCreated: created,
+						},
+						nil,
+						nil,
+					),
+					nil,
+				)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"order":{"orderId":"6f1c2a1e-9a4e-4c4e-8f57-6c3b0d8f2a11","clientName":"client","exchangeName":"exchange","label":"label","pair":"A_B","side":"buy","type":"limit","baseQty":2,"price":10,"timeCreated":"2024-01-01T00:00:00Z","status":"new","filledQty":0,"avgFillPrice":0,"commissionQuoteQty":0,"timeUpdated":"2024-01-01T00:00:00Z","fills":[],"events":[]}}`,
+		},
+		{
+			name:    "NOT FOUND",
+			orderID: lifecycleOrderID.String(),
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().GetOrderState(context.Background(), lifecycleOrderID).Return(
+					orders.OrderState{},
+					orders.ErrOrderNotFound,
+				)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       string(orderNotFoundResponse),
+		},
+		{
+			name:    "INVALID INPUT (BAD ORDER ID)",
+			orderID: "not-a-uuid",
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().GetOrderState(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidOrderIDResponse),
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				lifecycleService := mock_service.NewMockLifecycleService(c)
+				test.mockBehavior(lifecycleService)
+
+				handler := NewLifecycleHandler(lifecycleService, loggerStub)
+				router := chi.NewRouter()
+				router.Get("/lifecycle/{order_id}", handler.GetOrderState(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/lifecycle/"+test.orderID, nil)
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/orders/lifecycle/{order_id}", w)
+			},
+		)
+	}
+}
+
+func TestLifecycleHandler_AppendEvent(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLifecycleService)
+	testTable := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:      "INVALID TRANSITION",
+			inputBody: `{"status": "cancelled", "reason": "user request"}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().AppendEvent(
+					context.Background(),
+					orders.Event{OrderID: lifecycleOrderID, Status: orders.StatusCancelled, Reason: "user request"},
+				).Return(orders.OrderState{}, orders.ErrInvalidTransition)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       string(invalidTransitionResponse),
+		},
+		{
+			name:      "INVALID INPUT (FILL STATUS)",
+			inputBody: `{"status": "filled"}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidEventStatusResponse),
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				lifecycleService := mock_service.NewMockLifecycleService(c)
+				test.mockBehavior(lifecycleService)
+
+				handler := NewLifecycleHandler(lifecycleService, loggerStub)
+				router := chi.NewRouter()
+				router.Post("/lifecycle/{order_id}/events", handler.AppendEvent(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPost,
+					"/lifecycle/"+lifecycleOrderID.String()+"/events",
+					bytes.NewBufferString(test.inputBody),
+				)
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodPost, "/orders/lifecycle/{order_id}/events", w)
+			},
+		)
+	}
+}
+
+func TestLifecycleHandler_AppendFill(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockLifecycleService)
+	testTable := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:      "OVERFILL",
+			inputBody: `{"baseQty": 3, "price": 10}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().AppendFill(
+					context.Background(),
+					orders.Fill{OrderID: lifecycleOrderID, BaseQty: 3, Price: 10},
+				).Return(orders.OrderState{}, orders.ErrOverfill)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedBody:       string(overfillResponse),
+		},
+		{
+			name:      "INVALID INPUT (NO QTY)",
+			inputBody: `{"price": 10}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().AppendFill(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidQtyResponse),
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				lifecycleService := mock_service.NewMockLifecycleService(c)
+				test.mockBehavior(lifecycleService)
+
+				handler := NewLifecycleHandler(lifecycleService, loggerStub)
+				router := chi.NewRouter()
+				router.Post("/lifecycle/{order_id}/fills", handler.AppendFill(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(
+					http.MethodPost,
+					"/lifecycle/"+lifecycleOrderID.String()+"/fills",
+					bytes.NewBufferString(test.inputBody),
+				)
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodPost, "/orders/lifecycle/{order_id}/fills", w)
+			},
+		)
+	}
+}
+
+func TestLifecycleHandler_ClientScope(t *testing.T) {
+	identity := auth.Identity{Name: "desk", Clients: []string{"other-client"}, Exchanges: []string{auth.Wildcard}}
+	forbiddenResponse, _ := json.Marshal(j{"error": auth.ErrForbidden.Error()})
+	order := orders.Order{ID: lifecycleOrderID, Client: lifecycleClient, Side: "buy", Type: "limit", BaseQty: 2}
+	type mockBehavior func(s *mock_service.MockLifecycleService)
+	testTable := []struct {
+		name               string
+		method             string
+		path               string
+		pattern            string
+		body               string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:    "CREATE ORDER",
+			method:  http.MethodPost,
+			path:    "/orders/lifecycle",
+			pattern: "/orders/lifecycle",
+			body: `{
+				"client": {"clientName": "client", "exchangeName": "exchange", "label": "label", "pair": "A_B"},
+				"side": "buy",
+				"type": "limit",
+				"baseQty": 2
+			}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().CreateOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       string(forbiddenResponse),
+		},
+		{
+			name:    "GET ORDER STATE",
+			method:  http.MethodGet,
+			path:    "/orders/lifecycle/" + lifecycleOrderID.String(),
+			pattern: "/orders/lifecycle/{order_id}",
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().GetOrderState(gomock.Any(), lifecycleOrderID).Return(orders.ComputeState(order, nil, nil), nil)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       string(orderNotFoundResponse),
+		},
+		{
+			name:    "APPEND EVENT",
+			method:  http.MethodPost,
+			path:    "/orders/lifecycle/" + lifecycleOrderID.String() + "/events",
+			pattern: "/orders/lifecycle/{order_id}/events",
+			body:    `{"status": "cancelled"}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().GetOrder(gomock.Any(), lifecycleOrderID).Return(order, nil)
+				s.EXPECT().AppendEvent(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       string(orderNotFoundResponse),
+		},
+		{
+			name:    "APPEND FILL",
+			method:  http.MethodPost,
+			path:    "/orders/lifecycle/" + lifecycleOrderID.String() + "/fills",
+			pattern: "/orders/lifecycle/{order_id}/fills",
+			body:    `{"baseQty": 1, "price": 10}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().GetOrder(gomock.Any(), lifecycleOrderID).Return(order, nil)
+				s.EXPECT().AppendFill(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       string(orderNotFoundResponse),
+		},
+		{
+			name:    "APPEND FILL (NOT FOUND)",
+			method:  http.MethodPost,
+			path:    "/orders/lifecycle/" + lifecycleOrderID.String() + "/fills",
+			pattern: "/orders/lifecycle/{order_id}/fills",
+			body:    `{"baseQty": 1, "price": 10}`,
+			mockBehavior: func(s *mock_service.MockLifecycleService) {
+				s.EXPECT().GetOrder(gomock.Any(), lifecycleOrderID).Return(orders.Order{}, orders.ErrOrderNotFound)
+				s.EXPECT().AppendFill(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       string(orderNotFoundResponse),
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				lifecycleService := mock_service.NewMockLifecycleService(c)
+				test.mockBehavior(lifecycleService)
+
+				handler := NewLifecycleHandler(lifecycleService, loggerStub)
+				router := chi.NewRouter()
+				router.Post("/orders/lifecycle", handler.CreateOrder(context.Background()))
+				router.Get("/orders/lifecycle/{order_id}", handler.GetOrderState(context.Background()))
+				router.Post("/orders/lifecycle/{order_id}/events", handler.AppendEvent(context.Background()))
+				router.Post("/orders/lifecycle/{order_id}/fills", handler.AppendFill(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(test.method, test.path, bytes.NewBufferString(test.body))
+				r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, test.method, test.pattern, w)
+			},
+		)
+	}
+}
+
+func TestLifecycleHandler_ClientScopeAllowed(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	order := orders.Order{ID: lifecycleOrderID, Client: lifecycleClient, Side: "buy", Type: "limit", BaseQty: 2}
+	fill := orders.Fill{OrderID: lifecycleOrderID, BaseQty: 1, Price: 10}
+	lifecycleService := mock_service.NewMockLifecycleService(c)
+	lifecycleService.EXPECT().GetOrder(gomock.Any(), lifecycleOrderID).Return(order, nil)
+	lifecycleService.EXPECT().AppendFill(gomock.Any(), fill).Return(
+		orders.ComputeState(order, nil, []orders.Fill{fill}), nil,
+	)
+
+	handler := NewLifecycleHandler(lifecycleService, loggerStub)
+	router := chi.NewRouter()
+	router.Post("/lifecycle/{order_id}/fills", handler.AppendFill(context.Background()))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(
+		http.MethodPost,
+		"/lifecycle/"+lifecycleOrderID.String()+"/fills",
+		bytes.NewBufferString(`{"baseQty": 1, "price": 10}`),
+	)
+	identity := auth.Identity{Name: "desk", Clients: []string{"client"}, Exchanges: []string{"exchange"}}
+	r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assertDocumented(t, http.MethodPost, "/orders/lifecycle/{order_id}/fills", w)
+}
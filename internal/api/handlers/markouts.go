@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/api/schemas"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/markouts"
+	markoutService "github.com/plinkplenk/test-vortex/internal/markouts/service"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// defaultMarkoutsWindow is used to compute "from" when it is not provided
+const defaultMarkoutsWindow = 24 * time.Hour
+
+type MarkoutsHandler struct {
+	markoutService markoutService.MarkoutsService
+	logger         *slog.Logger
+	now            func() time.Time
+}
+
+func NewMarkoutsHandler(markoutService markoutService.MarkoutsService, logger *slog.Logger) *MarkoutsHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &MarkoutsHandler{markoutService: markoutService, logger: logger, now: time.Now}
+}
+
+func (mh *MarkoutsHandler) parseMarkoutsQuery(r *http.Request) (markouts.Query, error) {
+	params := r.URL.Query()
+	query := markouts.Query{
+		Algorithm: params.Get("algorithm"),
+		Label:     params.Get("label"),
+		Pair:      params.Get("pair"),
+	}
+	to, err := parseTimeParam(r, "to", mh.now().UTC())
+	if err != nil {
+		return query, err
+	}
+	query.To = to
+	from, err := parseTimeParam(r, "from", query.To.Add(-defaultMarkoutsWindow))
+	if err != nil {
+		return query, err
+	}
+	query.From = from
+	return query, nil
+}
+
+func (mh *MarkoutsHandler) GetMarkouts(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		query, err := mh.parseMarkoutsQuery(r)
+		if err == nil {
+			err = validators.ValidateMarkoutsQuery(query)
+		}
+		if err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+				logError(mh.logger, r, err)
+			}
+			return
+		}
+		result, err := mh.markoutService.GetMarkouts(ctx, query)
+		if err != nil {
+			mh.logger.DebugContext(
+				r.Context(),
+				"error while trying to get markouts",
+				"query", query,
+				"error", err,
+			)
+			if err := response(j{"error": "something went wrong"}, http.StatusInternalServerError, w); err != nil {
+				logError(mh.logger, r, err)
+			}
+			return
+		}
+		if result == nil {
+			result = []markouts.Markout{}
+		}
+		if err := response(j{"markouts": result}, http.StatusOK, w); err != nil {
+			logError(mh.logger, r, err)
+		}
+	}
+}
+
+// Compute computes markouts of orders placed in the requested window, it is used for backfills
+func (mh *MarkoutsHandler) Compute(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			logError(mh.logger, r, err)
+			return
+		}
+		var markoutsToCompute schemas.MarkoutsCompute
+		if err := decode(r, body, &markoutsToCompute); err != nil {
+			if err := response(
+				j{"error": ErrInvalidTime.Error()},
+				http.StatusBadRequest,
+				w,
+			); err != nil {
+				logError(mh.logger, r, err)
+			}
+			return
+		}
+		if err := validators.ValidateMarkoutsCompute(markoutsToCompute); err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+				logError(mh.logger, r, err)
+			}
+			return
+		}
+		if err := mh.markoutService.Compute(ctx, markoutsToCompute.From, markoutsToCompute.To); err != nil {
+			mh.logger.DebugContext(
+				r.Context(),
+				"error while trying to compute markouts",
+				"from", markoutsToCompute.From,
+				"to", markoutsToCompute.To,
+				"error", err,
+			)
+			if err := response(j{"error": "something went wrong"}, http.StatusInternalServerError, w); err != nil {
+				logError(mh.logger, r, err)
+			}
+			return
+		}
+		if err := response(nil, http.StatusCreated, w); err != nil {
+			logError(mh.logger, r, err)
+		}
+	}
+}
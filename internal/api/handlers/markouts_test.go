@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/markouts"
+	mock_service "github.com/plinkplenk/test-vortex/internal/markouts/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMarkoutsHandler_GetMarkouts(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	type mockBehavior func(s *mock_service.MockMarkoutsService)
+	testTable := []struct {
+		name               string
+		params             string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "SUCCESS",
+			params: "?algorithm=algo&pair=A_B",
+			mockBehavior: func(s *mock_service.MockMarkoutsService) {
+				s.EXPECT().GetMarkouts(
+					context.Background(),
+					markouts.Query{From: now.Add(-defaultMarkoutsWindow), To: now, Algorithm: "algo", Pair: "A_B"},
+				).Return(
+					[]markouts.Markout{
+						{
+							Algorithm:      "algo",
+							Label:          "label",
+							Pair:           "A_B",
+							HorizonSeconds: 10,
+							Orders:         2,
+							BaseVolume:     3,
+							AvgMarkoutBps:  -1.5,
+							MarkoutQuote:   -0.2,
+						},
+					},
+					nil,
+				)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"markouts":[{"algorithm":"algo","label":"label","pair":"A_B","horizonSeconds":10,"orders":2,"baseVolume":3,"avgMarkoutBps":-1.5,"markoutQuote":-0.2}]}`,
+		},
+		{
+			name:   "INVALID INPUT (FROM AFTER TO)",
+			params: "?from=2024-01-03T00:00:00Z",
+			mockBehavior: func(s *mock_service.MockMarkoutsService) {
+				s.EXPECT().GetMarkouts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidTimeRangeResponse),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				markoutService := mock_service.NewMockMarkoutsService(c)
+				test.mockBehavior(markoutService)
+
+				handler := NewMarkoutsHandler(markoutService, loggerStub)
+				handler.now = func() time.Time { return now }
+				router := chi.NewRouter()
+				router.Get("/markouts", handler.GetMarkouts(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/markouts"+test.params, nil)
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/markouts", w)
+			},
+		)
+	}
+}
+
+func TestMarkoutsHandler_Compute(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	type mockBehavior func(s *mock_service.MockMarkoutsService)
+	testTable := []struct {
+		name               string
+		inputBody          string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:      "SUCCESS",
+			inputBody: `{"from": "2024-01-01T00:00:00Z", "to": "2024-01-02T00:00:00Z"}`,
+			mockBehavior: func(s *mock_service.MockMarkoutsService) {
+				s.EXPECT().Compute(context.Background(), from, to).Return(nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedBody:       "",
+		},
+		{
+			name:      "SERVICE ERROR",
+			inputBody: `{"from": "2024-01-01T00:00:00Z", "to": "2024-01-02T00:00:00Z"}`,
+			mockBehavior: func(s *mock_service.MockMarkoutsService) {
+				s.EXPECT().Compute(context.Background(), from, to).Return(errors.New("db is down"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"error":"something went wrong"}`,
+		},
+		{
+			name:      "INVALID INPUT (BAD TIME)",
+			inputBody: `{"from": "yesterday"}`,
+			mockBehavior: func(s *mock_service.MockMarkoutsService) {
+				s.EXPECT().Compute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidTimeResponse),
+		},
+		{
+			name:      "INVALID INPUT (NO TIME RANGE)",
+			inputBody: `{}`,
+			mockBehavior: func(s *mock_service.MockMarkoutsService) {
+				s.EXPECT().Compute(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidTimeRangeResponse),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				markoutService := mock_service.NewMockMarkoutsService(c)
+				test.mockBehavior(markoutService)
+
+				handler := NewMarkoutsHandler(markoutService, loggerStub)
+				router := chi.NewRouter()
+				router.Post("/markouts/compute", handler.Compute(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/markouts/compute", bytes.NewBufferString(test.inputBody))
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodPost, "/markouts/compute", w)
+			},
+		)
+	}
+}
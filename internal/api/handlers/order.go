@@ -2,14 +2,17 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc/ordersv1"
 	"github.com/plinkplenk/test-vortex/internal/api/schemas"
 	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
 	"github.com/plinkplenk/test-vortex/internal/orders"
 	order "github.com/plinkplenk/test-vortex/internal/orders/service"
+	"google.golang.org/protobuf/proto"
 	"io"
 	"log/slog"
 	"net/http"
@@ -34,12 +37,14 @@ func NewOrdersHandler(orderService order.OrdersService, logger *slog.Logger) *Or
 
 func (oh *OrdersHandler) GetOrderBook(ctx context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
 		exchangeName := chi.URLParam(r, "exchange_name")
 		pair := chi.URLParam(r, "pair")
 		depthOrders, err := oh.orderService.GetOrderBook(ctx, exchangeName, pair)
 		if len(depthOrders) == 0 {
 			if err != nil {
-				oh.logger.Debug(
+				oh.logger.DebugContext(
+					r.Context(),
 					"error while trying to get order book",
 					"exchangeName", exchangeName,
 					"pair", pair,
@@ -55,7 +60,12 @@ func (oh *OrdersHandler) GetOrderBook(ctx context.Context) http.HandlerFunc {
 			}
 			return
 		}
-		if err := response(j{"depthOrders": depthOrders}, http.StatusOK, w); err != nil {
+		if err := messageResponse(
+			j{"depthOrders": depthOrders},
+			func() proto.Message { return rpc.OrderBookToProto(depthOrders) },
+			http.StatusOK,
+			w,
+		); err != nil {
 			logError(oh.logger, r, err)
 			return
 		}
@@ -64,13 +74,17 @@ func (oh *OrdersHandler) GetOrderBook(ctx context.Context) http.HandlerFunc {
 
 func (oh *OrdersHandler) SaveOrderBook(ctx context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			logError(oh.logger, r, err)
 			return
 		}
 		var orderToCreate schemas.OrderBookCreate
-		if err := json.Unmarshal(body, &orderToCreate); err != nil {
+		if err := decodeMessage(
+			r, body, &orderToCreate, &ordersv1.SaveOrderBookRequest{},
+			func(request *ordersv1.SaveOrderBookRequest) { orderToCreate = rpc.OrderBookFromProto(request) },
+		); err != nil {
 			logError(oh.logger, r, err)
 			if err := response(
 				j{"error": "You must provide correct exchange name pair"},
@@ -97,7 +111,8 @@ func (oh *OrdersHandler) SaveOrderBook(ctx context.Context) http.HandlerFunc {
 			orderToCreate.Pair,
 			orderToCreate.Depth,
 		); err != nil {
-			oh.logger.Debug(
+			oh.logger.DebugContext(
+				r.Context(),
 				"error while trying to save order book",
 				"exchangeName", orderToCreate.ExchangeName,
 				"pair", orderToCreate.Pair,
@@ -117,6 +132,7 @@ func (oh *OrdersHandler) SaveOrderBook(ctx context.Context) http.HandlerFunc {
 }
 func (oh *OrdersHandler) GetOrderHistory(ctx context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
 		clientName := chi.URLParam(r, "client_name")
 		exchangeName := chi.URLParam(r, "exchange_name")
 		label := r.URL.Query().Get("label")
@@ -137,13 +153,20 @@ func (oh *OrdersHandler) GetOrderHistory(ctx context.Context) http.HandlerFunc {
 			Label:        label,
 			Pair:         pair,
 		}
+		if err := auth.Authorize(r.Context(), client.ClientName, client.ExchangeName); err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusForbidden, w); err != nil {
+				logError(oh.logger, r, err)
+			}
+			return
+		}
 		orderHistory, err := oh.orderService.GetOrderHistory(ctx, client)
 		if err != nil {
 			logError(oh.logger, r, err)
 			return
 		}
 		if orderHistory == nil {
-			oh.logger.Debug(
+			oh.logger.DebugContext(
+				r.Context(),
 				"error while trying to get order book",
 				"exchangeName", exchangeName,
 				"pair", pair,
@@ -158,20 +181,29 @@ func (oh *OrdersHandler) GetOrderHistory(ctx context.Context) http.HandlerFunc {
 			}
 			return
 		}
-		if err := response(j{"orderHistory": orderHistory}, http.StatusOK, w); err != nil {
+		if err := messageResponse(
+			j{"orderHistory": orderHistory},
+			func() proto.Message { return rpc.OrderHistoryToProto(orderHistory) },
+			http.StatusOK,
+			w,
+		); err != nil {
 			logError(oh.logger, r, err)
 		}
 	}
 }
 func (oh *OrdersHandler) SaveOrder(ctx context.Context) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
 			logError(oh.logger, r, err)
 			return
 		}
 		var clientHistory schemas.ClientHistoryCreate
-		if err := json.Unmarshal(body, &clientHistory); err != nil {
+		if err := decodeMessage(
+			r, body, &clientHistory, &ordersv1.SaveOrderRequest{},
+			func(request *ordersv1.SaveOrderRequest) { clientHistory = rpc.ClientHistoryFromProto(request) },
+		); err != nil {
 			logError(oh.logger, r, err)
 			if err := response(
 				j{"error": "Client or History not provided"},
@@ -192,9 +224,20 @@ func (oh *OrdersHandler) SaveOrder(ctx context.Context) http.HandlerFunc {
 			}
 			return
 		}
+		if err := auth.Authorize(
+			r.Context(),
+			clientHistory.Client.ClientName,
+			clientHistory.Client.ExchangeName,
+		); err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusForbidden, w); err != nil {
+				logError(oh.logger, r, err)
+			}
+			return
+		}
 
 		if err := oh.orderService.SaveOrder(ctx, clientHistory.Client, &clientHistory.OrderHistory); err != nil {
-			oh.logger.Debug(
+			oh.logger.DebugContext(
+				r.Context(),
 				"error while trying to save order book",
 				"client", clientHistory.Client,
 				"orderHistory", clientHistory.OrderHistory,
@@ -6,12 +6,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/codec"
+	"github.com/plinkplenk/test-vortex/internal/api/middleware"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc/ordersv1"
 	"github.com/plinkplenk/test-vortex/internal/api/schemas"
 	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
 	"github.com/plinkplenk/test-vortex/internal/orders"
 	mock_service "github.com/plinkplenk/test-vortex/internal/orders/service/mocks"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 	"io"
 	"log/slog"
 	"net/http"
@@ -150,6 +157,7 @@ func TestOrdersHandler_GetOrderHistory(t *testing.T) {
 
 				assert.Equal(t, test.expectedStatusCode, w.Code)
 				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/orders/history/{client_name}/{exchange_name}", w)
 			},
 		)
 	}
@@ -214,6 +222,7 @@ func TestOrdersHandler_GetOrderBook(t *testing.T) {
 
 				assert.Equal(t, test.expectedStatusCode, w.Code)
 				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/orders/{exchange_name}/{pair}", w)
 			},
 		)
 	}
@@ -319,6 +328,7 @@ func TestOrdersHandler_SaveOrderBook(t *testing.T) {
 				router.ServeHTTP(w, r)
 				assert.Equal(t, test.expectedStatusCode, w.Code)
 				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodPost, "/orders", w)
 
 			},
 		)
@@ -459,7 +469,229 @@ func TestOrdersHandler_SaveOrder(t *testing.T) {
 
 				assert.Equal(t, test.expectedStatusCode, w.Code)
 				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodPost, "/orders/history", w)
 			},
 		)
 	}
 }
+
+func TestOrdersHandler_ClientScope(t *testing.T) {
+	identity := auth.Identity{Name: "desk", Clients: []string{"other-client"}, Exchanges: []string{auth.Wildcard}}
+	forbiddenResponse, _ := json.Marshal(j{"error": auth.ErrForbidden.Error()})
+	testTable := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{
+			name:   "GET HISTORY",
+			method: http.MethodGet,
+			path:   "/history/client/exchange?label=label&pair=A_B",
+		},
+		{
+			name:   "SAVE ORDER",
+			method: http.MethodPost,
+			path:   "/history",
+			body: `{
+				"client": {"clientName": "client", "exchangeName": "exchange", "label": "label", "pair": "A_B"},
+				"orderHistory": {"side": "buy", "baseQty": 1, "price": 0.1}
+			}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				orderService := mock_service.NewMockOrdersService(c)
+				orderService.EXPECT().GetOrderHistory(gomock.Any(), gomock.Any()).Times(0)
+				orderService.EXPECT().SaveOrder(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				handler := NewOrdersHandler(orderService, loggerStub)
+				router := chi.NewRouter()
+				router.Get("/history/{client_name}/{exchange_name}", handler.GetOrderHistory(context.Background()))
+				router.Post("/history", handler.SaveOrder(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(test.method, test.path, bytes.NewBufferString(test.body))
+				r = r.WithContext(auth.WithIdentity(r.Context(), identity))
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				assert.Equal(t, string(forbiddenResponse), w.Body.String())
+				pattern := "/orders/history"
+				if test.method == http.MethodGet {
+					pattern = "/orders/history/{client_name}/{exchange_name}"
+				}
+				assertDocumented(t, test.method, pattern, w)
+			},
+		)
+	}
+}
+
+func TestOrdersHandler_Codecs(t *testing.T) {
+	depth := []orders.Depth{{Price: 0.5, BaseQty: 1}}
+	for _, c := range []codec.Codec{codec.JSON{}, codec.MessagePack{}} {
+		t.Run(
+			c.ContentType(), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				orderService := mock_service.NewMockOrdersService(ctrl)
+				orderService.EXPECT().SaveOrderBook(gomock.Any(), "some-exchange", "A_B", depth).Return(nil)
+				orderService.EXPECT().GetOrderBook(gomock.Any(), "some-exchange", "A_B").Return(depth, nil)
+
+				handler := NewOrdersHandler(orderService, loggerStub)
+				router := chi.NewRouter()
+				router.Use(middleware.Negotiate)
+				router.Post("/", handler.SaveOrderBook(context.Background()))
+				router.Get("/{exchange_name}/{pair}", handler.GetOrderBook(context.Background()))
+
+				body, err := c.Marshal(
+					schemas.OrderBookCreate{ExchangeName: "some-exchange", Pair: "A_B", Depth: depth},
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+				r.Header.Set("Content-Type", c.ContentType())
+				r.Header.Set("Accept", c.ContentType())
+				router.ServeHTTP(w, r)
+				assert.Equal(t, http.StatusCreated, w.Code)
+				assert.Empty(t, w.Header().Get("Content-Type"), "responses without body have no content type")
+				assertDocumented(t, http.MethodPost, "/orders", w)
+
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(http.MethodGet, "/some-exchange/A_B", nil)
+				r.Header.Set("Accept", c.ContentType())
+				router.ServeHTTP(w, r)
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, c.ContentType(), w.Header().Get("Content-Type"))
+				var orderBook struct {
+					DepthOrders []orders.Depth `json:"depthOrders"`
+				}
+				assert.NoError(t, c.Unmarshal(w.Body.Bytes(), &orderBook))
+				assert.Equal(t, depth, orderBook.DepthOrders)
+				assertDocumented(t, http.MethodGet, "/orders/{exchange_name}/{pair}", w)
+
+				w = httptest.NewRecorder()
+				r = httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString("not a body"))
+				r.Header.Set("Content-Type", c.ContentType())
+				r.Header.Set("Accept", c.ContentType())
+				router.ServeHTTP(w, r)
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				var errorBody map[string]any
+				assert.NoError(t, c.Unmarshal(w.Body.Bytes(), &errorBody), "errors are encoded by the codec")
+				assert.Contains(t, errorBody, "error")
+				assertDocumented(t, http.MethodPost, "/orders", w)
+			},
+		)
+	}
+}
+
+func TestOrdersHandler_Protobuf(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	depth := []orders.Depth{{Price: 0.5, BaseQty: 1}}
+	client := orders.Client{ClientName: "client", ExchangeName: "some-exchange", Label: "label", Pair: "A_B"}
+	history := orders.History{
+		Client:     client,
+		Side:       "buy",
+		Type:       "limit",
+		BaseQty:    1,
+		Price:      0.5,
+		TimePlaced: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	orderService := mock_service.NewMockOrdersService(ctrl)
+	orderService.EXPECT().SaveOrderBook(gomock.Any(), "some-exchange", "A_B", depth).Return(nil)
+	orderService.EXPECT().GetOrderBook(gomock.Any(), "some-exchange", "A_B").Return(depth, nil)
+	orderService.EXPECT().GetOrderBook(gomock.Any(), "some-exchange", "B_C").Return(nil, nil)
+	orderService.EXPECT().SaveOrder(gomock.Any(), client, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ orders.Client, saved *orders.History) error {
+			assert.Equal(t, history.Price, saved.Price)
+			assert.True(t, history.TimePlaced.Equal(saved.TimePlaced))
+			return nil
+		},
+	)
+	orderService.EXPECT().GetOrderHistory(gomock.Any(), client).Return([]*orders.History{&history}, nil)
+
+	handler := NewOrdersHandler(orderService, loggerStub)
+	router := chi.NewRouter()
+	router.Use(middleware.Negotiate)
+	router.Post("/", handler.SaveOrderBook(context.Background()))
+	router.Get("/{exchange_name}/{pair}", handler.GetOrderBook(context.Background()))
+	router.Post("/history", handler.SaveOrder(context.Background()))
+	router.Get("/history/{client_name}/{exchange_name}", handler.GetOrderHistory(context.Background()))
+	serve := func(method, target string, message proto.Message) *httptest.ResponseRecorder {
+		var body []byte
+		if message != nil {
+			var err error
+			if body, err = proto.Marshal(message); err != nil {
+				t.Fatal(err)
+			}
+		}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(method, target, bytes.NewReader(body))
+		r.Header.Set("Content-Type", codec.ProtobufType)
+		r.Header.Set("Accept", codec.ProtobufType)
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(
+		http.MethodPost,
+		"/",
+		&ordersv1.SaveOrderBookRequest{
+			ExchangeName: "some-exchange",
+			Pair:         "A_B",
+			Depth:        []*ordersv1.Depth{{Price: 0.5, BaseQty: 1}},
+		},
+	)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assertDocumented(t, http.MethodPost, "/orders", w)
+
+	w = serve(http.MethodGet, "/some-exchange/A_B", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, codec.ProtobufType, w.Header().Get("Content-Type"))
+	var orderBook ordersv1.GetOrderBookResponse
+	assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), &orderBook))
+	assert.True(t, proto.Equal(rpc.OrderBookToProto(depth), &orderBook))
+	assertDocumented(t, http.MethodGet, "/orders/{exchange_name}/{pair}", w)
+
+	w = serve(http.MethodGet, "/some-exchange/B_C", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, codec.JSONType, w.Header().Get("Content-Type"), "errors have no message type")
+	assertDocumented(t, http.MethodGet, "/orders/{exchange_name}/{pair}", w)
+
+	w = serve(
+		http.MethodPost,
+		"/history",
+		&ordersv1.SaveOrderRequest{
+			Client: &ordersv1.Client{ClientName: "client", ExchangeName: "some-exchange", Label: "label", Pair: "A_B"},
+			OrderHistory: &ordersv1.History{
+				Side:       "buy",
+				Type:       "limit",
+				BaseQty:    1,
+				Price:      0.5,
+				TimePlaced: timestamppb.New(history.TimePlaced),
+			},
+		},
+	)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assertDocumented(t, http.MethodPost, "/orders/history", w)
+
+	w = serve(http.MethodGet, "/history/client/some-exchange?label=label&pair=A_B", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var orderHistory ordersv1.GetOrderHistoryResponse
+	assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), &orderHistory))
+	assert.True(t, proto.Equal(rpc.OrderHistoryToProto([]*orders.History{&history}), &orderHistory))
+	assertDocumented(t, http.MethodGet, "/orders/history/{client_name}/{exchange_name}", w)
+
+	w = serve(http.MethodPost, "/", &ordersv1.SaveOrderBookRequest{Pair: "A_B"})
+	assert.Equal(t, http.StatusBadRequest, w.Code, "messages are validated as json bodies")
+	assertDocumented(t, http.MethodPost, "/orders", w)
+}
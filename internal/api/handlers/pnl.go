@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/plinkplenk/test-vortex/internal/pnl"
+	pnlService "github.com/plinkplenk/test-vortex/internal/pnl/service"
+	"log/slog"
+	"net/http"
+)
+
+type PnLHandler struct {
+	pnlService pnlService.PnLService
+	logger     *slog.Logger
+}
+
+func NewPnLHandler(pnlService pnlService.PnLService, logger *slog.Logger) *PnLHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PnLHandler{pnlService: pnlService, logger: logger}
+}
+
+func (ph *PnLHandler) GetPosition(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		label := r.URL.Query().Get("label")
+		pair := r.URL.Query().Get("pair")
+		if label == "" || pair == "" {
+			if err := response(
+				j{"error": ErrLabelOrPairNotProvided.Error()},
+				http.StatusBadRequest,
+				w,
+			); err != nil {
+				logError(ph.logger, r, err)
+			}
+			return
+		}
+		method := pnl.MethodFIFO
+		if m := r.URL.Query().Get("method"); m != "" {
+			parsed, err := pnl.ParseMethod(m)
+			if err != nil {
+				if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+					logError(ph.logger, r, err)
+				}
+				return
+			}
+			method = parsed
+		}
+		client := orders.Client{
+			ClientName:   chi.URLParam(r, "client_name"),
+			ExchangeName: chi.URLParam(r, "exchange_name"),
+			Label:        label,
+			Pair:         pair,
+		}
+		if err := auth.Authorize(r.Context(), client.ClientName, client.ExchangeName); err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusForbidden, w); err != nil {
+				logError(ph.logger, r, err)
+			}
+			return
+		}
+		position, err := ph.pnlService.GetPosition(ctx, client, method)
+		if err != nil {
+			ph.logger.DebugContext(
+				r.Context(),
+				"error while trying to compute position",
+				"client", client,
+				"method", method,
+				"error", err,
+			)
+			if err := response(j{"error": "something went wrong"}, http.StatusInternalServerError, w); err != nil {
+				logError(ph.logger, r, err)
+			}
+			return
+		}
+		if err := response(j{"position": position}, http.StatusOK, w); err != nil {
+			logError(ph.logger, r, err)
+		}
+	}
+}
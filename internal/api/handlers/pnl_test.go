@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/plinkplenk/test-vortex/internal/pnl"
+	mock_service "github.com/plinkplenk/test-vortex/internal/pnl/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var unknownMethodResponse, _ = json.Marshal(j{"error": pnl.ErrUnknownMethod.Error()})
+
+func TestPnLHandler_GetPosition(t *testing.T) {
+	client := orders.Client{ClientName: "client", ExchangeName: "exchange", Label: "label", Pair: "A_B"}
+	type mockBehavior func(s *mock_service.MockPnLService)
+	testTable := []struct {
+		name               string
+		params             string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "SUCCESS",
+			params: "?label=label&pair=A_B&method=average",
+			mockBehavior: func(s *mock_service.MockPnLService) {
+				s.EXPECT().GetPosition(context.Background(), client, pnl.MethodAverageCost).Return(
+					pnl.Position{
+						Client:        client,
+						Method:        pnl.MethodAverageCost,
+						NetQty:        1,
+						AvgEntryPrice: 10,
+						RealizedPnL:   5,
+						UnrealizedPnL: 2,
+						MarkPrice:     12,
+						Fees:          1,
+						NetPnL:        6,
+						Trades:        3,
+					},
+					nil,
+				)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"position":{"clientName":"client","exchangeName":"exchange","label":"label","pair":"A_B","method":"average","netQty":1,"avgEntryPrice":10,"realizedPnl":5,"unrealizedPnl":2,"markPrice":12,"fees":1,"netPnl":6,"trades":3,"ignoredOrders":0}}`,
+		},
+		{
+			name:   "DEFAULT METHOD",
+			params: "?label=label&pair=A_B",
+			mockBehavior: func(s *mock_service.MockPnLService) {
+				s.EXPECT().GetPosition(context.Background(), client, pnl.MethodFIFO).Return(
+					pnl.Position{Client: client, Method: pnl.MethodFIFO},
+					nil,
+				)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"position":{"clientName":"client","exchangeName":"exchange","label":"label","pair":"A_B","method":"fifo","netQty":0,"avgEntryPrice":0,"realizedPnl":0,"unrealizedPnl":0,"markPrice":0,"fees":0,"netPnl":0,"trades":0,"ignoredOrders":0}}`,
+		},
+		{
+			name:   "INVALID INPUT (UNKNOWN METHOD)",
+			params: "?label=label&pair=A_B&method=lifo",
+			mockBehavior: func(s *mock_service.MockPnLService) {
+				s.EXPECT().GetPosition(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(unknownMethodResponse),
+		},
+		{
+			name:   "INVALID INPUT (NO LABEL)",
+			params: "?pair=A_B",
+			mockBehavior: func(s *mock_service.MockPnLService) {
+				s.EXPECT().GetPosition(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(labelOrPairNotProvidedResponse),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				pnlService := mock_service.NewMockPnLService(c)
+				test.mockBehavior(pnlService)
+
+				handler := NewPnLHandler(pnlService, loggerStub)
+				router := chi.NewRouter()
+				router.Get("/{client_name}/{exchange_name}", handler.GetPosition(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/client/exchange"+test.params, nil)
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/pnl/{client_name}/{exchange_name}", w)
+			},
+		)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/replay"
+	replayService "github.com/plinkplenk/test-vortex/internal/replay/service"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// defaultReplayWindow is used to compute "from" when it is not provided
+	defaultReplayWindow = time.Hour
+	ndjsonType          = "application/x-ndjson"
+)
+
+type ReplayHandler struct {
+	replayService replayService.ReplayService
+	logger        *slog.Logger
+	now           func() time.Time
+}
+
+func NewReplayHandler(replayService replayService.ReplayService, logger *slog.Logger) *ReplayHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ReplayHandler{replayService: replayService, logger: logger, now: time.Now}
+}
+
+func (rh *ReplayHandler) parseReplayQuery(r *http.Request) (replay.Query, error) {
+	query := replay.Query{
+		ExchangeName: chi.URLParam(r, "exchange_name"),
+		Pair:         chi.URLParam(r, "pair"),
+		ClientName:   r.URL.Query().Get("client_name"),
+		Speed:        1,
+	}
+	if speed := r.URL.Query().Get("speed"); speed != "" {
+		parsed, err := strconv.ParseFloat(speed, 64)
+		if err != nil {
+			return query, validators.ErrInvalidSpeed
+		}
+		query.Speed = parsed
+	}
+	to, err := parseTimeParam(r, "to", rh.now().UTC())
+	if err != nil {
+		return query, err
+	}
+	query.To = to
+	from, err := parseTimeParam(r, "from", query.To.Add(-defaultReplayWindow))
+	if err != nil {
+		return query, err
+	}
+	query.From = from
+	return query, nil
+}
+
+// Replay streams order book and order events of the pair as JSON lines when they are due at the requested speed.
+// Headers are sent before the first event, so errors after them abort the connection.
+// Replay ends when ctx is done on server shutdown, the stream is aborted as it is not complete
+func (rh *ReplayHandler) Replay(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		query, err := rh.parseReplayQuery(r)
+		if err == nil {
+			err = validators.ValidateReplayQuery(query)
+		}
+		if err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+				logError(rh.logger, r, err)
+			}
+			return
+		}
+		if err := auth.Authorize(r.Context(), query.ClientName, query.ExchangeName); err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusForbidden, w); err != nil {
+				logError(rh.logger, r, err)
+			}
+			return
+		}
+		// replay stops when the client disconnects
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stop := context.AfterFunc(r.Context(), cancel)
+		defer stop()
+
+		w.Header().Set("Content-Type", ndjsonType)
+		w.WriteHeader(http.StatusOK)
+		controller := http.NewResponseController(w)
+		// events are flushed one by one, so flushing unsupported by the writer is not an error
+		_ = controller.Flush()
+		encoder := json.NewEncoder(w)
+		err = rh.replayService.Replay(
+			ctx, query, func(event replay.Event) error {
+				if err := encoder.Encode(event); err != nil {
+					return err
+				}
+				if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+					return err
+				}
+				return nil
+			},
+		)
+		if err != nil && r.Context().Err() == nil {
+			rh.logger.DebugContext(
+				r.Context(),
+				"error while trying to replay events",
+				"query", query,
+				"error", err,
+			)
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
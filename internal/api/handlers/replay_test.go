@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/plinkplenk/test-vortex/internal/replay"
+	mock_service "github.com/plinkplenk/test-vortex/internal/replay/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReplayHandler_Replay(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []replay.Event{
+		{
+			Type: replay.EventBook,
+			Time: from,
+			Book: &replay.Book{Asks: []orders.Depth{{Price: 0.2, BaseQty: 1}}, Bids: []orders.Depth{}},
+		},
+		{
+			Type: replay.EventOrder,
+			Time: from,
+			Order: &orders.History{
+				Client:     orders.Client{ClientName: "client", ExchangeName: "exchange", Label: "label", Pair: "A_B"},
+				Side:       orders.SideBuy,
+				BaseQty:    1,
+				Price:      0.2,
+				TimePlaced: from,
+			},
+		},
+	}
+	invalidSpeedResponse, _ := json.Marshal(j{"error": validators.ErrInvalidSpeed.Error()})
+	forbiddenResponse, _ := json.Marshal(j{"error": auth.ErrForbidden.Error()})
+	type mockBehavior func(s *mock_service.MockReplayService)
+	testTable := []struct {
+		name                string
+		params              string
+		identity            *auth.Identity
+		mockBehavior        mockBehavior
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:   "SUCCESS",
+			params: "?from=2024-01-01T00:00:00Z&to=2024-01-01T01:00:00Z&speed=10&client_name=client",
+			mockBehavior: func(s *mock_service.MockReplayService) {
+				s.EXPECT().Replay(
+					gomock.Any(),
+					replay.Query{
+						ExchangeName: "exchange",
+						Pair:         "A_B",
+						ClientName:   "client",
+						From:         from,
+						To:           from.Add(time.Hour),
+						Speed:        10,
+					},
+					gomock.Any(),
+				).DoAndReturn(
+					func(_ context.Context, _ replay.Query, fn func(replay.Event) error) error {
+						for _, event := range events {
+							if err := fn(event); err != nil {
+								return err
+							}
+						}
+						return nil
+					},
+				)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: ndjsonType,
+			expectedBody: `{"type":"book","time":"2024-01-01T00:00:00Z","book":{"asks":[{"price":0.2,"baseQty":1}],"bids":[]}}` + "\n" +
+				`{"type":"order","time":"2024-01-01T00:00:00Z","order":{"clientName":"client","exchangeName":"exchange","label":"label","pair":"A_B","side":"buy","type":"","baseQty":1,"price":0.2,"algorithmNamePlaced":"","lowestSellPrc":0,"highestBuyPrc":0,"commissionQuoteQty":0,"timePlaced":"2024-01-01T00:00:00Z"}}` + "\n",
+		},
+		{
+			name:   "DEFAULTS",
+			params: "",
+			mockBehavior: func(s *mock_service.MockReplayService) {
+				s.EXPECT().Replay(
+					gomock.Any(),
+					replay.Query{
+						ExchangeName: "exchange",
+						Pair:         "A_B",
+						From:         now.Add(-defaultReplayWindow),
+						To:           now,
+						Speed:        1,
+					},
+					gomock.Any(),
+				).Return(nil)
+			},
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: ndjsonType,
+			expectedBody:        "",
+		},
+		{
+			name:     "FORBIDDEN",
+			params:   "",
+			identity: &auth.Identity{Name: "desk", Clients: []string{"client"}, Exchanges: []string{auth.Wildcard}},
+			mockBehavior: func(s *mock_service.MockReplayService) {
+				s.EXPECT().Replay(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusForbidden,
+			expectedContentType: "application/json",
+			expectedBody:        string(forbiddenResponse),
+		},
+		{
+			name:   "INVALID INPUT (SPEED)",
+			params: "?speed=-1",
+			mockBehavior: func(s *mock_service.MockReplayService) {
+				s.EXPECT().Replay(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        string(invalidSpeedResponse),
+		},
+		{
+			name:   "INVALID INPUT (FROM AFTER TO)",
+			params: "?from=2024-01-03T00:00:00Z",
+			mockBehavior: func(s *mock_service.MockReplayService) {
+				s.EXPECT().Replay(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode:  http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        string(invalidTimeRangeResponse),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				replayService := mock_service.NewMockReplayService(c)
+				test.mockBehavior(replayService)
+
+				handler := NewReplayHandler(replayService, loggerStub)
+				handler.now = func() time.Time { return now }
+				router := chi.NewRouter()
+				router.Get("/replay/{exchange_name}/{pair}", handler.Replay(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/replay/exchange/A_B"+test.params, nil)
+				if test.identity != nil {
+					r = r.WithContext(auth.WithIdentity(r.Context(), *test.identity))
+				}
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/replay/{exchange_name}/{pair}", w)
+			},
+		)
+	}
+}
+
+func TestReplayHandler_ReplayError(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	replayService := mock_service.NewMockReplayService(c)
+	replayService.EXPECT().Replay(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db is down"))
+
+	handler := NewReplayHandler(replayService, loggerStub)
+	router := chi.NewRouter()
+	router.Get("/replay/{exchange_name}/{pair}", handler.Replay(context.Background()))
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/replay/exchange/A_B", nil)
+	assert.PanicsWithValue(
+		t, http.ErrAbortHandler, func() { router.ServeHTTP(w, r) }, "stream is aborted after headers are sent",
+	)
+	assert.True(t, w.Flushed)
+}
+
+func TestReplayHandler_ReplayShutdown(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	started := make(chan struct{})
+	replayService := mock_service.NewMockReplayService(c)
+	replayService.EXPECT().Replay(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ replay.Query, send func(replay.Event) error) error {
+			if err := send(replay.Event{Type: replay.EventBook}); err != nil {
+				return err
+			}
+			close(started)
+			// real time replay waits for events due long after the server is stopped
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	)
+
+	shutdownCtx, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+	handler := NewReplayHandler(replayService, loggerStub)
+	router := chi.NewRouter()
+	router.Get("/replay/{exchange_name}/{pair}", handler.Replay(shutdownCtx))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/replay/exchange/A_B")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	<-started
+
+	shutdown()
+	done := make(chan error)
+	go func() {
+		_, err := io.ReadAll(resp.Body)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		assert.Error(t, err, "stream is aborted, so it is not taken as complete")
+	case <-time.After(5 * time.Second):
+		t.Fatal("replay didn't end on shutdown")
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/tca"
+	tcaService "github.com/plinkplenk/test-vortex/internal/tca/service"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+	// defaultTCAWindow is used to compute "from" when it is not provided
+	defaultTCAWindow = 24 * time.Hour
+)
+
+var ErrUnknownFormat = errors.New("unknown format, use json or csv")
+
+type TCAHandler struct {
+	tcaService tcaService.TCAService
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+func NewTCAHandler(tcaService tcaService.TCAService, logger *slog.Logger) *TCAHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TCAHandler{tcaService: tcaService, logger: logger, now: time.Now}
+}
+
+// responseFormat returns format from "format" query param or from Accept header
+func responseFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case formatJSON, formatCSV:
+		return format, nil
+	case "":
+		if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+			return formatCSV, nil
+		}
+		return formatJSON, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+func (th *TCAHandler) parseTCAQuery(r *http.Request) (tca.Query, error) {
+	query := tca.Query{Algorithm: r.URL.Query().Get("algorithm")}
+	to, err := parseTimeParam(r, "to", th.now().UTC())
+	if err != nil {
+		return query, err
+	}
+	query.To = to
+	from, err := parseTimeParam(r, "from", query.To.Add(-defaultTCAWindow))
+	if err != nil {
+		return query, err
+	}
+	query.From = from
+	return query, nil
+}
+
+func (th *TCAHandler) GetReports(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := requestContext(ctx, r)
+		format, err := responseFormat(r)
+		if err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+				logError(th.logger, r, err)
+			}
+			return
+		}
+		query, err := th.parseTCAQuery(r)
+		if err == nil {
+			err = validators.ValidateTCAQuery(query)
+		}
+		if err != nil {
+			if err := response(j{"error": err.Error()}, http.StatusBadRequest, w); err != nil {
+				logError(th.logger, r, err)
+			}
+			return
+		}
+		reports, err := th.tcaService.GetReports(ctx, query)
+		if err != nil {
+			th.logger.DebugContext(
+				r.Context(),
+				"error while trying to get tca reports",
+				"query", query,
+				"error", err,
+			)
+			if err := response(j{"error": "something went wrong"}, http.StatusInternalServerError, w); err != nil {
+				logError(th.logger, r, err)
+			}
+			return
+		}
+		if format == formatCSV {
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("Content-Disposition", `attachment; filename="tca.csv"`)
+			w.WriteHeader(http.StatusOK)
+			if err := tca.WriteCSV(w, reports); err != nil {
+				logError(th.logger, r, err)
+			}
+			return
+		}
+		if reports == nil {
+			reports = []tca.Report{}
+		}
+		if err := response(
+			j{"from": query.From, "to": query.To, "reports": reports},
+			http.StatusOK,
+			w,
+		); err != nil {
+			logError(th.logger, r, err)
+		}
+	}
+}
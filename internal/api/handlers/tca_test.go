@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/tca"
+	mock_service "github.com/plinkplenk/test-vortex/internal/tca/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var unknownFormatResponse, _ = json.Marshal(j{"error": ErrUnknownFormat.Error()})
+
+func TestTCAHandler_GetReports(t *testing.T) {
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	report := tca.Report{
+		Algorithm:        "algo",
+		Orders:           5,
+		BaseVolume:       2,
+		QuoteVolume:      20,
+		AvgSlippageBps:   1.5,
+		AvgSpreadCapture: 0.25,
+		CommissionBps:    10,
+		MakerOrders:      1,
+		TakerOrders:      3,
+		MakerRatio:       0.25,
+		NoTouchOrders:    1,
+	}
+	type mockBehavior func(s *mock_service.MockTCAService)
+	testTable := []struct {
+		name               string
+		params             string
+		accept             string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:   "SUCCESS (JSON)",
+			params: "?algorithm=algo",
+			mockBehavior: func(s *mock_service.MockTCAService) {
+				s.EXPECT().GetReports(
+					context.Background(),
+					tca.Query{From: now.Add(-defaultTCAWindow), To: now, Algorithm: "algo"},
+				).Return([]tca.Report{report}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"from":"2024-01-01T00:00:00Z","reports":[{"algorithm":"algo","orders":5,"baseVolume":2,"quoteVolume":20,"avgSlippageBps":1.5,"avgSpreadCapture":0.25,"commissionBps":10,"makerOrders":1,"takerOrders":3,"makerRatio":0.25,"noTouchOrders":1}],"to":"2024-01-02T00:00:00Z"}`,
+		},
+		{
+			name:   "SUCCESS (CSV)",
+			params: "?from=2024-01-01T12:00:00Z",
+			accept: "text/csv",
+			mockBehavior: func(s *mock_service.MockTCAService) {
+				s.EXPECT().GetReports(
+					context.Background(),
+					tca.Query{From: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), To: now},
+				).Return([]tca.Report{report}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody: "algorithm,orders,base_volume,quote_volume,avg_slippage_bps,avg_spread_capture,commission_bps,maker_orders,taker_orders,maker_ratio,no_touch_orders\n" +
+				"algo,5,2,20,1.5,0.25,10,1,3,0.25,1\n",
+		},
+		{
+			name:   "INVALID INPUT (UNKNOWN FORMAT)",
+			params: "?format=xml",
+			mockBehavior: func(s *mock_service.MockTCAService) {
+				s.EXPECT().GetReports(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(unknownFormatResponse),
+		},
+		{
+			name:   "INVALID INPUT (FROM AFTER TO)",
+			params: "?from=2024-01-03T00:00:00Z",
+			mockBehavior: func(s *mock_service.MockTCAService) {
+				s.EXPECT().GetReports(gomock.Any(), gomock.Any()).Times(0)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       string(invalidTimeRangeResponse),
+		},
+	}
+
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				tcaService := mock_service.NewMockTCAService(c)
+				test.mockBehavior(tcaService)
+
+				handler := NewTCAHandler(tcaService, loggerStub)
+				handler.now = func() time.Time { return now }
+				router := chi.NewRouter()
+				router.Get("/tca", handler.GetReports(context.Background()))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/tca"+test.params, nil)
+				if test.accept != "" {
+					r.Header.Set("Accept", test.accept)
+				}
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+				assertDocumented(t, http.MethodGet, "/tca", w)
+			},
+		)
+	}
+}
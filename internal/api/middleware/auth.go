@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/api/apiversion"
+	"github.com/plinkplenk/test-vortex/internal/api/codec"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authService "github.com/plinkplenk/test-vortex/internal/auth/service"
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"log/slog"
+	"net/http"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type Auth struct {
+	authService authService.AuthService
+	logger      *slog.Logger
+}
+
+func NewAuthMiddleware(authService authService.AuthService, logger *slog.Logger) Auth {
+	return Auth{authService: authService, logger: logger}
+}
+
+// Authenticate rejects requests without valid api key and stores caller identity in request context.
+// Requests already authenticated by signature are passed as is
+func (a Auth) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := auth.IdentityFromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			identity, err := a.authService.Authenticate(r.Context(), r.Header.Get(APIKeyHeader))
+			if err != nil {
+				a.logger.DebugContext(r.Context(), "Unauthenticated request", "PATH", r.URL.Path, "error", err)
+				errorResponse(w, http.StatusUnauthorized, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		},
+	)
+}
+
+// RequireIdentity rejects requests not authenticated by the previous middlewares.
+// It goes after them when any authentication is configured, so credentials are never optional
+func RequireIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := auth.IdentityFromContext(r.Context()); !ok {
+				errorResponse(w, http.StatusUnauthorized, auth.ErrNotAuthenticated)
+				return
+			}
+			next.ServeHTTP(w, r)
+		},
+	)
+}
+
+func errorResponse(w http.ResponseWriter, code int, err error) {
+	body := apiversion.ErrorBody(
+		w.Header().Get(apiversion.Header), code, err.Error(), w.Header().Get(requestid.Header),
+	)
+	// content type is set by Negotiate, bodies are JSON before it and with codecs of message types only
+	c, b, _ := codec.Marshal(codec.FromHeader(w.Header()), body)
+	w.Header().Set("Content-Type", c.ContentType())
+	w.WriteHeader(code)
+	_, _ = w.Write(b)
+}
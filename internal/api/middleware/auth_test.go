@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	mock_service "github.com/plinkplenk/test-vortex/internal/auth/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+var loggerStub = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestAuth_Authenticate(t *testing.T) {
+	identity := auth.Identity{Name: "desk", Clients: []string{"client"}, Exchanges: []string{auth.Wildcard}}
+	type mockBehavior func(s *mock_service.MockAuthService)
+	testTable := []struct {
+		name               string
+		key                string
+		mockBehavior       mockBehavior
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name: "SUCCESS",
+			key:  "secret",
+			mockBehavior: func(s *mock_service.MockAuthService) {
+				s.EXPECT().Authenticate(gomock.Any(), "secret").Return(identity, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "desk",
+		},
+		{
+			name: "INVALID KEY",
+			key:  "wrong",
+			mockBehavior: func(s *mock_service.MockAuthService) {
+				s.EXPECT().Authenticate(gomock.Any(), "wrong").Return(auth.Identity{}, auth.ErrInvalidKey)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"error":"invalid api key"}`,
+		},
+		{
+			name: "NO KEY",
+			mockBehavior: func(s *mock_service.MockAuthService) {
+				s.EXPECT().Authenticate(gomock.Any(), "").Return(auth.Identity{}, auth.ErrKeyNotProvided)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"error":"api key not provided"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				authService := mock_service.NewMockAuthService(c)
+				test.mockBehavior(authService)
+
+				next := http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						identity, _ := auth.IdentityFromContext(r.Context())
+						_, _ = w.Write([]byte(identity.Name))
+					},
+				)
+				handler := NewAuthMiddleware(authService, loggerStub).Authenticate(next)
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+				if test.key != "" {
+					r.Header.Set(APIKeyHeader, test.key)
+				}
+				handler.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+			},
+		)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	testTable := []struct {
+		name               string
+		identity           *auth.Identity
+		role               auth.Role
+		expectedStatusCode int
+	}{
+		{
+			name:               "SAME ROLE",
+			identity:           &auth.Identity{Roles: []auth.Role{auth.RoleWriter}},
+			role:               auth.RoleWriter,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "HIGHER ROLE",
+			identity:           &auth.Identity{Roles: []auth.Role{auth.RoleAdmin}},
+			role:               auth.RoleReader,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "LOWER ROLE",
+			identity:           &auth.Identity{Roles: []auth.Role{auth.RoleReader}},
+			role:               auth.RoleWriter,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "NO ROLES",
+			identity:           &auth.Identity{},
+			role:               auth.RoleReader,
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{
+			name:               "AUTH DISABLED",
+			role:               auth.RoleAdmin,
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+				handler := RequireRole(test.role)(next)
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+				if test.identity != nil {
+					r = r.WithContext(auth.WithIdentity(r.Context(), *test.identity))
+				}
+				handler.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+			},
+		)
+	}
+}
+
+func TestRequireIdentity(t *testing.T) {
+	identity := auth.Identity{Name: "desk", Roles: []auth.Role{auth.RoleReader}}
+	testTable := []struct {
+		name               string
+		token              string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			name:               "AUTHENTICATED",
+			token:              "token",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "desk",
+		},
+		{
+			name:               "NO CREDENTIALS",
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedBody:       `{"error":"request is not authenticated"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				jwtService := mock_service.NewMockJWTService(c)
+				jwtService.EXPECT().Verify(gomock.Any(), "token").Return(identity, nil).AnyTimes()
+
+				next := http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						identity, _ := auth.IdentityFromContext(r.Context())
+						_, _ = w.Write([]byte(identity.Name))
+					},
+				)
+				// bearer tokens are optional for the jwt middleware, so identity is required after it
+				handler := NewJWTMiddleware(jwtService, loggerStub).Authenticate(RequireIdentity(next))
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/markouts/compute", nil)
+				if test.token != "" {
+					r.Header.Set("Authorization", "Bearer "+test.token)
+				}
+				handler.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedStatusCode, w.Code)
+				assert.Equal(t, test.expectedBody, w.Body.String())
+			},
+		)
+	}
+}
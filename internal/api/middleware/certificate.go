@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authService "github.com/plinkplenk/test-vortex/internal/auth/service"
+	"log/slog"
+	"net/http"
+)
+
+type ClientCertificate struct {
+	certificateService authService.CertificateService
+	logger             *slog.Logger
+}
+
+func NewClientCertificateMiddleware(
+	certificateService authService.CertificateService, logger *slog.Logger,
+) ClientCertificate {
+	return ClientCertificate{certificateService: certificateService, logger: logger}
+}
+
+// Authenticate stores identity mapped to the verified client certificate in request context.
+// Requests without verified certificate, with unmapped certificate or already authenticated by signature
+// are passed as is, so they can be authenticated by bearer token or api key
+func (c ClientCertificate) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, ok := auth.IdentityFromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			certificate := r.TLS.VerifiedChains[0][0]
+			identity, err := c.certificateService.Identify(r.Context(), certificate)
+			if err != nil {
+				c.logger.DebugContext(
+					r.Context(), "Client certificate is not mapped", "SUBJECT", auth.CertificateSubject(certificate),
+					"error", err,
+				)
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		},
+	)
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	mock_service "github.com/plinkplenk/test-vortex/internal/auth/service/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientCertificate_Authenticate(t *testing.T) {
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: "gateway-1"}}
+	identity := auth.Identity{Name: "gateway-1", Clients: []string{"client"}}
+	type mockBehavior func(s *mock_service.MockCertificateService)
+	testTable := []struct {
+		name         string
+		tls          *tls.ConnectionState
+		mockBehavior mockBehavior
+		expectedName string
+	}{
+		{
+			name: "MAPPED CERTIFICATE",
+			tls:  &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+			mockBehavior: func(s *mock_service.MockCertificateService) {
+				s.EXPECT().Identify(gomock.Any(), certificate).Return(identity, nil)
+			},
+			expectedName: "gateway-1",
+		},
+		{
+			name: "UNMAPPED CERTIFICATE",
+			tls:  &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}},
+			mockBehavior: func(s *mock_service.MockCertificateService) {
+				s.EXPECT().Identify(gomock.Any(), certificate).Return(auth.Identity{}, auth.ErrUnknownCertificate)
+			},
+		},
+		{
+			name:         "NOT VERIFIED CERTIFICATE",
+			tls:          &tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}},
+			mockBehavior: func(s *mock_service.MockCertificateService) {},
+		},
+		{
+			name:         "PLAIN HTTP",
+			mockBehavior: func(s *mock_service.MockCertificateService) {},
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				certificateService := mock_service.NewMockCertificateService(c)
+				test.mockBehavior(certificateService)
+				var name string
+				handler := NewClientCertificateMiddleware(certificateService, loggerStub).Authenticate(
+					http.HandlerFunc(
+						func(w http.ResponseWriter, r *http.Request) {
+							if identity, ok := auth.IdentityFromContext(r.Context()); ok {
+								name = identity.Name
+							}
+						},
+					),
+				)
+
+				r := httptest.NewRequest(http.MethodGet, "/orders/history/client/exchange/label/pair", nil)
+				r.TLS = test.tls
+				handler.ServeHTTP(httptest.NewRecorder(), r)
+				assert.Equal(t, test.expectedName, name)
+			},
+		)
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/api/codec"
+	"net/http"
+)
+
+var (
+	ErrNotAcceptable        = errors.New("route has no protobuf messages, accept json or msgpack")
+	ErrUnsupportedMediaType = errors.New("route has no protobuf messages, send json or msgpack")
+)
+
+// Negotiate selects the codec of response bodies by Accept header and sets its content type
+// before the request is handled, so handlers and middlewares encode bodies with it
+func Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			c := codec.Negotiate(r.Header.Get("Accept"))
+			w.Header().Set("Content-Type", c.ContentType())
+			next.ServeHTTP(w, r)
+		},
+	)
+}
+
+// Untyped serves routes without protobuf message types after Negotiate. Protobuf request bodies are rejected
+// with 415 and Accept of only Protobuf with 406, another accepted codec is used if Protobuf is preferred
+func Untyped(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if codec.FromHeader(r.Header).ContentType() == codec.ProtobufType {
+				errorResponse(w, http.StatusUnsupportedMediaType, ErrUnsupportedMediaType)
+				return
+			}
+			if codec.FromHeader(w.Header()).ContentType() == codec.ProtobufType {
+				c, ok := codec.NegotiateUntyped(r.Header.Get("Accept"))
+				if !ok {
+					errorResponse(w, http.StatusNotAcceptable, ErrNotAcceptable)
+					return
+				}
+				w.Header().Set("Content-Type", c.ContentType())
+			}
+			next.ServeHTTP(w, r)
+		},
+	)
+}
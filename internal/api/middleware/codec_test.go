@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"github.com/plinkplenk/test-vortex/internal/api/codec"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	disabled := Negotiate(Feature(func() bool { return false })(http.NotFoundHandler()))
+	testTable := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+	}{
+		{name: "DEFAULT", expectedContentType: codec.JSONType},
+		{name: "MESSAGEPACK", accept: "application/msgpack", expectedContentType: codec.MessagePackType},
+		{name: "UNSUPPORTED", accept: "text/csv", expectedContentType: codec.JSONType},
+		{name: "PROTOBUF ERROR", accept: "application/x-protobuf", expectedContentType: codec.JSONType},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/tca", nil)
+				r.Header.Set("Accept", test.accept)
+				disabled.ServeHTTP(w, r)
+
+				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+				var body map[string]any
+				c, _ := codec.Lookup(test.expectedContentType)
+				assert.NoError(t, c.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, "feature is disabled", body["error"])
+			},
+		)
+	}
+}
+
+func TestUntyped(t *testing.T) {
+	handler := Negotiate(
+		Untyped(
+			http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				},
+			),
+		),
+	)
+	testTable := []struct {
+		name                string
+		accept              string
+		contentType         string
+		expectedCode        int
+		expectedContentType string
+	}{
+		{name: "JSON", accept: "application/json", expectedCode: http.StatusOK, expectedContentType: codec.JSONType},
+		{
+			name:                "MESSAGEPACK",
+			accept:              "application/msgpack",
+			expectedCode:        http.StatusOK,
+			expectedContentType: codec.MessagePackType,
+		},
+		{
+			name:                "PROTOBUF PREFERRED",
+			accept:              "application/x-protobuf, application/msgpack;q=0.5",
+			expectedCode:        http.StatusOK,
+			expectedContentType: codec.MessagePackType,
+		},
+		{
+			name:                "PROTOBUF WILDCARD",
+			accept:              "application/x-protobuf, */*;q=0.1",
+			expectedCode:        http.StatusOK,
+			expectedContentType: codec.JSONType,
+		},
+		{
+			name:                "PROTOBUF ONLY",
+			accept:              "application/x-protobuf",
+			expectedCode:        http.StatusNotAcceptable,
+			expectedContentType: codec.JSONType,
+		},
+		{
+			name:                "PROTOBUF BODY",
+			contentType:         "application/x-protobuf",
+			expectedCode:        http.StatusUnsupportedMediaType,
+			expectedContentType: codec.JSONType,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, "/orders/lifecycle", bytes.NewReader(nil))
+				r.Header.Set("Accept", test.accept)
+				r.Header.Set("Content-Type", test.contentType)
+				handler.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedCode, w.Code)
+				assert.Equal(t, test.expectedContentType, w.Header().Get("Content-Type"))
+			},
+		)
+	}
+}
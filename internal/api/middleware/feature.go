@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+)
+
+var ErrFeatureDisabled = errors.New("feature is disabled")
+
+// Feature responds with 404 while enabled returns false, so optional routes can be turned off without restart
+func Feature(enabled func() bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if !enabled() {
+					errorResponse(w, http.StatusNotFound, ErrFeatureDisabled)
+					return
+				}
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
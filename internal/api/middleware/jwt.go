@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authService "github.com/plinkplenk/test-vortex/internal/auth/service"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const bearerPrefix = "Bearer "
+
+type JWT struct {
+	jwtService authService.JWTService
+	logger     *slog.Logger
+}
+
+func NewJWTMiddleware(jwtService authService.JWTService, logger *slog.Logger) JWT {
+	return JWT{jwtService: jwtService, logger: logger}
+}
+
+// Authenticate validates bearer token from Authorization header and stores identity from its claims
+// in request context. Requests without bearer token or already authenticated by signature are passed as is
+func (j JWT) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, ok := auth.IdentityFromContext(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			identity, err := j.jwtService.Verify(r.Context(), strings.TrimSpace(header[len(bearerPrefix):]))
+			if err != nil {
+				j.logger.DebugContext(r.Context(), "Invalid bearer token", "PATH", r.URL.Path, "error", err)
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				errorResponse(w, http.StatusUnauthorized, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		},
+	)
+}
+
+// RequireRole rejects authenticated callers without the role, see auth.AuthorizeRole
+func RequireRole(role auth.Role) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if err := auth.AuthorizeRole(r.Context(), role); err != nil {
+					errorResponse(w, http.StatusForbidden, err)
+					return
+				}
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
@@ -21,6 +21,11 @@ func (l *loggerWriter) WriteHeader(code int) {
 	l.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap lets streaming handlers flush the underlying writer with http.ResponseController
+func (l *loggerWriter) Unwrap() http.ResponseWriter {
+	return l.ResponseWriter
+}
+
 type Logger struct {
 	logger *slog.Logger
 }
@@ -35,7 +40,8 @@ func (l Logger) Log(next http.Handler) http.Handler {
 			lw := wrapWriter(w)
 			timeBefore := time.Now()
 			next.ServeHTTP(lw, r)
-			l.logger.Info(
+			l.logger.InfoContext(
+				r.Context(),
 				"Incoming request",
 				"PATH", r.URL.Path,
 				"METHOD", r.Method,
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/metrics"
+	"net/http"
+	"time"
+)
+
+// unmatchedRoute labels requests that didn't match any route, so unknown paths don't create new series
+const unmatchedRoute = "unmatched"
+
+type Metrics struct {
+	metrics *metrics.Metrics
+}
+
+func NewMetricsMiddleware(metrics *metrics.Metrics) Metrics {
+	return Metrics{metrics: metrics}
+}
+
+// Observe records request count and latency by chi route pattern
+func (m Metrics) Observe(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			lw := wrapWriter(w)
+			timeBefore := time.Now()
+			next.ServeHTTP(lw, r)
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := lw.code
+			if status == 0 {
+				status = http.StatusOK
+			}
+			m.metrics.ObserveRequest(route, r.Method, status, time.Since(timeBefore))
+		},
+	)
+}
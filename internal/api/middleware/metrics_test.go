@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	m := metrics.New()
+	r := chi.NewRouter()
+	r.Use(NewMetricsMiddleware(m).Observe)
+	r.Route(
+		"/orders", func(r chi.Router) {
+			r.Get("/{exchange_name}/{pair}", func(w http.ResponseWriter, r *http.Request) {})
+			r.Post("/", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadRequest) })
+		},
+	)
+
+	for _, request := range []struct{ method, target string }{
+		{http.MethodGet, "/orders/binance/BTC_USDT"},
+		{http.MethodGet, "/orders/okx/ETH_USDT"},
+		{http.MethodPost, "/orders/"},
+		{http.MethodGet, "/unknown"},
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(request.method, request.target, nil))
+	}
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+	assert.Contains(
+		t, string(body),
+		`order_service_http_requests_total{method="GET",route="/orders/{exchange_name}/{pair}",status="200"} 2`,
+	)
+	assert.Contains(t, string(body), `order_service_http_requests_total{method="POST",route="/orders",status="400"} 1`)
+	assert.Contains(t, string(body), `order_service_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, string(body), `order_service_http_request_duration_seconds_bucket`)
+}
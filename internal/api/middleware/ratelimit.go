@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/codec"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/ratelimit"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Rate limit keys
+const (
+	// RateLimitByKey limits every api key, signing key or token subject, anonymous requests are limited by ip
+	RateLimitByKey = "key"
+	// RateLimitByClient limits every client name the request is made for, requests without client are limited by key
+	RateLimitByClient = "client"
+	// RateLimitByIP limits every remote address
+	RateLimitByIP = "ip"
+)
+
+// maxPeekBody limits how much of the body is read to find client name
+const maxPeekBody = 1 << 20
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+type RateLimit struct {
+	read   *ratelimit.Limiter
+	write  *ratelimit.Limiter
+	keyBy  string
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewRateLimitMiddleware creates middleware with separate limiters for read and write routes
+func NewRateLimitMiddleware(read, write *ratelimit.Limiter, keyBy string, logger *slog.Logger) RateLimit {
+	return RateLimit{read: read, write: write, keyBy: keyBy, logger: logger, now: time.Now}
+}
+
+// Read limits read routes
+func (l RateLimit) Read(next http.Handler) http.Handler {
+	return l.limit(l.read, "read", next)
+}
+
+// Write limits write routes
+func (l RateLimit) Write(next http.Handler) http.Handler {
+	return l.limit(l.write, "write", next)
+}
+
+func (l RateLimit) limit(limiter *ratelimit.Limiter, kind string, next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if !limiter.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := l.key(r)
+			decision := limiter.Allow(kind+"\n"+key, l.now())
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(decision.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(decision.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(decision.Reset)))
+			if !decision.Allowed {
+				l.logger.DebugContext(r.Context(), "Rate limit exceeded", "PATH", r.URL.Path, "KEY", key)
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(decision.RetryAfter)))
+				errorResponse(w, http.StatusTooManyRequests, ErrRateLimited)
+				return
+			}
+			next.ServeHTTP(w, r)
+		},
+	)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+func (l RateLimit) key(r *http.Request) string {
+	switch l.keyBy {
+	case RateLimitByIP:
+		return "ip:" + remoteIP(r)
+	case RateLimitByClient:
+		if client := clientName(r); client != "" {
+			return "client:" + client
+		}
+	}
+	if identity, ok := auth.IdentityFromContext(r.Context()); ok {
+		return "key:" + identity.Name
+	}
+	return "ip:" + remoteIP(r)
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientName returns client from client_name url param or from "client" object of the body
+func clientName(r *http.Request) string {
+	if client := chi.URLParam(r, "client_name"); client != "" {
+		return client
+	}
+	if r.Body == nil || r.Method == http.MethodGet {
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPeekBody))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	if err != nil {
+		return ""
+	}
+	var payload struct {
+		Client struct {
+			ClientName string `json:"clientName"`
+		} `json:"client"`
+	}
+	if err := codec.FromHeader(r.Header).Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+	return payload.Client.ClientName
+}
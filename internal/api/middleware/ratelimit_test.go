@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	type request struct {
+		method   string
+		target   string
+		body     string
+		remote   string
+		identity string
+	}
+	testTable := []struct {
+		name     string
+		keyBy    string
+		requests []request
+		// expected status code of each request
+		expected []int
+	}{
+		{
+			name:  "BY KEY",
+			keyBy: RateLimitByKey,
+			requests: []request{
+				{method: http.MethodGet, target: "/history/a/ex", remote: "10.0.0.1:1", identity: "desk"},
+				{method: http.MethodGet, target: "/history/b/ex", remote: "10.0.0.2:1", identity: "desk"},
+				{method: http.MethodGet, target: "/history/a/ex", remote: "10.0.0.1:1", identity: "other"},
+			},
+			expected: []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+		{
+			name:  "BY CLIENT",
+			keyBy: RateLimitByClient,
+			requests: []request{
+				{method: http.MethodGet, target: "/history/a/ex", identity: "desk"},
+				{method: http.MethodGet, target: "/history/b/ex", identity: "desk"},
+				{method: http.MethodPost, target: "/history", body: `{"client":{"clientName":"a"}}`},
+				{method: http.MethodPost, target: "/history", body: `{"client":{"clientName":"a"}}`},
+			},
+			expected: []int{http.StatusOK, http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+		},
+		{
+			name:  "BY IP",
+			keyBy: RateLimitByIP,
+			requests: []request{
+				{method: http.MethodGet, target: "/history/a/ex", remote: "10.0.0.1:1", identity: "desk"},
+				{method: http.MethodGet, target: "/history/a/ex", remote: "10.0.0.1:2", identity: "other"},
+				{method: http.MethodGet, target: "/history/a/ex", remote: "10.0.0.2:1", identity: "desk"},
+			},
+			expected: []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK},
+		},
+		{
+			name:  "SEPARATE READ AND WRITE",
+			keyBy: RateLimitByKey,
+			requests: []request{
+				{method: http.MethodGet, target: "/history/a/ex", identity: "desk"},
+				{method: http.MethodPost, target: "/history", identity: "desk"},
+			},
+			expected: []int{http.StatusOK, http.StatusOK},
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				limit := ratelimit.Limit{Rate: 0.5, Burst: 1}
+				m := NewRateLimitMiddleware(ratelimit.NewLimiter(limit), ratelimit.NewLimiter(limit), test.keyBy, loggerStub)
+				m.now = func() time.Time { return now }
+				next := http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						body, _ := io.ReadAll(r.Body)
+						_, _ = w.Write(body)
+					},
+				)
+				r := chi.NewRouter()
+				r.With(m.Read).Get("/history/{client_name}/{exchange_name}", next)
+				r.With(m.Write).Post("/history", next)
+
+				for i, req := range test.requests {
+					w := httptest.NewRecorder()
+					httpRequest := httptest.NewRequest(req.method, req.target, strings.NewReader(req.body))
+					if req.remote != "" {
+						httpRequest.RemoteAddr = req.remote
+					}
+					if req.identity != "" {
+						httpRequest = httpRequest.WithContext(
+							auth.WithIdentity(httpRequest.Context(), auth.Identity{Name: req.identity}),
+						)
+					}
+					r.ServeHTTP(w, httpRequest)
+
+					assert.Equal(t, test.expected[i], w.Code, "request %d", i)
+					assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+					if w.Code == http.StatusTooManyRequests {
+						assert.Equal(t, "2", w.Header().Get("Retry-After"))
+						assert.Equal(t, `{"error":"rate limit exceeded"}`, w.Body.String())
+					} else {
+						assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+						assert.Equal(t, req.body, w.Body.String(), "body is passed to handler")
+					}
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"net/http"
+)
+
+// RequestID takes request id from X-Request-ID header or generates one, stores it in request context
+// and sets it in the response header before the request is handled, so every response carries it
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestid.Header)
+			if !requestid.Valid(id) {
+				id = requestid.New()
+			}
+			w.Header().Set(requestid.Header, id)
+			next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+		},
+	)
+}
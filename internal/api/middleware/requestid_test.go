@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	testTable := []struct {
+		name      string
+		requestID string
+		generated bool
+	}{
+		{name: "ACCEPTED", requestID: "gateway-1-42"},
+		{name: "GENERATED", generated: true},
+		{name: "TOO LONG", requestID: strings.Repeat("a", 129), generated: true},
+		{name: "NOT PRINTABLE", requestID: "id\twith tab", generated: true},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var stored string
+				handler := RequestID(
+					http.HandlerFunc(
+						func(w http.ResponseWriter, r *http.Request) {
+							stored = requestid.FromContext(r.Context())
+							errorResponse(w, http.StatusInternalServerError, errors.New("db is down"))
+						},
+					),
+				)
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+				if test.requestID != "" {
+					r.Header.Set(requestid.Header, test.requestID)
+				}
+				handler.ServeHTTP(w, r)
+
+				id := w.Header().Get(requestid.Header)
+				if test.generated {
+					assert.NotEqual(t, test.requestID, id)
+					assert.True(t, requestid.Valid(id))
+				} else {
+					assert.Equal(t, test.requestID, id)
+				}
+				assert.Equal(t, id, stored)
+				assert.JSONEq(t, `{"error":"db is down","requestId":"`+id+`"}`, w.Body.String())
+			},
+		)
+	}
+}
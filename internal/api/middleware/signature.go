@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authService "github.com/plinkplenk/test-vortex/internal/auth/service"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	SignatureKeyHeader       = "X-Signature-Key"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+	SignatureNonceHeader     = "X-Signature-Nonce"
+	SignatureHeader          = "X-Signature"
+)
+
+var ErrBodyTooLarge = errors.New("signed request body is too large")
+
+type Signature struct {
+	signingService authService.SigningService
+	// maxBody bounds signed bodies, they are buffered before the request is authenticated
+	maxBody int64
+	logger  *slog.Logger
+}
+
+func NewSignatureMiddleware(signingService authService.SigningService, maxBody int64, logger *slog.Logger) Signature {
+	return Signature{signingService: signingService, maxBody: maxBody, logger: logger}
+}
+
+// Verify verifies HMAC signature of requests carrying X-Signature header and stores signing key identity
+// in request context, such requests don't need an api key. Requests without signature are passed as is.
+// Signed bodies larger than maxBody are rejected with 413
+func (s Signature) Verify(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(SignatureHeader) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxBody))
+			if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+				errorResponse(w, http.StatusRequestEntityTooLarge, ErrBodyTooLarge)
+				return
+			}
+			if err != nil {
+				errorResponse(w, http.StatusBadRequest, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			identity, err := s.signingService.Verify(
+				r.Context(), auth.SignedRequest{
+					KeyID:     r.Header.Get(SignatureKeyHeader),
+					Timestamp: r.Header.Get(SignatureTimestampHeader),
+					Nonce:     r.Header.Get(SignatureNonceHeader),
+					Signature: r.Header.Get(SignatureHeader),
+					Method:    r.Method,
+					Path:      r.URL.RequestURI(),
+					Body:      body,
+				},
+			)
+			if err != nil {
+				s.logger.DebugContext(r.Context(), "Invalid request signature", "PATH", r.URL.Path, "error", err)
+				errorResponse(w, http.StatusUnauthorized, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		},
+	)
+}
+
+// Require rejects requests that were not verified by Verify
+func (s Signature) Require(next http.Handler) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if identity, ok := auth.IdentityFromContext(r.Context()); !ok || !identity.Signed {
+				errorResponse(w, http.StatusUnauthorized, auth.ErrSignatureNotProvided)
+				return
+			}
+			next.ServeHTTP(w, r)
+		},
+	)
+}
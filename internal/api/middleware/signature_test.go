@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authService "github.com/plinkplenk/test-vortex/internal/auth/service"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type signingRepositoryStub []auth.SigningKey
+
+func (r signingRepositoryStub) GetSigningKeys(_ context.Context) ([]auth.SigningKey, error) {
+	return r, nil
+}
+
+func TestSignature(t *testing.T) {
+	key := auth.SigningKey{KeyID: "gateway", Secrets: []string{"secret"}, Clients: []string{"client"}}
+	signingService, err := authService.NewSigningService(
+		context.Background(), signingRepositoryStub{key}, time.Second, 30*time.Second,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := func(nonce, path, body string) auth.SignedRequest {
+		request := auth.SignedRequest{
+			KeyID:     key.KeyID,
+			Timestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			Nonce:     nonce,
+			Method:    http.MethodPost,
+			Path:      path,
+			Body:      []byte(body),
+		}
+		request.Signature = auth.Sign("secret", request)
+		return request
+	}
+	body := `{"client":{"clientName":"client"}}`
+	testTable := []struct {
+		name         string
+		signed       *auth.SignedRequest
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{
+			name:         "VALID",
+			signed:       ptr(signed("1", "/orders/history", body)),
+			path:         "/orders/history",
+			body:         body,
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:         "TAMPERED BODY",
+			signed:       ptr(signed("2", "/orders/history", body)),
+			path:         "/orders/history",
+			body:         `{"client":{"clientName":"other"}}`,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "TAMPERED PATH",
+			signed:       ptr(signed("3", "/orders/history", body)),
+			path:         "/orders",
+			body:         body,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "TAMPERED QUERY",
+			signed:       ptr(signed("4", "/orders/history?label=a", body)),
+			path:         "/orders/history?label=b",
+			body:         body,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "REPLAYED NONCE",
+			signed:       ptr(signed("1", "/orders/history", body)),
+			path:         "/orders/history",
+			body:         body,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "BODY TOO LARGE",
+			signed:       ptr(signed("5", "/orders/history", strings.Repeat("a", 65))),
+			path:         "/orders/history",
+			body:         strings.Repeat("a", 65),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{name: "UNSIGNED WRITE", path: "/orders/history", body: body, expectedCode: http.StatusUnauthorized},
+	}
+	signature := NewSignatureMiddleware(signingService, 64, loggerStub)
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var (
+					identity auth.Identity
+					received []byte
+				)
+				handler := signature.Verify(
+					signature.Require(
+						http.HandlerFunc(
+							func(w http.ResponseWriter, r *http.Request) {
+								identity, _ = auth.IdentityFromContext(r.Context())
+								received, _ = io.ReadAll(r.Body)
+								w.WriteHeader(http.StatusCreated)
+							},
+						),
+					),
+				)
+
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodPost, test.path, bytes.NewBufferString(test.body))
+				if test.signed != nil {
+					r.Header.Set(SignatureKeyHeader, test.signed.KeyID)
+					r.Header.Set(SignatureTimestampHeader, test.signed.Timestamp)
+					r.Header.Set(SignatureNonceHeader, test.signed.Nonce)
+					r.Header.Set(SignatureHeader, test.signed.Signature)
+				}
+				handler.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedCode, w.Code)
+				if test.expectedCode != http.StatusCreated {
+					assert.Nil(t, received, "rejected requests are not handled")
+					return
+				}
+				assert.True(t, identity.Signed)
+				assert.Equal(t, key.Clients, identity.Clients)
+				assert.Equal(t, test.body, string(received), "handler reads the verified body")
+			},
+		)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/api/apiversion"
+	"net/http"
+)
+
+// APIVersion sets the version serving the request in the response header before the request is handled,
+// so error responses of handlers and middlewares use the shape of that version
+func APIVersion(version string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(apiversion.Header, version)
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
+
+// Deprecated marks responses as deprecated and links the same path under successor prefix like "/v1"
+func Deprecated(successor string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Deprecation", "true")
+				w.Header().Set("Link", "<"+successor+r.URL.Path+`>; rel="successor-version"`)
+				next.ServeHTTP(w, r)
+			},
+		)
+	}
+}
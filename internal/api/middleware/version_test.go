@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/api/apiversion"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIVersion(t *testing.T) {
+	disabled := Feature(func() bool { return false })(http.NotFoundHandler())
+	testTable := []struct {
+		name         string
+		version      string
+		expectedBody string
+	}{
+		{
+			name:         "V1",
+			version:      apiversion.V1,
+			expectedBody: `{"error":"feature is disabled","requestId":"req-1"}`,
+		},
+		{
+			name:         "V2",
+			version:      apiversion.V2,
+			expectedBody: `{"error":{"code":"not_found","message":"feature is disabled","requestId":"req-1"}}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				handler := RequestID(APIVersion(test.version)(disabled))
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(http.MethodGet, "/tca", nil)
+				r.Header.Set("X-Request-ID", "req-1")
+				handler.ServeHTTP(w, r)
+
+				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, test.version, w.Header().Get(apiversion.Header))
+				assert.Equal(t, test.expectedBody, w.Body.String())
+			},
+		)
+	}
+}
+
+func TestDeprecated(t *testing.T) {
+	handler := Deprecated("/v1")(http.NotFoundHandler())
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/some-exchange/A_B", nil))
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+	assert.Equal(t, `</v1/orders/some-exchange/A_B>; rel="successor-version"`, w.Header().Get("Link"))
+}
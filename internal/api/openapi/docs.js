@@ -0,0 +1,128 @@
+// Renders the document served at /openapi.json without third party scripts,
+// so the page works offline and only runs code served by the api
+(function () {
+    "use strict";
+
+    const methods = ["get", "post", "put", "patch", "delete"];
+    const root = document.getElementById("docs");
+
+    function element(tag, text, className) {
+        const node = document.createElement(tag);
+        if (text !== undefined) {
+            node.textContent = text;
+        }
+        if (className) {
+            node.className = className;
+        }
+        return node;
+    }
+
+    // resolve follows local references, cycles are cut at depth
+    function resolve(spec, node, depth) {
+        if (depth > 8 || node === null || typeof node !== "object") {
+            return node;
+        }
+        if (typeof node.$ref === "string" && node.$ref.startsWith("#/")) {
+            let target = spec;
+            for (const key of node.$ref.slice(2).split("/")) {
+                target = target === undefined ? undefined : target[key];
+            }
+            return resolve(spec, target, depth + 1);
+        }
+        if (Array.isArray(node)) {
+            return node.map((item) => resolve(spec, item, depth + 1));
+        }
+        const result = {};
+        for (const [key, value] of Object.entries(node)) {
+            result[key] = resolve(spec, value, depth + 1);
+        }
+        return result;
+    }
+
+    function content(spec, section, body) {
+        for (const [mediaType, media] of Object.entries(body.content || {})) {
+            section.appendChild(element("div", mediaType, "media"));
+            if (media.schema) {
+                section.appendChild(element("pre", JSON.stringify(resolve(spec, media.schema, 0), null, 2)));
+            }
+        }
+    }
+
+    function operation(spec, path, method, op) {
+        const section = element("details", undefined, "operation");
+        const summary = element("summary");
+        summary.appendChild(element("span", method.toUpperCase(), "method " + method));
+        summary.appendChild(element("code", path));
+        summary.appendChild(element("span", op.summary || "", "summary"));
+        section.appendChild(summary);
+        if (op.description) {
+            section.appendChild(element("p", op.description));
+        }
+        const parameters = (op.parameters || []).map((parameter) => resolve(spec, parameter, 0));
+        if (parameters.length > 0) {
+            section.appendChild(element("h4", "Parameters"));
+            const list = element("ul");
+            for (const parameter of parameters) {
+                const type = parameter.schema ? parameter.schema.type || "" : "";
+                const required = parameter.required ? ", required" : "";
+                list.appendChild(
+                    element(
+                        "li",
+                        `${parameter.name} (${parameter.in}, ${type}${required})` +
+                        (parameter.description ? ` - ${parameter.description}` : ""),
+                    ),
+                );
+            }
+            section.appendChild(list);
+        }
+        if (op.requestBody) {
+            section.appendChild(element("h4", "Request body"));
+            content(spec, section, resolve(spec, op.requestBody, 0));
+        }
+        section.appendChild(element("h4", "Responses"));
+        for (const [code, documented] of Object.entries(op.responses || {})) {
+            const response = resolve(spec, documented, 0);
+            section.appendChild(element("div", `${code} ${response.description || ""}`, "status"));
+            content(spec, section, response);
+        }
+        return section;
+    }
+
+    function render(spec) {
+        root.textContent = "";
+        const info = spec.info || {};
+        root.appendChild(element("h1", `${info.title || "API"} ${info.version || ""}`));
+        if (info.description) {
+            root.appendChild(element("p", info.description, "description"));
+        }
+        const byTag = new Map();
+        for (const [path, item] of Object.entries(spec.paths || {})) {
+            for (const method of methods) {
+                if (!item[method]) {
+                    continue;
+                }
+                const tag = (item[method].tags || ["default"])[0];
+                if (!byTag.has(tag)) {
+                    byTag.set(tag, []);
+                }
+                byTag.get(tag).push(operation(spec, path, method, item[method]));
+            }
+        }
+        for (const [tag, operations] of byTag) {
+            root.appendChild(element("h2", tag));
+            operations.forEach((op) => root.appendChild(op));
+        }
+    }
+
+    fetch("/openapi.json")
+        .then((response) => {
+            if (!response.ok) {
+                throw new Error(`status ${response.status}`);
+            }
+            return response.json();
+        })
+        .then(render)
+        .catch((err) => {
+            root.textContent = `Document can't be loaded: ${err.message}`;
+        });
+})();
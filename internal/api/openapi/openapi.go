@@ -0,0 +1,194 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/api/codec"
+	"gopkg.in/yaml.v3"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	//go:embed openapi.yaml
+	document []byte
+	//go:embed docs.html
+	docsPage []byte
+	//go:embed docs.js
+	docsScript []byte
+)
+
+// docsPolicy allows the docs page to run only scripts served by the api, so it works offline
+const docsPolicy = "default-src 'self'; style-src 'self' 'unsafe-inline'"
+
+var (
+	ErrNotDocumented    = errors.New("not documented")
+	ErrInvalidReference = errors.New("invalid reference")
+)
+
+// Document is the OpenAPI document of the api
+type Document struct {
+	spec map[string]any
+	json []byte
+}
+
+// Load parses the embedded document
+func Load() (*Document, error) {
+	var raw any
+	if err := yaml.Unmarshal(document, &raw); err != nil {
+		return nil, fmt.Errorf("parse openapi document: %w", err)
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("convert openapi document to json: %w", err)
+	}
+	// decoded from json, so numbers and nested objects have the same types as decoded response bodies
+	var spec map[string]any
+	if err := json.Unmarshal(b, &spec); err != nil {
+		return nil, fmt.Errorf("convert openapi document to json: %w", err)
+	}
+	return &Document{spec: spec, json: b}, nil
+}
+
+// JSON returns the document encoded as json
+func (d *Document) JSON() []byte {
+	return d.json
+}
+
+// ServeJSON serves the document as json
+func (d *Document) ServeJSON(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(d.json)
+}
+
+// ServeDocs serves the page rendering the document served at /openapi.json with the script of ServeDocsScript
+func (d *Document) ServeDocs(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", docsPolicy)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(docsPage)
+}
+
+// ServeDocsScript serves the script of the docs page, it is embedded so the page has no third party scripts
+func (d *Document) ServeDocsScript(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(docsScript)
+}
+
+// HasOperation reports whether the operation is documented,
+// path is a template with parameters in braces like "/orders/{exchange_name}/{pair}"
+func (d *Document) HasOperation(method, path string) bool {
+	_, err := d.operation(method, path)
+	return err == nil
+}
+
+func (d *Document) operation(method, path string) (map[string]any, error) {
+	paths, _ := d.spec["paths"].(map[string]any)
+	item, ok := paths[path].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("path %s: %w", path, ErrNotDocumented)
+	}
+	operation, ok := item[strings.ToLower(method)].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s %s: %w", method, path, ErrNotDocumented)
+	}
+	return operation, nil
+}
+
+// ValidateResponse checks that the response status is documented for the operation
+// and the body matches the schema documented for its content type
+func (d *Document) ValidateResponse(method, path string, code int, header http.Header, body []byte) error {
+	operation, err := d.operation(method, path)
+	if err != nil {
+		return err
+	}
+	responses, _ := operation["responses"].(map[string]any)
+	documented, ok := responses[strconv.Itoa(code)].(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s %s status %d: %w", method, path, code, ErrNotDocumented)
+	}
+	documented, err = d.resolve(documented)
+	if err != nil {
+		return err
+	}
+	content, _ := documented["content"].(map[string]any)
+	if len(content) == 0 {
+		if len(body) != 0 {
+			return fmt.Errorf("%s %s status %d body: %w", method, path, code, ErrNotDocumented)
+		}
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return fmt.Errorf("%s %s status %d: content type: %w", method, path, code, err)
+	}
+	// bodies of other codecs are the documented JSON values in another encoding, unless their type is documented
+	c, ok := codec.Lookup(mediaType)
+	if _, documented := content[mediaType]; ok && !documented {
+		mediaType = codec.JSONType
+	}
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s %s status %d content type %s: %w", method, path, code, mediaType, ErrNotDocumented)
+	}
+	if mediaType != codec.JSONType {
+		return nil
+	}
+	value, err := decodeJSON(c, body)
+	if err != nil {
+		return fmt.Errorf("%s %s status %d: body can't be decoded: %w", method, path, code, err)
+	}
+	schema, _ := media["schema"].(map[string]any)
+	if err := d.validate(schema, value, "$"); err != nil {
+		return fmt.Errorf("%s %s status %d: %w", method, path, code, err)
+	}
+	return nil
+}
+
+// decodeJSON decodes body of the codec to values encoding/json decodes its JSON representation to
+func decodeJSON(c codec.Codec, body []byte) (any, error) {
+	var value any
+	if err := c.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+	if _, ok := c.(codec.JSON); ok {
+		return value, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	value = nil
+	return value, json.Unmarshal(b, &value)
+}
+
+// resolve follows $ref of the node, only references within the document are supported
+func (d *Document) resolve(node map[string]any) (map[string]any, error) {
+	for {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node, nil
+		}
+		pointer, ok := strings.CutPrefix(ref, "#/")
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", ref, ErrInvalidReference)
+		}
+		var current any = d.spec
+		for _, key := range strings.Split(pointer, "/") {
+			object, ok := current.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: %w", ref, ErrInvalidReference)
+			}
+			current = object[key]
+		}
+		if node, ok = current.(map[string]any); !ok {
+			return nil, fmt.Errorf("%s: %w", ref, ErrInvalidReference)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package openapi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestLoad_References(t *testing.T) {
+	document, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var walk func(node any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			if _, ok := node["$ref"]; ok {
+				_, err := document.resolve(node)
+				assert.NoError(t, err)
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(document.spec)
+}
+
+func TestDocument_ValidateResponse(t *testing.T) {
+	document, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	testTable := []struct {
+		name        string
+		method      string
+		path        string
+		code        int
+		header      http.Header
+		body        string
+		expectedErr error
+	}{
+		{
+			name:   "SUCCESS",
+			method: http.MethodGet,
+			path:   "/orders/lifecycle/{order_id}",
+			code:   http.StatusOK,
+			header: jsonHeader,
+			body: `{"order":{"orderId":"8d6c2f0e-8e4b-4a0e-9d4e-3f4b8b1e2a10","clientName":"client",` +
+				`"exchangeName":"some-exchange","label":"label","pair":"A_B","side":"buy","type":"limit",` +
+				`"baseQty":1,"price":0.01,"timeCreated":"2024-01-01T00:00:00Z","status":"new","filledQty":0,` +
+				`"avgFillPrice":0,"commissionQuoteQty":0,"timeUpdated":"2024-01-01T00:00:00Z","fills":null,"events":[]}}`,
+		},
+		{
+			name:   "SUCCESS (ERROR RESPONSE)",
+			method: http.MethodGet,
+			path:   "/orders/{exchange_name}/{pair}",
+			code:   http.StatusNotFound,
+			header: http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+			body:   `{"error":"order book not found","requestId":"req-1"}`,
+		},
+		{
+			name:   "SUCCESS (V2 ERROR RESPONSE)",
+			method: http.MethodGet,
+			path:   "/orders/{exchange_name}/{pair}",
+			code:   http.StatusNotFound,
+			header: jsonHeader,
+			body:   `{"error":{"code":"not_found","message":"order book not found","requestId":"req-1"}}`,
+		},
+		{
+			name:   "SUCCESS (NO CONTENT)",
+			method: http.MethodPost,
+			path:   "/orders",
+			code:   http.StatusCreated,
+		},
+		{
+			name:   "SUCCESS (CSV)",
+			method: http.MethodGet,
+			path:   "/tca",
+			code:   http.StatusOK,
+			header: http.Header{"Content-Type": {"text/csv"}},
+			body:   "algorithm,orders\n",
+		},
+		{
+			name:        "UNKNOWN PATH",
+			method:      http.MethodGet,
+			path:        "/orders",
+			code:        http.StatusOK,
+			expectedErr: ErrNotDocumented,
+		},
+		{
+			name:        "UNKNOWN STATUS",
+			method:      http.MethodGet,
+			path:        "/healthz",
+			code:        http.StatusInternalServerError,
+			header:      jsonHeader,
+			body:        `{"error":"something went wrong"}`,
+			expectedErr: ErrNotDocumented,
+		},
+		{
+			name:        "UNKNOWN CONTENT TYPE",
+			method:      http.MethodGet,
+			path:        "/healthz",
+			code:        http.StatusOK,
+			header:      http.Header{"Content-Type": {"text/plain"}},
+			body:        `{"status":"ok"}`,
+			expectedErr: ErrNotDocumented,
+		},
+		{
+			name:        "NOT ONE OF",
+			method:      http.MethodGet,
+			path:        "/orders/{exchange_name}/{pair}",
+			code:        http.StatusNotFound,
+			header:      jsonHeader,
+			body:        `{"error":{"message":"order book not found"}}`,
+			expectedErr: ErrSchemaMismatch,
+		},
+		{
+			name:        "UNDOCUMENTED PROPERTY",
+			method:      http.MethodGet,
+			path:        "/healthz",
+			code:        http.StatusOK,
+			header:      jsonHeader,
+			body:        `{"status":"ok","uptime":1}`,
+			expectedErr: ErrSchemaMismatch,
+		},
+		{
+			name:        "MISSING PROPERTY",
+			method:      http.MethodGet,
+			path:        "/candles/{exchange_name}/{pair}",
+			code:        http.StatusOK,
+			header:      jsonHeader,
+			body:        `{"candles":[{"time":"2024-01-01T00:00:00Z","open":1,"high":1,"low":1,"close":1,"baseVolume":1}]}`,
+			expectedErr: ErrSchemaMismatch,
+		},
+		{
+			name:   "WRONG TYPE",
+			method: http.MethodGet,
+			path:   "/candles/{exchange_name}/{pair}",
+			code:   http.StatusOK,
+			header: jsonHeader,
+			body: `{"candles":[{"time":"2024-01-01T00:00:00Z","open":1,"high":1,"low":1,"close":1,` +
+				`"baseVolume":1,"quoteVolume":1,"trades":1.5}]}`,
+			expectedErr: ErrSchemaMismatch,
+		},
+		{
+			name:        "NOT NULLABLE",
+			method:      http.MethodGet,
+			path:        "/markouts",
+			code:        http.StatusOK,
+			header:      jsonHeader,
+			body:        `{"markouts":null}`,
+			expectedErr: ErrSchemaMismatch,
+		},
+		{
+			name:        "NOT IN ENUM",
+			method:      http.MethodGet,
+			path:        "/readyz",
+			code:        http.StatusServiceUnavailable,
+			header:      jsonHeader,
+			body:        `{"status":"fail","checks":{"clickhouse":{"status":"down"}}}`,
+			expectedErr: ErrSchemaMismatch,
+		},
+		{
+			name:        "UNDOCUMENTED BODY",
+			method:      http.MethodPost,
+			path:        "/markouts/compute",
+			code:        http.StatusCreated,
+			header:      jsonHeader,
+			body:        `{}`,
+			expectedErr: ErrNotDocumented,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				err := document.ValidateResponse(test.method, test.path, test.code, test.header, []byte(test.body))
+				if test.expectedErr != nil {
+					assert.ErrorIs(t, err, test.expectedErr)
+					return
+				}
+				assert.NoError(t, err)
+			},
+		)
+	}
+}
@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+var ErrSchemaMismatch = errors.New("value doesn't match schema")
+
+// validate checks value decoded from json against the schema.
+// Only keywords used by the document are supported: oneOf, type, format, enum, nullable,
+// properties, required, additionalProperties and items
+func (d *Document) validate(schema map[string]any, value any, at string) error {
+	schema, err := d.resolve(schema)
+	if err != nil {
+		return err
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		return d.validateOneOf(oneOf, value, at)
+	}
+	schemaType, _ := schema["type"].(string)
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable || schemaType == "" {
+			return nil
+		}
+		return mismatch(at, "null is not allowed")
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		switch value.(type) {
+		case string, float64, bool:
+			if !slices.Contains(enum, value) {
+				return mismatch(at, fmt.Sprintf("%v is not one of %v", value, enum))
+			}
+		}
+	}
+	switch schemaType {
+	case "":
+		return nil
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return mismatch(at, "object expected")
+		}
+		return d.validateObject(schema, object, at)
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			return mismatch(at, "array expected")
+		}
+		items, _ := schema["items"].(map[string]any)
+		for i, item := range array {
+			if err := d.validate(items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return mismatch(at, "string expected")
+		}
+		return validateFormat(schema, s, at)
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return mismatch(at, "number expected")
+		}
+		return nil
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			return mismatch(at, "integer expected")
+		}
+		return nil
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return mismatch(at, "boolean expected")
+		}
+		return nil
+	default:
+		return mismatch(at, fmt.Sprintf("unsupported type %s", schemaType))
+	}
+}
+
+// validateOneOf checks that value matches exactly one of the schemas
+func (d *Document) validateOneOf(schemas []any, value any, at string) error {
+	var matched int
+	for _, schema := range schemas {
+		schema, _ := schema.(map[string]any)
+		if err := d.validate(schema, value, at); err == nil {
+			matched++
+		} else if !errors.Is(err, ErrSchemaMismatch) {
+			return err
+		}
+	}
+	if matched != 1 {
+		return mismatch(at, fmt.Sprintf("%d of oneOf schemas matched", matched))
+	}
+	return nil
+}
+
+func (d *Document) validateObject(schema map[string]any, object map[string]any, at string) error {
+	required, _ := schema["required"].([]any)
+	for _, key := range required {
+		if _, ok := object[key.(string)]; !ok {
+			return mismatch(at, fmt.Sprintf("%s is required", key))
+		}
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property, ok := properties[key].(map[string]any)
+		if !ok {
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return mismatch(at, fmt.Sprintf("%s is not documented", key))
+				}
+				continue
+			case map[string]any:
+				property = additional
+			default:
+				continue
+			}
+		}
+		if err := d.validate(property, object[key], at+"."+key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateFormat(schema map[string]any, s string, at string) error {
+	switch schema["format"] {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return mismatch(at, "date-time expected")
+		}
+	case "uuid":
+		if _, err := uuid.Parse(s); err != nil {
+			return mismatch(at, "uuid expected")
+		}
+	}
+	return nil
+}
+
+func mismatch(at string, reason string) error {
+	return fmt.Errorf("%s: %s: %w", at, reason, ErrSchemaMismatch)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/handlers"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	candle "github.com/plinkplenk/test-vortex/internal/candles/service"
+	"log/slog"
+	"net/http"
+)
+
+func CandlesRouter(
+	ctx context.Context,
+	version string,
+	candleService candle.CandlesService,
+	logger *slog.Logger,
+	middlewares Middlewares,
+) http.Handler {
+	candlesHandler := handlers.NewCandlesHandler(candleService, logger)
+	r := chi.NewRouter()
+	reader := with(middlewares.Read, auth.RoleReader)
+	r.With(reader...).Get("/{exchange_name}/{pair}", candlesHandler.GetCandles(ctx))
+	return r
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/handlers"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	exportService "github.com/plinkplenk/test-vortex/internal/export/service"
+	"log/slog"
+	"net/http"
+)
+
+func ExportRouter(
+	ctx context.Context,
+	version string,
+	exportService exportService.ExportService,
+	logger *slog.Logger,
+	middlewares Middlewares,
+) http.Handler {
+	exportHandler := handlers.NewExportHandler(exportService, logger)
+	r := chi.NewRouter()
+	r.With(with(middlewares.Read, auth.RoleReader)...).Get("/orders/history", exportHandler.ExportHistory(ctx))
+	return r
+}
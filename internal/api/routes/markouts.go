@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/handlers"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	markoutService "github.com/plinkplenk/test-vortex/internal/markouts/service"
+	"log/slog"
+	"net/http"
+)
+
+func MarkoutsRouter(
+	ctx context.Context,
+	version string,
+	markoutService markoutService.MarkoutsService,
+	logger *slog.Logger,
+	middlewares Middlewares,
+) http.Handler {
+	markoutsHandler := handlers.NewMarkoutsHandler(markoutService, logger)
+	r := chi.NewRouter()
+	r.With(with(middlewares.Read, auth.RoleReader)...).Get("/", markoutsHandler.GetMarkouts(ctx))
+	r.With(with(middlewares.Write, auth.RoleAdmin)...).Post("/compute", markoutsHandler.Compute(ctx))
+	return r
+}
@@ -4,20 +4,49 @@ import (
 	"context"
 	"github.com/go-chi/chi/v5"
 	"github.com/plinkplenk/test-vortex/internal/api/handlers"
+	"github.com/plinkplenk/test-vortex/internal/auth"
 	order "github.com/plinkplenk/test-vortex/internal/orders/service"
 	"log/slog"
 	"net/http"
+	"slices"
 )
 
-func OrderRouter(ctx context.Context, orderService order.OrdersService, logger *slog.Logger) http.Handler {
+func OrderRouter(
+	ctx context.Context,
+	version string,
+	orderService order.OrdersService,
+	lifecycleService order.LifecycleService,
+	logger *slog.Logger,
+	middlewares Middlewares,
+) http.Handler {
 	orderHandler := handlers.NewOrdersHandler(orderService, logger)
+	lifecycleHandler := handlers.NewLifecycleHandler(lifecycleService, logger)
+	// order books and history exchange messages of OrdersService when Protobuf is negotiated
+	bookReader := typed(middlewares.Read, auth.RoleReader)
+	reader := with(middlewares.Read, auth.RoleReader)
+	writer := with(middlewares.Write, auth.RoleWriter)
+	// role is checked after ingest middlewares, so unsigned requests are rejected as unauthenticated
+	ingest := typed(slices.Concat(middlewares.Write, middlewares.Ingest), auth.RoleWriter)
 	r := chi.NewRouter()
-	r.Get("/{exchange_name}/{pair}", orderHandler.GetOrderBook(ctx))
-	r.Post("/", orderHandler.SaveOrderBook(ctx))
+	r.With(bookReader...).Get("/{exchange_name}/{pair}", orderHandler.GetOrderBook(ctx))
+	r.With(ingest...).Post("/", orderHandler.SaveOrderBook(ctx))
 	r.Route(
 		"/history", func(r chi.Router) {
-			r.Get("/{client_name}/{exchange_name}", orderHandler.GetOrderHistory(ctx))
-			r.Post("/", orderHandler.SaveOrder(ctx))
+			r.With(bookReader...).Get("/{client_name}/{exchange_name}", orderHandler.GetOrderHistory(ctx))
+			r.With(ingest...).Post("/", orderHandler.SaveOrder(ctx))
+		},
+	)
+	r.Route(
+		"/lifecycle", func(r chi.Router) {
+			r.With(reader...).Get("/{order_id}", lifecycleHandler.GetOrderState(ctx))
+			r.Group(
+				func(r chi.Router) {
+					r.Use(writer...)
+					r.Post("/", lifecycleHandler.CreateOrder(ctx))
+					r.Post("/{order_id}/events", lifecycleHandler.AppendEvent(ctx))
+					r.Post("/{order_id}/fills", lifecycleHandler.AppendFill(ctx))
+				},
+			)
 		},
 	)
 	return r
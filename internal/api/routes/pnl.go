@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/handlers"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	pnlService "github.com/plinkplenk/test-vortex/internal/pnl/service"
+	"log/slog"
+	"net/http"
+)
+
+func PnLRouter(
+	ctx context.Context, version string, pnlService pnlService.PnLService, logger *slog.Logger, middlewares Middlewares,
+) http.Handler {
+	pnlHandler := handlers.NewPnLHandler(pnlService, logger)
+	r := chi.NewRouter()
+	reader := with(middlewares.Read, auth.RoleReader)
+	r.With(reader...).Get("/{client_name}/{exchange_name}", pnlHandler.GetPosition(ctx))
+	return r
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/handlers"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	replayService "github.com/plinkplenk/test-vortex/internal/replay/service"
+	"log/slog"
+	"net/http"
+)
+
+func ReplayRouter(
+	ctx context.Context,
+	version string,
+	replayService replayService.ReplayService,
+	logger *slog.Logger,
+	middlewares Middlewares,
+) http.Handler {
+	replayHandler := handlers.NewReplayHandler(replayService, logger)
+	r := chi.NewRouter()
+	r.With(with(middlewares.Read, auth.RoleReader)...).Get("/{exchange_name}/{pair}", replayHandler.Replay(ctx))
+	return r
+}
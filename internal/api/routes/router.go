@@ -3,17 +3,128 @@ package routes
 import (
 	"context"
 	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/apiversion"
+	"github.com/plinkplenk/test-vortex/internal/api/handlers"
 	"github.com/plinkplenk/test-vortex/internal/api/middleware"
+	"github.com/plinkplenk/test-vortex/internal/api/openapi"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	candle "github.com/plinkplenk/test-vortex/internal/candles/service"
+	exportService "github.com/plinkplenk/test-vortex/internal/export/service"
+	"github.com/plinkplenk/test-vortex/internal/health"
+	markoutService "github.com/plinkplenk/test-vortex/internal/markouts/service"
 	order "github.com/plinkplenk/test-vortex/internal/orders/service"
+	pnlService "github.com/plinkplenk/test-vortex/internal/pnl/service"
+	replayService "github.com/plinkplenk/test-vortex/internal/replay/service"
+	tcaService "github.com/plinkplenk/test-vortex/internal/tca/service"
 	"log/slog"
 	"net/http"
+	"slices"
 )
 
+type Services struct {
+	Orders    order.OrdersService
+	Lifecycle order.LifecycleService
+	Candles   candle.CandlesService
+	PnL       pnlService.PnLService
+	TCA       tcaService.TCAService
+	Markouts  markoutService.MarkoutsService
+	Export    exportService.ExportService
+	Replay    replayService.ReplayService
+}
+
+type Middlewares struct {
+	// Global middlewares are applied to every route
+	Global []middleware.Middleware
+	// Read middlewares are applied to every GET route
+	Read []middleware.Middleware
+	// Write middlewares are applied to every POST route
+	Write []middleware.Middleware
+	// Ingest middlewares are applied to order book and order history write routes after Write
+	Ingest []middleware.Middleware
+	// Optional middlewares are applied to routers of optional subsystems by their mount path like "/candles"
+	Optional map[string]middleware.Middleware
+}
+
+// with returns route middlewares followed by role check, the route has no protobuf messages
+func with(middlewares []middleware.Middleware, role auth.Role) []middleware.Middleware {
+	return append(typed(middlewares, role), middleware.Untyped)
+}
+
+// typed is with of routes exchanging protobuf messages of their gRPC methods
+func typed(middlewares []middleware.Middleware, role auth.Role) []middleware.Middleware {
+	return append(slices.Clone(middlewares), middleware.RequireRole(role))
+}
+
+// Handlers are operational endpoints served without global middlewares
+type Handlers struct {
+	// Metrics is served at /metrics if set
+	Metrics http.Handler
+	// Health is served at /healthz and /readyz if set
+	Health *health.Health
+	// OpenAPI is served at /openapi.json with the docs page at /docs and its script at /docs/docs.js if set
+	OpenAPI *openapi.Document
+}
+
+// NewRouter serves every api version under its prefix like "/v1" and operational endpoints at the root.
+// Unversioned routes are kept for clients of the api before versioning, they are served by v1 with deprecation headers.
+// Streams of replay and export end when ctx is done, so they don't hold up server shutdown
 func NewRouter(
-	orderService order.OrdersService, logger *slog.Logger, middlewares ...middleware.Middleware,
+	ctx context.Context, services Services, logger *slog.Logger, middlewares Middlewares, operational Handlers,
+) http.Handler {
+	root := chi.NewRouter()
+	if operational.Metrics != nil {
+		root.Method(http.MethodGet, "/metrics", operational.Metrics)
+	}
+	if operational.Health != nil {
+		healthHandler := handlers.NewHealthHandler(operational.Health, logger)
+		root.Get("/healthz", healthHandler.Live(context.Background()))
+		root.Get("/readyz", healthHandler.Ready(context.Background()))
+	}
+	if operational.OpenAPI != nil {
+		root.Get("/openapi.json", operational.OpenAPI.ServeJSON)
+		root.Get("/docs", operational.OpenAPI.ServeDocs)
+		root.Get("/docs/docs.js", operational.OpenAPI.ServeDocsScript)
+	}
+	for _, version := range []string{apiversion.V1, apiversion.V2} {
+		root.Mount(apiversion.Prefix(version), APIRouter(ctx, version, services, logger, middlewares))
+	}
+	root.Group(
+		func(r chi.Router) {
+			r.Use(middleware.Deprecated(apiversion.Prefix(apiversion.V1)))
+			r.Mount("/", APIRouter(ctx, apiversion.V1, services, logger, middlewares))
+		},
+	)
+	return root
+}
+
+// APIRouter serves routes of the api version with shared services. Routers of subsystems get the version,
+// so v2 routes can be served by their own handlers while v1 payloads stay frozen.
+// Currently versions share handlers and differ only by error bodies, see apiversion.ErrorBody
+func APIRouter(
+	ctx context.Context, version string, services Services, logger *slog.Logger, middlewares Middlewares,
 ) http.Handler {
 	r := chi.NewRouter()
-	r.Use(middlewares...)
-	r.Mount("/orders", OrderRouter(context.Background(), orderService, logger))
+	r.Use(middleware.APIVersion(version))
+	r.Use(middlewares.Global...)
+	r.Mount(
+		"/orders",
+		OrderRouter(context.Background(), version, services.Orders, services.Lifecycle, logger, middlewares),
+	)
+	mountOptional := func(pattern string, router http.Handler) {
+		r.Group(
+			func(r chi.Router) {
+				if optional, ok := middlewares.Optional[pattern]; ok {
+					r.Use(optional)
+				}
+				r.Mount(pattern, router)
+			},
+		)
+	}
+	mountOptional("/candles", CandlesRouter(context.Background(), version, services.Candles, logger, middlewares))
+	mountOptional("/pnl", PnLRouter(context.Background(), version, services.PnL, logger, middlewares))
+	mountOptional("/tca", TCARouter(context.Background(), version, services.TCA, logger, middlewares))
+	mountOptional("/markouts", MarkoutsRouter(context.Background(), version, services.Markouts, logger, middlewares))
+	mountOptional("/export", ExportRouter(ctx, version, services.Export, logger, middlewares))
+	mountOptional("/replay", ReplayRouter(ctx, version, services.Replay, logger, middlewares))
 	return r
 }
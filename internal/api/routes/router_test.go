@@ -0,0 +1,170 @@
+package routes
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/apiversion"
+	"github.com/plinkplenk/test-vortex/internal/api/middleware"
+	"github.com/plinkplenk/test-vortex/internal/api/openapi"
+	"github.com/plinkplenk/test-vortex/internal/health"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRouter_Documented(t *testing.T) {
+	document, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter(
+		context.Background(),
+		Services{},
+		nil,
+		Middlewares{},
+		Handlers{Metrics: http.NotFoundHandler(), Health: health.New(time.Second), OpenAPI: document},
+	)
+
+	var routes int
+	err = chi.Walk(
+		router.(chi.Routes),
+		func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+			routes++
+			// routes mounted at "/" of a subrouter are served with and without trailing slash
+			if route != "/" {
+				route = strings.TrimSuffix(route, "/")
+			}
+			// versions are documented as servers
+			for _, version := range []string{apiversion.V1, apiversion.V2} {
+				route = strings.TrimPrefix(route, apiversion.Prefix(version))
+			}
+			assert.True(t, document.HasOperation(method, route), "%s %s is not documented", method, route)
+			return nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 51, routes)
+}
+
+func TestNewRouter_OpenAPI(t *testing.T) {
+	document, err := openapi.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter(context.Background(), Services{}, nil, Middlewares{}, Handlers{OpenAPI: document})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, document.ValidateResponse(http.MethodGet, "/openapi.json", w.Code, w.Header(), w.Body.Bytes()))
+	assert.JSONEq(t, string(document.JSON()), w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<script src="/docs/docs.js"></script>`)
+	assert.NotContains(t, w.Body.String(), "https://", "the page loads no third party scripts")
+	assert.Equal(t, "default-src 'self'; style-src 'self' 'unsafe-inline'", w.Header().Get("Content-Security-Policy"))
+	assert.NoError(t, document.ValidateResponse(http.MethodGet, "/docs", w.Code, w.Header(), w.Body.Bytes()))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/docs.js", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `fetch("/openapi.json")`)
+	assert.NoError(t, document.ValidateResponse(http.MethodGet, "/docs/docs.js", w.Code, w.Header(), w.Body.Bytes()))
+}
+
+func TestNewRouter_Versions(t *testing.T) {
+	disabled := middleware.Feature(func() bool { return false })
+	router := NewRouter(context.Background(), Services{}, nil, Middlewares{Optional: map[string]middleware.Middleware{"/tca": disabled}}, Handlers{})
+	testTable := []struct {
+		name              string
+		path              string
+		expectedVersion   string
+		expectedLink      string
+		expectedErrorBody string
+	}{
+		{
+			name:              "V1",
+			path:              "/v1/tca",
+			expectedVersion:   apiversion.V1,
+			expectedErrorBody: `{"error":"feature is disabled"}`,
+		},
+		{
+			name:              "V2",
+			path:              "/v2/tca",
+			expectedVersion:   apiversion.V2,
+			expectedErrorBody: `{"error":{"code":"not_found","message":"feature is disabled"}}`,
+		},
+		{
+			name:              "UNVERSIONED",
+			path:              "/tca",
+			expectedVersion:   apiversion.V1,
+			expectedLink:      `</v1/tca>; rel="successor-version"`,
+			expectedErrorBody: `{"error":"feature is disabled"}`,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+				assert.Equal(t, http.StatusNotFound, w.Code)
+				assert.Equal(t, test.expectedVersion, w.Header().Get(apiversion.Header))
+				assert.Equal(t, test.expectedLink, w.Header().Get("Link"))
+				assert.Equal(t, test.expectedLink != "", w.Header().Get("Deprecation") == "true")
+				assert.Equal(t, test.expectedErrorBody, w.Body.String())
+			},
+		)
+	}
+}
+
+func TestNewRouter_Protobuf(t *testing.T) {
+	router := NewRouter(context.Background(), Services{}, nil, Middlewares{Global: []middleware.Middleware{middleware.Negotiate}}, Handlers{})
+	testTable := []struct {
+		name         string
+		method       string
+		path         string
+		header       string
+		expectedCode int
+	}{
+		{
+			name:         "NOT ACCEPTABLE",
+			method:       http.MethodGet,
+			path:         "/v1/orders/lifecycle/6f1c2a9e-1d3b-4b8e-9f3a-2c4d5e6f7a8b",
+			header:       "Accept",
+			expectedCode: http.StatusNotAcceptable,
+		},
+		{
+			name:         "UNSUPPORTED MEDIA TYPE",
+			method:       http.MethodPost,
+			path:         "/v1/orders/lifecycle",
+			header:       "Content-Type",
+			expectedCode: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:         "OPTIONAL ROUTE",
+			method:       http.MethodGet,
+			path:         "/v1/tca",
+			header:       "Accept",
+			expectedCode: http.StatusNotAcceptable,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				r := httptest.NewRequest(test.method, test.path, nil)
+				r.Header.Set(test.header, "application/x-protobuf")
+				router.ServeHTTP(w, r)
+
+				assert.Equal(t, test.expectedCode, w.Code)
+				assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			},
+		)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/plinkplenk/test-vortex/internal/api/handlers"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	tcaService "github.com/plinkplenk/test-vortex/internal/tca/service"
+	"log/slog"
+	"net/http"
+)
+
+func TCARouter(
+	ctx context.Context, version string, tcaService tcaService.TCAService, logger *slog.Logger, middlewares Middlewares,
+) http.Handler {
+	tcaHandler := handlers.NewTCAHandler(tcaService, logger)
+	r := chi.NewRouter()
+	reader := with(middlewares.Read, auth.RoleReader)
+	r.With(reader...).Get("/", tcaHandler.GetReports(ctx))
+	return r
+}
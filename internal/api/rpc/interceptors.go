@@ -0,0 +1,235 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authService "github.com/plinkplenk/test-vortex/internal/auth/service"
+	"github.com/plinkplenk/test-vortex/internal/ratelimit"
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+)
+
+// Metadata keys, gRPC metadata keys are lower case HTTP header names
+const (
+	APIKeyMetadata        = "x-api-key"
+	AuthorizationMetadata = "authorization"
+	RequestIDMetadata     = "x-request-id"
+)
+
+const bearerPrefix = "bearer "
+
+var (
+	ErrRateLimited  = errors.New("rate limit exceeded")
+	ErrShuttingDown = errors.New("server is shutting down")
+)
+
+// Authenticator returns identity of the caller, ok is false if the caller didn't provide credentials it checks
+type Authenticator func(ctx context.Context) (identity auth.Identity, ok bool, err error)
+
+// CertificateAuthenticator identifies callers by verified client certificate,
+// unmapped certificates are passed to the next authenticator like in middleware.ClientCertificate
+func CertificateAuthenticator(service authService.CertificateService, logger *slog.Logger) Authenticator {
+	return func(ctx context.Context) (auth.Identity, bool, error) {
+		p, ok := peer.FromContext(ctx)
+		if !ok {
+			return auth.Identity{}, false, nil
+		}
+		tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+		if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+			return auth.Identity{}, false, nil
+		}
+		certificate := tlsInfo.State.VerifiedChains[0][0]
+		identity, err := service.Identify(ctx, certificate)
+		if err != nil {
+			logger.DebugContext(
+				ctx, "Client certificate is not mapped", "SUBJECT", auth.CertificateSubject(certificate), "error", err,
+			)
+			return auth.Identity{}, false, nil
+		}
+		return identity, true, nil
+	}
+}
+
+// BearerAuthenticator verifies bearer token from authorization metadata
+func BearerAuthenticator(service authService.JWTService) Authenticator {
+	return func(ctx context.Context) (auth.Identity, bool, error) {
+		value := firstMetadata(ctx, AuthorizationMetadata)
+		if len(value) < len(bearerPrefix) || !strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+			return auth.Identity{}, false, nil
+		}
+		identity, err := service.Verify(ctx, strings.TrimSpace(value[len(bearerPrefix):]))
+		return identity, err == nil, err
+	}
+}
+
+// APIKeyAuthenticator authenticates by x-api-key metadata, calls without a valid key are rejected
+func APIKeyAuthenticator(service authService.AuthService) Authenticator {
+	return func(ctx context.Context) (auth.Identity, bool, error) {
+		identity, err := service.Authenticate(ctx, firstMetadata(ctx, APIKeyMetadata))
+		return identity, err == nil, err
+	}
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	values := metadata.ValueFromIncomingContext(ctx, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// interceptor prepares context of every call: request id, caller identity, role and rate limit.
+// It is shared by unary and stream calls
+type interceptor struct {
+	authenticators  []Authenticator
+	requireIdentity bool
+	read            *ratelimit.Limiter
+	write           *ratelimit.Limiter
+	logger          *slog.Logger
+	now             func() time.Time
+}
+
+// prepare returns context of the call or status error rejecting it.
+// Rejected calls get context with the request id, so their logs have it
+func (i interceptor) prepare(ctx context.Context, fullMethod string) (context.Context, error) {
+	id := firstMetadata(ctx, RequestIDMetadata)
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+	ctx = requestid.WithID(ctx, id)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadata, id))
+	for _, authenticate := range i.authenticators {
+		identity, ok, err := authenticate(ctx)
+		if err != nil {
+			i.logger.DebugContext(ctx, "Unauthenticated call", "METHOD", fullMethod, "error", err)
+			return ctx, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if ok {
+			ctx = auth.WithIdentity(ctx, identity)
+			break
+		}
+	}
+	if _, ok := auth.IdentityFromContext(ctx); !ok && i.requireIdentity {
+		i.logger.DebugContext(ctx, "Unauthenticated call", "METHOD", fullMethod)
+		return ctx, status.Error(codes.Unauthenticated, auth.ErrNotAuthenticated.Error())
+	}
+	role := methodRole(fullMethod)
+	if err := auth.AuthorizeRole(ctx, role); err != nil {
+		return ctx, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err := i.allow(ctx, role); err != nil {
+		return ctx, err
+	}
+	return ctx, nil
+}
+
+// allow takes a token of the read or write limiter of the caller, see middleware.RateLimit.
+// Calls are limited by identity or by peer ip for anonymous callers
+func (i interceptor) allow(ctx context.Context, role auth.Role) error {
+	limiter, kind := i.read, "read"
+	if role != auth.RoleReader {
+		limiter, kind = i.write, "write"
+	}
+	if limiter == nil || !limiter.Enabled() {
+		return nil
+	}
+	key := "ip:" + peerIP(ctx)
+	if identity, ok := auth.IdentityFromContext(ctx); ok {
+		key = "key:" + identity.Name
+	}
+	decision := limiter.Allow(kind+"\n"+key, i.now())
+	if !decision.Allowed {
+		i.logger.DebugContext(ctx, "Rate limit exceeded", "KEY", key)
+		return status.Error(codes.ResourceExhausted, ErrRateLimited.Error())
+	}
+	return nil
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+func (i interceptor) log(ctx context.Context, fullMethod string, start time.Time, err error) {
+	i.logger.InfoContext(
+		ctx,
+		"Incoming call",
+		"METHOD", fullMethod,
+		"CODE", status.Code(err).String(),
+		"DURATION", fmt.Sprintf("%dms", time.Since(start).Milliseconds()),
+	)
+}
+
+func (i interceptor) Unary(
+	ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
+) (any, error) {
+	start := time.Now()
+	callCtx, err := i.prepare(ctx, info.FullMethod)
+	if err != nil {
+		i.log(callCtx, info.FullMethod, start, err)
+		return nil, err
+	}
+	response, err := handler(callCtx, request)
+	i.log(callCtx, info.FullMethod, start, err)
+	return response, err
+}
+
+func (i interceptor) Stream(
+	server any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	start := time.Now()
+	ctx, err := i.prepare(stream.Context(), info.FullMethod)
+	if err != nil {
+		i.log(ctx, info.FullMethod, start, err)
+		return err
+	}
+	wrapped := &serverStream{ServerStream: stream, ctx: ctx}
+	if info.IsClientStream {
+		// every received message after the first one takes a token, so streaming ingestion is limited like unary calls
+		wrapped.allow = func() error { return i.allow(ctx, methodRole(info.FullMethod)) }
+	}
+	err = handler(server, wrapped)
+	i.log(ctx, info.FullMethod, start, err)
+	return err
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	// allow is called before every received message except the first one, which is allowed with the call
+	allow    func() error
+	received int
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.received++
+	if s.allow != nil && s.received > 1 {
+		return s.allow()
+	}
+	return nil
+}
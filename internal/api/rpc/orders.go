@@ -0,0 +1,298 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc/ordersv1"
+	"github.com/plinkplenk/test-vortex/internal/api/schemas"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	order "github.com/plinkplenk/test-vortex/internal/orders/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
+	"log/slog"
+)
+
+var (
+	ErrOrderBookNotFound      = errors.New("order book not found")
+	ErrOrderHistoryNotFound   = errors.New("order history not found")
+	ErrLabelOrPairNotProvided = errors.New("label and pair not provided")
+	errInternal               = status.Error(codes.Internal, "something went wrong")
+)
+
+type OrdersServer struct {
+	ordersv1.UnimplementedOrdersServiceServer
+	orderService order.OrdersService
+	updates      *order.BookUpdates
+	logger       *slog.Logger
+}
+
+// NewOrdersServer creates server of OrdersService, updates are the books saved through orderService
+func NewOrdersServer(orderService order.OrdersService, updates *order.BookUpdates, logger *slog.Logger) *OrdersServer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &OrdersServer{orderService: orderService, updates: updates, logger: logger}
+}
+
+func (s *OrdersServer) GetOrderBook(
+	ctx context.Context, request *ordersv1.GetOrderBookRequest,
+) (*ordersv1.GetOrderBookResponse, error) {
+	depth, err := s.orderService.GetOrderBook(ctx, request.GetExchangeName(), request.GetPair())
+	if len(depth) == 0 {
+		if err != nil {
+			s.logger.DebugContext(
+				ctx,
+				"error while trying to get order book",
+				"exchangeName", request.GetExchangeName(),
+				"pair", request.GetPair(),
+				"error", err,
+			)
+		}
+		return nil, status.Error(codes.NotFound, ErrOrderBookNotFound.Error())
+	}
+	return OrderBookToProto(depth), nil
+}
+
+func (s *OrdersServer) SaveOrderBook(
+	ctx context.Context, request *ordersv1.SaveOrderBookRequest,
+) (*ordersv1.SaveOrderBookResponse, error) {
+	orderBook := OrderBookFromProto(request)
+	if err := validators.ValidateOrderBook(orderBook); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.orderService.SaveOrderBook(ctx, orderBook.ExchangeName, orderBook.Pair, orderBook.Depth); err != nil {
+		s.logger.DebugContext(
+			ctx,
+			"error while trying to save order book",
+			"exchangeName", orderBook.ExchangeName,
+			"pair", orderBook.Pair,
+			"error", err,
+		)
+		return nil, errInternal
+	}
+	return &ordersv1.SaveOrderBookResponse{}, nil
+}
+
+func (s *OrdersServer) GetOrderHistory(
+	ctx context.Context, request *ordersv1.GetOrderHistoryRequest,
+) (*ordersv1.GetOrderHistoryResponse, error) {
+	client := clientFromProto(request.GetClient())
+	if client.Label == "" || client.Pair == "" {
+		return nil, status.Error(codes.InvalidArgument, ErrLabelOrPairNotProvided.Error())
+	}
+	if err := auth.Authorize(ctx, client.ClientName, client.ExchangeName); err != nil {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	history, err := s.orderService.GetOrderHistory(ctx, client)
+	if err != nil {
+		s.logger.DebugContext(ctx, "error while trying to get order history", "client", client, "error", err)
+		return nil, errInternal
+	}
+	if history == nil {
+		return nil, status.Error(codes.NotFound, ErrOrderHistoryNotFound.Error())
+	}
+	return OrderHistoryToProto(history), nil
+}
+
+func (s *OrdersServer) SaveOrder(
+	ctx context.Context, request *ordersv1.SaveOrderRequest,
+) (*ordersv1.SaveOrderResponse, error) {
+	if err := s.saveOrder(ctx, request); err != nil {
+		return nil, err
+	}
+	return &ordersv1.SaveOrderResponse{}, nil
+}
+
+func (s *OrdersServer) IngestOrders(stream ordersv1.OrdersService_IngestOrdersServer) error {
+	var saved uint64
+	for {
+		request, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stream.SendAndClose(&ordersv1.IngestOrdersResponse{Saved: saved})
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.saveOrder(stream.Context(), request); err != nil {
+			// the number of saved orders is reported in the error, the client resends orders after them
+			return status.Errorf(status.Code(err), "order %d: %s", saved, status.Convert(err).Message())
+		}
+		saved++
+	}
+}
+
+func (s *OrdersServer) saveOrder(ctx context.Context, request *ordersv1.SaveOrderRequest) error {
+	clientHistory := ClientHistoryFromProto(request)
+	if err := validators.ValidateClientHistory(clientHistory); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := auth.Authorize(ctx, clientHistory.Client.ClientName, clientHistory.Client.ExchangeName); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err := s.orderService.SaveOrder(ctx, clientHistory.Client, &clientHistory.OrderHistory); err != nil {
+		s.logger.DebugContext(
+			ctx,
+			"error while trying to save order",
+			"client", clientHistory.Client,
+			"orderHistory", clientHistory.OrderHistory,
+			"error", err,
+		)
+		return errInternal
+	}
+	return nil
+}
+
+func (s *OrdersServer) WatchOrderBook(
+	request *ordersv1.WatchOrderBookRequest, stream ordersv1.OrdersService_WatchOrderBookServer,
+) error {
+	if request.GetExchangeName() == "" {
+		return status.Error(codes.InvalidArgument, validators.ErrExchangeNameNotProvided.Error())
+	}
+	if request.GetPair() == "" {
+		return status.Error(codes.InvalidArgument, validators.ErrPairNotProvided.Error())
+	}
+	// subscribed before the stored book is read, so books saved in between are not missed
+	updates, unsubscribe := s.updates.Subscribe(request.GetExchangeName(), request.GetPair())
+	defer unsubscribe()
+	ctx := stream.Context()
+	depth, err := s.orderService.GetOrderBook(ctx, request.GetExchangeName(), request.GetPair())
+	if err != nil {
+		s.logger.DebugContext(
+			ctx,
+			"error while trying to get order book",
+			"exchangeName", request.GetExchangeName(),
+			"pair", request.GetPair(),
+			"error", err,
+		)
+	}
+	if len(depth) > 0 {
+		if err := stream.Send(bookToProto(request.GetExchangeName(), request.GetPair(), depth)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return status.Error(codes.Unavailable, ErrShuttingDown.Error())
+			}
+			if err := stream.Send(bookToProto(update.ExchangeName, update.Pair, update.Depth)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// OrderBookToProto is the response of GetOrderBook, it is served by the http api too
+func OrderBookToProto(depth []orders.Depth) *ordersv1.GetOrderBookResponse {
+	return &ordersv1.GetOrderBookResponse{Depth: depthToProto(depth)}
+}
+
+// OrderBookFromProto is the order book of SaveOrderBook request, it is not validated
+func OrderBookFromProto(request *ordersv1.SaveOrderBookRequest) schemas.OrderBookCreate {
+	return schemas.OrderBookCreate{
+		ExchangeName: request.GetExchangeName(),
+		Pair:         request.GetPair(),
+		Depth:        depthFromProto(request.GetDepth()),
+	}
+}
+
+// OrderHistoryToProto is the response of GetOrderHistory, it is served by the http api too
+func OrderHistoryToProto(history []*orders.History) *ordersv1.GetOrderHistoryResponse {
+	response := &ordersv1.GetOrderHistoryResponse{OrderHistory: make([]*ordersv1.History, 0, len(history))}
+	for _, h := range history {
+		response.OrderHistory = append(response.OrderHistory, historyToProto(h))
+	}
+	return response
+}
+
+// ClientHistoryFromProto is the order of SaveOrder request, it is not validated
+func ClientHistoryFromProto(request *ordersv1.SaveOrderRequest) schemas.ClientHistoryCreate {
+	return schemas.ClientHistoryCreate{
+		Client:       clientFromProto(request.GetClient()),
+		OrderHistory: historyFromProto(request.GetOrderHistory()),
+	}
+}
+
+func depthToProto(depth []orders.Depth) []*ordersv1.Depth {
+	result := make([]*ordersv1.Depth, 0, len(depth))
+	for _, d := range depth {
+		result = append(result, &ordersv1.Depth{Price: d.Price, BaseQty: d.BaseQty})
+	}
+	return result
+}
+
+func depthFromProto(depth []*ordersv1.Depth) []orders.Depth {
+	if len(depth) == 0 {
+		return nil
+	}
+	result := make([]orders.Depth, 0, len(depth))
+	for _, d := range depth {
+		result = append(result, orders.Depth{Price: d.GetPrice(), BaseQty: d.GetBaseQty()})
+	}
+	return result
+}
+
+func bookToProto(exchangeName, pair string, depth []orders.Depth) *ordersv1.OrderBook {
+	return &ordersv1.OrderBook{ExchangeName: exchangeName, Pair: pair, Depth: depthToProto(depth)}
+}
+
+func clientToProto(client orders.Client) *ordersv1.Client {
+	return &ordersv1.Client{
+		ClientName:   client.ClientName,
+		ExchangeName: client.ExchangeName,
+		Label:        client.Label,
+		Pair:         client.Pair,
+	}
+}
+
+func clientFromProto(client *ordersv1.Client) orders.Client {
+	return orders.Client{
+		ClientName:   client.GetClientName(),
+		ExchangeName: client.GetExchangeName(),
+		Label:        client.GetLabel(),
+		Pair:         client.GetPair(),
+	}
+}
+
+func historyToProto(history *orders.History) *ordersv1.History {
+	result := &ordersv1.History{
+		Client:              clientToProto(history.Client),
+		Side:                history.Side,
+		Type:                history.Type,
+		BaseQty:             history.BaseQty,
+		Price:               history.Price,
+		AlgorithmNamePlaced: history.AlgorithmNamePlaced,
+		LowestSellPrc:       history.LowestSellPrc,
+		HighestBuyPrc:       history.HighestBuyPrc,
+		CommissionQuoteQty:  history.CommissionQuoteQty,
+	}
+	if !history.TimePlaced.IsZero() {
+		result.TimePlaced = timestamppb.New(history.TimePlaced)
+	}
+	return result
+}
+
+// historyFromProto ignores client of the history, client of the request is saved
+func historyFromProto(history *ordersv1.History) orders.History {
+	result := orders.History{
+		Side:                history.GetSide(),
+		Type:                history.GetType(),
+		BaseQty:             history.GetBaseQty(),
+		Price:               history.GetPrice(),
+		AlgorithmNamePlaced: history.GetAlgorithmNamePlaced(),
+		LowestSellPrc:       history.GetLowestSellPrc(),
+		HighestBuyPrc:       history.GetHighestBuyPrc(),
+		CommissionQuoteQty:  history.GetCommissionQuoteQty(),
+	}
+	if history.GetTimePlaced() != nil {
+		result.TimePlaced = history.GetTimePlaced().AsTime()
+	}
+	return result
+}
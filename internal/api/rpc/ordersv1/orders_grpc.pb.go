@@ -0,0 +1,364 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: internal/api/rpc/ordersv1/orders.proto
+
+package ordersv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	OrdersService_GetOrderBook_FullMethodName    = "/orders.v1.OrdersService/GetOrderBook"
+	OrdersService_SaveOrderBook_FullMethodName   = "/orders.v1.OrdersService/SaveOrderBook"
+	OrdersService_GetOrderHistory_FullMethodName = "/orders.v1.OrdersService/GetOrderHistory"
+	OrdersService_SaveOrder_FullMethodName       = "/orders.v1.OrdersService/SaveOrder"
+	OrdersService_IngestOrders_FullMethodName    = "/orders.v1.OrdersService/IngestOrders"
+	OrdersService_WatchOrderBook_FullMethodName  = "/orders.v1.OrdersService/WatchOrderBook"
+)
+
+// OrdersServiceClient is the client API for OrdersService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OrdersServiceClient interface {
+	GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error)
+	SaveOrderBook(ctx context.Context, in *SaveOrderBookRequest, opts ...grpc.CallOption) (*SaveOrderBookResponse, error)
+	GetOrderHistory(ctx context.Context, in *GetOrderHistoryRequest, opts ...grpc.CallOption) (*GetOrderHistoryResponse, error)
+	SaveOrder(ctx context.Context, in *SaveOrderRequest, opts ...grpc.CallOption) (*SaveOrderResponse, error)
+	// IngestOrders saves every order as it is received, the stream is aborted on the first invalid or failed order
+	// and the response has the number of orders saved before it
+	IngestOrders(ctx context.Context, opts ...grpc.CallOption) (OrdersService_IngestOrdersClient, error)
+	// WatchOrderBook sends the stored order book of the pair, then every order book saved by this server.
+	// Slow receivers skip intermediate books and get the latest one
+	WatchOrderBook(ctx context.Context, in *WatchOrderBookRequest, opts ...grpc.CallOption) (OrdersService_WatchOrderBookClient, error)
+}
+
+type ordersServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrdersServiceClient(cc grpc.ClientConnInterface) OrdersServiceClient {
+	return &ordersServiceClient{cc}
+}
+
+func (c *ordersServiceClient) GetOrderBook(ctx context.Context, in *GetOrderBookRequest, opts ...grpc.CallOption) (*GetOrderBookResponse, error) {
+	out := new(GetOrderBookResponse)
+	err := c.cc.Invoke(ctx, OrdersService_GetOrderBook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) SaveOrderBook(ctx context.Context, in *SaveOrderBookRequest, opts ...grpc.CallOption) (*SaveOrderBookResponse, error) {
+	out := new(SaveOrderBookResponse)
+	err := c.cc.Invoke(ctx, OrdersService_SaveOrderBook_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) GetOrderHistory(ctx context.Context, in *GetOrderHistoryRequest, opts ...grpc.CallOption) (*GetOrderHistoryResponse, error) {
+	out := new(GetOrderHistoryResponse)
+	err := c.cc.Invoke(ctx, OrdersService_GetOrderHistory_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) SaveOrder(ctx context.Context, in *SaveOrderRequest, opts ...grpc.CallOption) (*SaveOrderResponse, error) {
+	out := new(SaveOrderResponse)
+	err := c.cc.Invoke(ctx, OrdersService_SaveOrder_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) IngestOrders(ctx context.Context, opts ...grpc.CallOption) (OrdersService_IngestOrdersClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OrdersService_ServiceDesc.Streams[0], OrdersService_IngestOrders_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ordersServiceIngestOrdersClient{stream}
+	return x, nil
+}
+
+type OrdersService_IngestOrdersClient interface {
+	Send(*SaveOrderRequest) error
+	CloseAndRecv() (*IngestOrdersResponse, error)
+	grpc.ClientStream
+}
+
+type ordersServiceIngestOrdersClient struct {
+	grpc.ClientStream
+}
+
+func (x *ordersServiceIngestOrdersClient) Send(m *SaveOrderRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ordersServiceIngestOrdersClient) CloseAndRecv() (*IngestOrdersResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(IngestOrdersResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ordersServiceClient) WatchOrderBook(ctx context.Context, in *WatchOrderBookRequest, opts ...grpc.CallOption) (OrdersService_WatchOrderBookClient, error) {
+	stream, err := c.cc.NewStream(ctx, &OrdersService_ServiceDesc.Streams[1], OrdersService_WatchOrderBook_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ordersServiceWatchOrderBookClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type OrdersService_WatchOrderBookClient interface {
+	Recv() (*OrderBook, error)
+	grpc.ClientStream
+}
+
+type ordersServiceWatchOrderBookClient struct {
+	grpc.ClientStream
+}
+
+func (x *ordersServiceWatchOrderBookClient) Recv() (*OrderBook, error) {
+	m := new(OrderBook)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// OrdersServiceServer is the server API for OrdersService service.
+// All implementations must embed UnimplementedOrdersServiceServer
+// for forward compatibility
+type OrdersServiceServer interface {
+	GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error)
+	SaveOrderBook(context.Context, *SaveOrderBookRequest) (*SaveOrderBookResponse, error)
+	GetOrderHistory(context.Context, *GetOrderHistoryRequest) (*GetOrderHistoryResponse, error)
+	SaveOrder(context.Context, *SaveOrderRequest) (*SaveOrderResponse, error)
+	// IngestOrders saves every order as it is received, the stream is aborted on the first invalid or failed order
+	// and the response has the number of orders saved before it
+	IngestOrders(OrdersService_IngestOrdersServer) error
+	// WatchOrderBook sends the stored order book of the pair, then every order book saved by this server.
+	// Slow receivers skip intermediate books and get the latest one
+	WatchOrderBook(*WatchOrderBookRequest, OrdersService_WatchOrderBookServer) error
+	mustEmbedUnimplementedOrdersServiceServer()
+}
+
+// UnimplementedOrdersServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedOrdersServiceServer struct {
+}
+
+func (UnimplementedOrdersServiceServer) GetOrderBook(context.Context, *GetOrderBookRequest) (*GetOrderBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderBook not implemented")
+}
+func (UnimplementedOrdersServiceServer) SaveOrderBook(context.Context, *SaveOrderBookRequest) (*SaveOrderBookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveOrderBook not implemented")
+}
+func (UnimplementedOrdersServiceServer) GetOrderHistory(context.Context, *GetOrderHistoryRequest) (*GetOrderHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrderHistory not implemented")
+}
+func (UnimplementedOrdersServiceServer) SaveOrder(context.Context, *SaveOrderRequest) (*SaveOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveOrder not implemented")
+}
+func (UnimplementedOrdersServiceServer) IngestOrders(OrdersService_IngestOrdersServer) error {
+	return status.Errorf(codes.Unimplemented, "method IngestOrders not implemented")
+}
+func (UnimplementedOrdersServiceServer) WatchOrderBook(*WatchOrderBookRequest, OrdersService_WatchOrderBookServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOrderBook not implemented")
+}
+func (UnimplementedOrdersServiceServer) mustEmbedUnimplementedOrdersServiceServer() {}
+
+// UnsafeOrdersServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrdersServiceServer will
+// result in compilation errors.
+type UnsafeOrdersServiceServer interface {
+	mustEmbedUnimplementedOrdersServiceServer()
+}
+
+func RegisterOrdersServiceServer(s grpc.ServiceRegistrar, srv OrdersServiceServer) {
+	s.RegisterService(&OrdersService_ServiceDesc, srv)
+}
+
+func _OrdersService_GetOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).GetOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_GetOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).GetOrderBook(ctx, req.(*GetOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_SaveOrderBook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveOrderBookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).SaveOrderBook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_SaveOrderBook_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).SaveOrderBook(ctx, req.(*SaveOrderBookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_GetOrderHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).GetOrderHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_GetOrderHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).GetOrderHistory(ctx, req.(*GetOrderHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_SaveOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).SaveOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_SaveOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).SaveOrder(ctx, req.(*SaveOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_IngestOrders_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrdersServiceServer).IngestOrders(&ordersServiceIngestOrdersServer{stream})
+}
+
+type OrdersService_IngestOrdersServer interface {
+	SendAndClose(*IngestOrdersResponse) error
+	Recv() (*SaveOrderRequest, error)
+	grpc.ServerStream
+}
+
+type ordersServiceIngestOrdersServer struct {
+	grpc.ServerStream
+}
+
+func (x *ordersServiceIngestOrdersServer) SendAndClose(m *IngestOrdersResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ordersServiceIngestOrdersServer) Recv() (*SaveOrderRequest, error) {
+	m := new(SaveOrderRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _OrdersService_WatchOrderBook_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOrderBookRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrdersServiceServer).WatchOrderBook(m, &ordersServiceWatchOrderBookServer{stream})
+}
+
+type OrdersService_WatchOrderBookServer interface {
+	Send(*OrderBook) error
+	grpc.ServerStream
+}
+
+type ordersServiceWatchOrderBookServer struct {
+	grpc.ServerStream
+}
+
+func (x *ordersServiceWatchOrderBookServer) Send(m *OrderBook) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// OrdersService_ServiceDesc is the grpc.ServiceDesc for OrdersService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrdersService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orders.v1.OrdersService",
+	HandlerType: (*OrdersServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetOrderBook",
+			Handler:    _OrdersService_GetOrderBook_Handler,
+		},
+		{
+			MethodName: "SaveOrderBook",
+			Handler:    _OrdersService_SaveOrderBook_Handler,
+		},
+		{
+			MethodName: "GetOrderHistory",
+			Handler:    _OrdersService_GetOrderHistory_Handler,
+		},
+		{
+			MethodName: "SaveOrder",
+			Handler:    _OrdersService_SaveOrder_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "IngestOrders",
+			Handler:       _OrdersService_IngestOrders_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchOrderBook",
+			Handler:       _OrdersService_WatchOrderBook_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "internal/api/rpc/ordersv1/orders.proto",
+}
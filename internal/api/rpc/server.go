@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc/ordersv1"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	order "github.com/plinkplenk/test-vortex/internal/orders/service"
+	"github.com/plinkplenk/test-vortex/internal/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"log/slog"
+	"time"
+)
+
+// readMethods are called by readers, other methods require writer role like POST routes
+var readMethods = map[string]struct{}{
+	ordersv1.OrdersService_GetOrderBook_FullMethodName:    {},
+	ordersv1.OrdersService_GetOrderHistory_FullMethodName: {},
+	ordersv1.OrdersService_WatchOrderBook_FullMethodName:  {},
+}
+
+func methodRole(fullMethod string) auth.Role {
+	if _, ok := readMethods[fullMethod]; ok {
+		return auth.RoleReader
+	}
+	return auth.RoleWriter
+}
+
+type Params struct {
+	Orders order.OrdersService
+	// Updates are order books saved through Orders
+	Updates *order.BookUpdates
+	// Authenticators are tried in order, the first one recognizing credentials of the call sets its identity
+	Authenticators []Authenticator
+	// RequireIdentity rejects calls no authenticator recognized, it is set when any authentication is configured
+	RequireIdentity bool
+	// Read and Write limiters are shared with the HTTP api, nil limiter doesn't limit calls
+	Read  *ratelimit.Limiter
+	Write *ratelimit.Limiter
+	// TLSConfig is nil for plain connections
+	TLSConfig *tls.Config
+	Logger    *slog.Logger
+}
+
+// GracefulStop waits for calls in progress until ctx is done and then closes the remaining ones.
+// Watch streams end only when Params.Updates is closed, so it must be closed before
+func GracefulStop(ctx context.Context, server *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// NewServer creates gRPC server of OrdersService
+func NewServer(params Params) *grpc.Server {
+	logger := params.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	i := interceptor{
+		authenticators:  params.Authenticators,
+		requireIdentity: params.RequireIdentity,
+		read:            params.Read,
+		write:           params.Write,
+		logger:          logger,
+		now:             time.Now,
+	}
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(i.Unary),
+		grpc.ChainStreamInterceptor(i.Stream),
+	}
+	if params.TLSConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(params.TLSConfig)))
+	}
+	server := grpc.NewServer(options...)
+	ordersv1.RegisterOrdersServiceServer(server, NewOrdersServer(params.Orders, params.Updates, logger))
+	return server
+}
@@ -0,0 +1,502 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc/ordersv1"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	mock_auth "github.com/plinkplenk/test-vortex/internal/auth/service/mocks"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	order "github.com/plinkplenk/test-vortex/internal/orders/service"
+	mock_service "github.com/plinkplenk/test-vortex/internal/orders/service/mocks"
+	"github.com/plinkplenk/test-vortex/internal/ratelimit"
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+var loggerStub = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// dial starts server with params on in-memory listener and returns its client
+func dial(t *testing.T, params Params) ordersv1.OrdersServiceClient {
+	t.Helper()
+	_, client := start(t, params)
+	return client
+}
+
+// start starts server with params on in-memory listener and returns it with its client
+func start(t *testing.T, params Params) (*grpc.Server, ordersv1.OrdersServiceClient) {
+	t.Helper()
+	if params.Updates == nil {
+		params.Updates = order.NewBookUpdates()
+	}
+	if params.Logger == nil {
+		params.Logger = loggerStub
+	}
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(params)
+	go func() { _ = server.Serve(listener) }()
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(
+			func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) },
+		),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(
+		func() {
+			_ = conn.Close()
+			server.Stop()
+		},
+	)
+	return server, ordersv1.NewOrdersServiceClient(conn)
+}
+
+func TestOrdersServer_GetOrderBook(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockOrdersService)
+	testTable := []struct {
+		name         string
+		request      *ordersv1.GetOrderBookRequest
+		mockBehavior mockBehavior
+		expectedCode codes.Code
+		expected     []*ordersv1.Depth
+	}{
+		{
+			name:    "SUCCESS",
+			request: &ordersv1.GetOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"},
+			mockBehavior: func(s *mock_service.MockOrdersService) {
+				s.EXPECT().GetOrderBook(gomock.Any(), "binance", "BTC_USDT").Return(
+					[]orders.Depth{{Price: 100, BaseQty: 1}}, nil,
+				)
+			},
+			expectedCode: codes.OK,
+			expected:     []*ordersv1.Depth{{Price: 100, BaseQty: 1}},
+		},
+		{
+			name:    "NOT FOUND",
+			request: &ordersv1.GetOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"},
+			mockBehavior: func(s *mock_service.MockOrdersService) {
+				s.EXPECT().GetOrderBook(gomock.Any(), "binance", "BTC_USDT").Return(nil, nil)
+			},
+			expectedCode: codes.NotFound,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				orderService := mock_service.NewMockOrdersService(c)
+				test.mockBehavior(orderService)
+				client := dial(t, Params{Orders: orderService})
+
+				response, err := client.GetOrderBook(context.Background(), test.request)
+				assert.Equal(t, test.expectedCode, status.Code(err))
+				if test.expected != nil {
+					assert.Len(t, response.GetDepth(), len(test.expected))
+					for i, depth := range test.expected {
+						assert.Equal(t, depth.GetPrice(), response.GetDepth()[i].GetPrice())
+						assert.Equal(t, depth.GetBaseQty(), response.GetDepth()[i].GetBaseQty())
+					}
+				}
+			},
+		)
+	}
+}
+
+func TestOrdersServer_SaveOrderBook(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockOrdersService)
+	testTable := []struct {
+		name         string
+		request      *ordersv1.SaveOrderBookRequest
+		mockBehavior mockBehavior
+		expectedCode codes.Code
+	}{
+		{
+			name: "SUCCESS",
+			request: &ordersv1.SaveOrderBookRequest{
+				ExchangeName: "binance", Pair: "BTC_USDT", Depth: []*ordersv1.Depth{{Price: 100, BaseQty: 1}},
+			},
+			mockBehavior: func(s *mock_service.MockOrdersService) {
+				s.EXPECT().SaveOrderBook(
+					gomock.Any(), "binance", "BTC_USDT", []orders.Depth{{Price: 100, BaseQty: 1}},
+				).Return(nil)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "INVALID",
+			request:      &ordersv1.SaveOrderBookRequest{Pair: "BTC_USDT"},
+			mockBehavior: func(s *mock_service.MockOrdersService) {},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "INTERNAL",
+			request: &ordersv1.SaveOrderBookRequest{
+				ExchangeName: "binance", Pair: "BTC_USDT", Depth: []*ordersv1.Depth{{Price: 100, BaseQty: 1}},
+			},
+			mockBehavior: func(s *mock_service.MockOrdersService) {
+				s.EXPECT().SaveOrderBook(gomock.Any(), "binance", "BTC_USDT", gomock.Any()).Return(io.ErrUnexpectedEOF)
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				orderService := mock_service.NewMockOrdersService(c)
+				test.mockBehavior(orderService)
+				client := dial(t, Params{Orders: orderService})
+
+				_, err := client.SaveOrderBook(context.Background(), test.request)
+				assert.Equal(t, test.expectedCode, status.Code(err))
+			},
+		)
+	}
+}
+
+func saveOrderRequest(clientName string) *ordersv1.SaveOrderRequest {
+	return &ordersv1.SaveOrderRequest{
+		Client: &ordersv1.Client{ClientName: clientName, ExchangeName: "binance", Label: "label", Pair: "BTC_USDT"},
+		OrderHistory: &ordersv1.History{
+			Side: "buy", Type: "limit", BaseQty: 1, Price: 100, AlgorithmNamePlaced: "twap",
+		},
+	}
+}
+
+func TestOrdersServer_IngestOrders(t *testing.T) {
+	type mockBehavior func(s *mock_service.MockOrdersService)
+	testTable := []struct {
+		name          string
+		requests      []*ordersv1.SaveOrderRequest
+		mockBehavior  mockBehavior
+		expectedCode  codes.Code
+		expectedSaved uint64
+		expectedError string
+	}{
+		{
+			name:     "SUCCESS",
+			requests: []*ordersv1.SaveOrderRequest{saveOrderRequest("first"), saveOrderRequest("second")},
+			mockBehavior: func(s *mock_service.MockOrdersService) {
+				s.EXPECT().SaveOrder(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+			},
+			expectedCode:  codes.OK,
+			expectedSaved: 2,
+		},
+		{
+			name:     "INVALID ORDER",
+			requests: []*ordersv1.SaveOrderRequest{saveOrderRequest("first"), {}, saveOrderRequest("third")},
+			mockBehavior: func(s *mock_service.MockOrdersService) {
+				s.EXPECT().SaveOrder(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedCode:  codes.InvalidArgument,
+			expectedError: "order 1: ",
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				orderService := mock_service.NewMockOrdersService(c)
+				test.mockBehavior(orderService)
+				client := dial(t, Params{Orders: orderService})
+
+				stream, err := client.IngestOrders(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, request := range test.requests {
+					// the server may abort the stream before all orders are sent
+					if err := stream.Send(request); err != nil {
+						break
+					}
+				}
+				response, err := stream.CloseAndRecv()
+				assert.Equal(t, test.expectedCode, status.Code(err))
+				assert.Equal(t, test.expectedSaved, response.GetSaved())
+				if test.expectedError != "" {
+					assert.Contains(t, status.Convert(err).Message(), test.expectedError)
+				}
+			},
+		)
+	}
+}
+
+func TestOrdersServer_WatchOrderBook(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	orderService := mock_service.NewMockOrdersService(c)
+	orderService.EXPECT().GetOrderBook(gomock.Any(), "binance", "BTC_USDT").Return(
+		[]orders.Depth{{Price: 100, BaseQty: 1}}, nil,
+	)
+	orderService.EXPECT().SaveOrderBook(gomock.Any(), "binance", "BTC_USDT", gomock.Any()).Return(nil)
+	updates := order.NewBookUpdates()
+	client := dial(t, Params{Orders: order.NewPublishing(orderService, updates), Updates: updates})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchOrderBook(ctx, &ordersv1.WatchOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, stored.GetDepth()[0].GetPrice())
+
+	_, err = client.SaveOrderBook(
+		ctx,
+		&ordersv1.SaveOrderBookRequest{
+			ExchangeName: "binance", Pair: "BTC_USDT", Depth: []*ordersv1.Depth{{Price: 101, BaseQty: 2}},
+		},
+	)
+	assert.NoError(t, err)
+	update, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "binance", update.GetExchangeName())
+	assert.Equal(t, 101.0, update.GetDepth()[0].GetPrice())
+
+	invalid, err := client.WatchOrderBook(ctx, &ordersv1.WatchOrderBookRequest{ExchangeName: "binance"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = invalid.Recv()
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGracefulStop(t *testing.T) {
+	testTable := []struct {
+		name         string
+		closeUpdates bool
+		timeout      time.Duration
+	}{
+		{name: "UPDATES CLOSED", closeUpdates: true, timeout: 5 * time.Second},
+		{name: "TIMEOUT", timeout: 100 * time.Millisecond},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				orderService := mock_service.NewMockOrdersService(c)
+				orderService.EXPECT().GetOrderBook(gomock.Any(), "binance", "BTC_USDT").Return(
+					[]orders.Depth{{Price: 100, BaseQty: 1}}, nil,
+				)
+				updates := order.NewBookUpdates()
+				server, client := start(t, Params{Orders: orderService, Updates: updates})
+
+				stream, err := client.WatchOrderBook(
+					context.Background(), &ordersv1.WatchOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"},
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := stream.Recv(); err != nil {
+					t.Fatal(err)
+				}
+
+				if test.closeUpdates {
+					updates.Close()
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), test.timeout)
+				defer cancel()
+				begin := time.Now()
+				GracefulStop(ctx, server)
+				assert.Less(t, time.Since(begin), 5*time.Second, "open watch stream doesn't block stop")
+
+				_, err = stream.Recv()
+				assert.Equal(t, codes.Unavailable, status.Code(err))
+			},
+		)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	reader := auth.Identity{
+		Name: "reader", Clients: []string{"client"}, Exchanges: []string{auth.Wildcard}, Roles: []auth.Role{auth.RoleReader},
+	}
+	type mockBehavior func(a *mock_auth.MockAuthService, s *mock_service.MockOrdersService)
+	testTable := []struct {
+		name         string
+		key          string
+		call         func(ctx context.Context, client ordersv1.OrdersServiceClient) error
+		mockBehavior mockBehavior
+		expectedCode codes.Code
+	}{
+		{
+			name: "NO KEY",
+			call: func(ctx context.Context, client ordersv1.OrdersServiceClient) error {
+				_, err := client.GetOrderBook(ctx, &ordersv1.GetOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"})
+				return err
+			},
+			mockBehavior: func(a *mock_auth.MockAuthService, s *mock_service.MockOrdersService) {
+				a.EXPECT().Authenticate(gomock.Any(), "").Return(auth.Identity{}, auth.ErrKeyNotProvided)
+			},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name: "READER",
+			key:  "secret",
+			call: func(ctx context.Context, client ordersv1.OrdersServiceClient) error {
+				_, err := client.GetOrderBook(ctx, &ordersv1.GetOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"})
+				return err
+			},
+			mockBehavior: func(a *mock_auth.MockAuthService, s *mock_service.MockOrdersService) {
+				a.EXPECT().Authenticate(gomock.Any(), "secret").Return(reader, nil)
+				s.EXPECT().GetOrderBook(gomock.Any(), "binance", "BTC_USDT").Return(
+					[]orders.Depth{{Price: 100, BaseQty: 1}}, nil,
+				)
+			},
+			expectedCode: codes.OK,
+		},
+		{
+			name: "READER WRITES",
+			key:  "secret",
+			call: func(ctx context.Context, client ordersv1.OrdersServiceClient) error {
+				_, err := client.SaveOrder(ctx, saveOrderRequest("client"))
+				return err
+			},
+			mockBehavior: func(a *mock_auth.MockAuthService, s *mock_service.MockOrdersService) {
+				a.EXPECT().Authenticate(gomock.Any(), "secret").Return(reader, nil)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name: "OUT OF SCOPE",
+			key:  "secret",
+			call: func(ctx context.Context, client ordersv1.OrdersServiceClient) error {
+				_, err := client.GetOrderHistory(
+					ctx, &ordersv1.GetOrderHistoryRequest{Client: saveOrderRequest("other").GetClient()},
+				)
+				return err
+			},
+			mockBehavior: func(a *mock_auth.MockAuthService, s *mock_service.MockOrdersService) {
+				a.EXPECT().Authenticate(gomock.Any(), "secret").Return(reader, nil)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				c := gomock.NewController(t)
+				defer c.Finish()
+
+				authService := mock_auth.NewMockAuthService(c)
+				orderService := mock_service.NewMockOrdersService(c)
+				test.mockBehavior(authService, orderService)
+				client := dial(
+					t, Params{Orders: orderService, Authenticators: []Authenticator{APIKeyAuthenticator(authService)}},
+				)
+
+				ctx := context.Background()
+				if test.key != "" {
+					ctx = metadata.AppendToOutgoingContext(ctx, APIKeyMetadata, test.key)
+				}
+				assert.Equal(t, test.expectedCode, status.Code(test.call(ctx, client)))
+			},
+		)
+	}
+}
+
+func TestServer_RequireIdentity(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	orderService := mock_service.NewMockOrdersService(c)
+	orderService.EXPECT().GetOrderBook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	// authenticator of optional credentials like bearer tokens doesn't reject calls without them
+	optional := func(context.Context) (auth.Identity, bool, error) { return auth.Identity{}, false, nil }
+	client := dial(t, Params{Orders: orderService, Authenticators: []Authenticator{optional}, RequireIdentity: true})
+
+	_, err := client.GetOrderBook(
+		context.Background(), &ordersv1.GetOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"},
+	)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServer_RejectedCallLog(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	orderService := mock_service.NewMockOrdersService(c)
+	var logs bytes.Buffer
+	client := dial(
+		t,
+		Params{
+			Orders:          orderService,
+			RequireIdentity: true,
+			Logger:          slog.New(requestid.NewLogHandler(slog.NewJSONHandler(&logs, nil))),
+		},
+	)
+
+	id := requestid.New()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), RequestIDMetadata, id)
+	_, err := client.GetOrderBook(ctx, &ordersv1.GetOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	stream, err := client.WatchOrderBook(ctx, &ordersv1.WatchOrderBookRequest{ExchangeName: "binance", Pair: "BTC_USDT"})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	var calls int
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		if record["msg"] == "Incoming call" {
+			calls++
+			assert.Equal(t, id, record["request_id"], "rejected calls are logged with their request id")
+		}
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	c := gomock.NewController(t)
+	defer c.Finish()
+
+	orderService := mock_service.NewMockOrdersService(c)
+	orderService.EXPECT().SaveOrder(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	write := ratelimit.NewLimiter(ratelimit.Limit{Rate: 0.001, Burst: 2})
+	client := dial(t, Params{Orders: orderService, Write: write})
+
+	stream, err := client.IngestOrders(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if err := stream.Send(saveOrderRequest("client")); err != nil {
+			break
+		}
+	}
+	response, err := stream.CloseAndRecv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "every ingested order takes a token")
+	assert.Nil(t, response)
+
+	_, err = client.SaveOrder(context.Background(), saveOrderRequest("client"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -1,6 +1,10 @@
 package schemas
 
-import "github.com/plinkplenk/test-vortex/internal/orders"
+import (
+	"github.com/google/uuid"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"time"
+)
 
 type ClientHistoryCreate struct {
 	Client       orders.Client  `json:"client"`
@@ -12,3 +16,35 @@ type OrderBookCreate struct {
 	Pair         string         `json:"pair"`
 	Depth        []orders.Depth `json:"depth"`
 }
+
+type MarkoutsCompute struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+type LifecycleOrderCreate struct {
+	// OrderID is optional, it is generated if not provided
+	OrderID uuid.UUID     `json:"orderId"`
+	Client  orders.Client `json:"client"`
+	Side    string        `json:"side"`
+	Type    string        `json:"type"`
+	BaseQty float64       `json:"baseQty"`
+	Price   float64       `json:"price"`
+}
+
+type EventCreate struct {
+	Status orders.Status `json:"status"`
+	Reason string        `json:"reason"`
+	// Time is optional, current time is used if not provided
+	Time time.Time `json:"time"`
+}
+
+type FillCreate struct {
+	// FillID is optional, it is generated if not provided
+	FillID             uuid.UUID `json:"fillId"`
+	BaseQty            float64   `json:"baseQty"`
+	Price              float64   `json:"price"`
+	CommissionQuoteQty float64   `json:"commissionQuoteQty"`
+	// Time is optional, current time is used if not provided
+	Time time.Time `json:"time"`
+}
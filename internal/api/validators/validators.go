@@ -2,10 +2,22 @@ package validators
 
 import (
 	"errors"
+	"fmt"
 	"github.com/plinkplenk/test-vortex/internal/api/schemas"
+	"github.com/plinkplenk/test-vortex/internal/candles"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	"github.com/plinkplenk/test-vortex/internal/markouts"
 	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/plinkplenk/test-vortex/internal/replay"
+	"github.com/plinkplenk/test-vortex/internal/tca"
+	"math"
+	"strings"
+	"time"
 )
 
+// MaxCandles is the maximum number of candles that can be requested at once
+const MaxCandles = 10000
+
 var (
 	ErrExchangeNameNotProvided = errors.New("exchange not provided")
 
@@ -14,6 +26,18 @@ var (
 
 	ErrClientNotProvided       = errors.New("client not provided")
 	ErrOrderHistoryNotProvided = errors.New("order history not provided")
+
+	ErrInvalidSide        = errors.New("side must be buy or sell")
+	ErrInvalidQty         = errors.New("base quantity must be positive")
+	ErrInvalidPrice       = errors.New("price must be positive")
+	ErrNegativePrice      = errors.New("price must not be negative")
+	ErrInvalidEventStatus = errors.New("event status must be cancelled, rejected or expired")
+	ErrNegativeCommission = errors.New("commission must not be negative")
+
+	ErrInvalidInterval  = errors.New("interval must be a positive whole number of seconds")
+	ErrInvalidTimeRange = errors.New("from must be before to")
+	ErrTooManyCandles   = fmt.Errorf("too many candles requested, maximum is %d", MaxCandles)
+	ErrInvalidSpeed     = errors.New("speed must be a number not less than 0")
 )
 
 func ValidateOrderBook(ob schemas.OrderBookCreate) error {
@@ -40,3 +64,108 @@ func ValidateClientHistory(ch schemas.ClientHistoryCreate) error {
 	}
 	return nil
 }
+
+func ValidateCandlesQuery(q candles.Query) error {
+	if len(q.ExchangeName) == 0 {
+		return ErrExchangeNameNotProvided
+	}
+	if len(q.Pair) == 0 {
+		return ErrPairNotProvided
+	}
+	if q.Interval < time.Second || q.Interval%time.Second != 0 {
+		return ErrInvalidInterval
+	}
+	if !q.From.Before(q.To) {
+		return ErrInvalidTimeRange
+	}
+	if q.To.Sub(q.From)/q.Interval > MaxCandles {
+		return ErrTooManyCandles
+	}
+	return nil
+}
+
+func ValidateTCAQuery(q tca.Query) error {
+	if !q.From.Before(q.To) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
+
+func ValidateMarkoutsCompute(mc schemas.MarkoutsCompute) error {
+	if !mc.From.Before(mc.To) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
+
+func ValidateMarkoutsQuery(q markouts.Query) error {
+	if !q.From.Before(q.To) {
+		return ErrInvalidTimeRange
+	}
+	return nil
+}
+
+// ValidateExportQuery checks time range and side if they are provided, export is unbounded without them
+func ValidateExportQuery(q export.Query) error {
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return ErrInvalidTimeRange
+	}
+	if q.Side != "" && q.Side != orders.SideBuy && q.Side != orders.SideSell {
+		return ErrInvalidSide
+	}
+	return nil
+}
+
+func ValidateReplayQuery(q replay.Query) error {
+	if len(q.ExchangeName) == 0 {
+		return ErrExchangeNameNotProvided
+	}
+	if len(q.Pair) == 0 {
+		return ErrPairNotProvided
+	}
+	if !q.From.Before(q.To) {
+		return ErrInvalidTimeRange
+	}
+	if !(q.Speed >= 0) || math.IsInf(q.Speed, 1) {
+		return ErrInvalidSpeed
+	}
+	return nil
+}
+
+func ValidateLifecycleOrder(o schemas.LifecycleOrderCreate) error {
+	if o.Client == (orders.Client{}) {
+		return ErrClientNotProvided
+	}
+	if !strings.EqualFold(o.Side, orders.SideBuy) && !strings.EqualFold(o.Side, orders.SideSell) {
+		return ErrInvalidSide
+	}
+	if o.BaseQty <= 0 {
+		return ErrInvalidQty
+	}
+	if o.Price < 0 {
+		return ErrNegativePrice
+	}
+	return nil
+}
+
+func ValidateEvent(e schemas.EventCreate) error {
+	switch e.Status {
+	case orders.StatusCancelled, orders.StatusRejected, orders.StatusExpired:
+		return nil
+	default:
+		return ErrInvalidEventStatus
+	}
+}
+
+func ValidateFill(f schemas.FillCreate) error {
+	if f.BaseQty <= 0 {
+		return ErrInvalidQty
+	}
+	if f.Price <= 0 {
+		return ErrInvalidPrice
+	}
+	if f.CommissionQuoteQty < 0 {
+		return ErrNegativeCommission
+	}
+	return nil
+}
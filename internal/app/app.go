@@ -2,53 +2,97 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/plinkplenk/test-vortex/internal/api/middleware"
+	"github.com/plinkplenk/test-vortex/internal/api/openapi"
 	"github.com/plinkplenk/test-vortex/internal/api/routes"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc"
+	authRepository "github.com/plinkplenk/test-vortex/internal/auth/repository"
+	authService "github.com/plinkplenk/test-vortex/internal/auth/service"
+	candlesRepository "github.com/plinkplenk/test-vortex/internal/candles/repository"
+	candle "github.com/plinkplenk/test-vortex/internal/candles/service"
 	"github.com/plinkplenk/test-vortex/internal/config"
+	exportRepository "github.com/plinkplenk/test-vortex/internal/export/repository"
+	exportService "github.com/plinkplenk/test-vortex/internal/export/service"
+	"github.com/plinkplenk/test-vortex/internal/health"
+	healthRepository "github.com/plinkplenk/test-vortex/internal/health/repository"
+	markoutsRepository "github.com/plinkplenk/test-vortex/internal/markouts/repository"
+	markoutService "github.com/plinkplenk/test-vortex/internal/markouts/service"
+	"github.com/plinkplenk/test-vortex/internal/metrics"
+	"github.com/plinkplenk/test-vortex/internal/migrate"
+	migrateRepository "github.com/plinkplenk/test-vortex/internal/migrate/repository"
+	migrateService "github.com/plinkplenk/test-vortex/internal/migrate/service"
 	ordersRepository "github.com/plinkplenk/test-vortex/internal/orders/repository"
 	order "github.com/plinkplenk/test-vortex/internal/orders/service"
+	pnlService "github.com/plinkplenk/test-vortex/internal/pnl/service"
+	"github.com/plinkplenk/test-vortex/internal/ratelimit"
+	replayRepository "github.com/plinkplenk/test-vortex/internal/replay/repository"
+	replayService "github.com/plinkplenk/test-vortex/internal/replay/service"
+	tcaRepository "github.com/plinkplenk/test-vortex/internal/tca/repository"
+	tcaService "github.com/plinkplenk/test-vortex/internal/tca/service"
+	"github.com/plinkplenk/test-vortex/internal/tlsconfig"
+	"github.com/plinkplenk/test-vortex/internal/tracing"
+	"github.com/plinkplenk/test-vortex/migrations"
+	"google.golang.org/grpc"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
+	"sync"
 	"time"
 )
 
 func setupRouters(
-	orderService order.OrdersService, logger *slog.Logger, middlewares ...middleware.Middleware,
+	ctx context.Context,
+	services routes.Services,
+	logger *slog.Logger,
+	middlewares routes.Middlewares,
+	handlers routes.Handlers,
 ) http.Handler {
-	return routes.NewRouter(orderService, logger, middlewares...)
+	return routes.NewRouter(ctx, services, logger, middlewares, handlers)
 }
 
-func connectToClickhouse(clickhouseCfg config.Clickhouse, debug bool) (clickhouse.Conn, error) {
+var compressionMethods = map[string]clickhouse.CompressionMethod{
+	"none":    clickhouse.CompressionNone,
+	"lz4":     clickhouse.CompressionLZ4,
+	"zstd":    clickhouse.CompressionZSTD,
+	"gzip":    clickhouse.CompressionGZIP,
+	"deflate": clickhouse.CompressionDeflate,
+	"br":      clickhouse.CompressionBrotli,
+}
+
+// connectToClickhouse opens connection pool, tlsConfig is nil for plain connections
+func connectToClickhouse(clickhouseCfg config.Clickhouse, tlsConfig *tls.Config, debug bool) (clickhouse.Conn, error) {
 	addr := fmt.Sprintf("%s:%s", clickhouseCfg.Host, clickhouseCfg.Port)
 	conn, err := clickhouse.Open(
 		&clickhouse.Options{
 			Addr: []string{addr},
 			Auth: clickhouse.Auth{
-				Database: "default",
+				Database: clickhouseCfg.Database,
 				Username: clickhouseCfg.User,
 				Password: clickhouseCfg.Password,
 			},
+			TLS:   tlsConfig,
 			Debug: debug,
 			Debugf: func(format string, v ...any) {
 				log.Printf(format+"\n", v...)
 			},
 			Settings: clickhouse.Settings{
-				"max_execution_time": 60,
+				"max_execution_time": int(clickhouseCfg.MaxExecutionTime.Seconds()),
 			},
 			Compression: &clickhouse.Compression{
-				Method: clickhouse.CompressionLZ4,
+				Method: compressionMethods[clickhouseCfg.Compression],
 			},
-			DialTimeout:          time.Second * 10,
-			MaxOpenConns:         300,
-			MaxIdleConns:         10,
-			ConnMaxLifetime:      30 * time.Second,
+			DialTimeout:          clickhouseCfg.DialTimeout,
+			MaxOpenConns:         clickhouseCfg.MaxOpenConns,
+			MaxIdleConns:         clickhouseCfg.MaxIdleConns,
+			ConnMaxLifetime:      clickhouseCfg.ConnMaxLifetime,
 			ConnOpenStrategy:     clickhouse.ConnOpenInOrder,
-			BlockBufferSize:      10,
-			MaxCompressionBuffer: 10240,
+			BlockBufferSize:      uint8(clickhouseCfg.BlockBufferSize),
+			MaxCompressionBuffer: clickhouseCfg.MaxCompressionBuffer,
 			ClientInfo: clickhouse.ClientInfo{
 				Products: []struct {
 					Name    string
@@ -65,55 +109,457 @@ func connectToClickhouse(clickhouseCfg config.Clickhouse, debug bool) (clickhous
 	return conn, nil
 }
 
+// job is a background task running until ctx is done
+type job interface {
+	Run(ctx context.Context)
+}
+
+func setupAuth(
+	authCfg config.Auth, chConn clickhouse.Conn, timeout time.Duration, logger *slog.Logger,
+) (middleware.Middleware, rpc.Authenticator, authService.RefreshJob, error) {
+	repository := authRepository.NewClickHouseRepository(chConn)
+	if authCfg.KeysFile != "" {
+		repository = authRepository.NewFileRepository(authCfg.KeysFile)
+	}
+	service, err := authService.New(context.Background(), repository, timeout)
+	if err != nil {
+		return nil, nil, authService.RefreshJob{}, fmt.Errorf("loading api keys: %w", err)
+	}
+	authMiddleware := middleware.NewAuthMiddleware(service, logger)
+	refreshJob := authService.NewRefreshJob(service, authCfg.RefreshInterval, logger)
+	return authMiddleware.Authenticate, rpc.APIKeyAuthenticator(service), refreshJob, nil
+}
+
+func setupSigning(
+	signingCfg config.Signing, refreshInterval, timeout time.Duration, logger *slog.Logger,
+) (middleware.Signature, authService.RefreshJob, error) {
+	service, err := authService.NewSigningService(
+		context.Background(),
+		authRepository.NewSigningFileRepository(signingCfg.KeysFile),
+		timeout,
+		signingCfg.MaxSkew,
+	)
+	if err != nil {
+		return middleware.Signature{}, authService.RefreshJob{}, fmt.Errorf("loading signing keys: %w", err)
+	}
+	refreshJob := authService.NewRefreshJob(service, refreshInterval, logger)
+	return middleware.NewSignatureMiddleware(service, int64(signingCfg.MaxBody), logger), refreshJob, nil
+}
+
+func setupJWT(
+	jwtCfg config.JWT, refreshInterval, timeout time.Duration, logger *slog.Logger,
+) (middleware.Middleware, rpc.Authenticator, authService.RefreshJob, error) {
+	service, err := authService.NewJWTService(
+		context.Background(),
+		authRepository.NewVerificationKeysFileRepository(jwtCfg.KeysFile),
+		timeout,
+		jwtCfg.Issuer,
+		jwtCfg.Audience,
+		jwtCfg.Leeway,
+	)
+	if err != nil {
+		return nil, nil, authService.RefreshJob{}, fmt.Errorf("loading jwt verification keys: %w", err)
+	}
+	jwtMiddleware := middleware.NewJWTMiddleware(service, logger)
+	refreshJob := authService.NewRefreshJob(service, refreshInterval, logger)
+	return jwtMiddleware.Authenticate, rpc.BearerAuthenticator(service), refreshJob, nil
+}
+
+func setupHealth(chConn clickhouse.Conn, timeout time.Duration) (*health.Health, error) {
+	expectedVersion, err := migrations.LatestVersion()
+	if err != nil {
+		return nil, err
+	}
+	repository := healthRepository.NewClickHouseRepository(chConn)
+	h := health.New(timeout)
+	h.Register("clickhouse", health.PingCheck(repository))
+	h.Register("migrations", health.MigrationCheck(repository, expectedVersion))
+	h.Register("clickhouse_pool", health.PoolCheck(chConn))
+	return h, nil
+}
+
+// NewMigrator connects to ClickHouse and creates migrator of the embedded migrations.
+// Returned function closes the connection
+func NewMigrator(params Params) (migrateService.Migrator, func() error, error) {
+	chTLS, _, err := clickhouseTLS(params.Config.Clickhouse.TLS)
+	if err != nil {
+		return nil, nil, err
+	}
+	chConn, err := connectToClickhouse(params.Config.Clickhouse, chTLS, params.Debug)
+	if err != nil {
+		return nil, nil, err
+	}
+	migrator, err := setupMigrator(chConn, params.Logger)
+	if err != nil {
+		return nil, nil, errors.Join(err, chConn.Close())
+	}
+	return migrator, chConn.Close, nil
+}
+
+// NewExporter connects to ClickHouse and creates export service of order history.
+// Returned function closes the connection
+func NewExporter(params Params) (exportService.ExportService, func() error, error) {
+	chTLS, _, err := clickhouseTLS(params.Config.Clickhouse.TLS)
+	if err != nil {
+		return nil, nil, err
+	}
+	chConn, err := connectToClickhouse(params.Config.Clickhouse, chTLS, params.Debug)
+	if err != nil {
+		return nil, nil, err
+	}
+	service := exportService.New(exportRepository.NewClickHouseRepository(chConn), params.Config.Export.Timeout)
+	return service, chConn.Close, nil
+}
+
+// NewReplayer connects to ClickHouse and creates replay service of order books and orders.
+// Returned function closes the connection
+func NewReplayer(params Params) (replayService.ReplayService, func() error, error) {
+	chTLS, _, err := clickhouseTLS(params.Config.Clickhouse.TLS)
+	if err != nil {
+		return nil, nil, err
+	}
+	chConn, err := connectToClickhouse(params.Config.Clickhouse, chTLS, params.Debug)
+	if err != nil {
+		return nil, nil, err
+	}
+	service := replayService.New(
+		replayRepository.NewClickHouseRepository(chConn),
+		params.Config.Server.Timeout,
+		params.Config.Replay.Chunk,
+	)
+	return service, chConn.Close, nil
+}
+
+// NewOrdersRepository connects to ClickHouse and creates repository of order books and order history
+// for commands writing to it directly. Returned function closes the connection
+func NewOrdersRepository(params Params) (ordersRepository.Repository, func() error, error) {
+	chTLS, _, err := clickhouseTLS(params.Config.Clickhouse.TLS)
+	if err != nil {
+		return nil, nil, err
+	}
+	chConn, err := connectToClickhouse(params.Config.Clickhouse, chTLS, params.Debug)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ordersRepository.NewClickHouseRepository(chConn), chConn.Close, nil
+}
+
+func setupMigrator(chConn clickhouse.Conn, logger *slog.Logger) (migrateService.Migrator, error) {
+	embedded, err := migrate.Load(migrations.FS)
+	if err != nil {
+		return nil, err
+	}
+	return migrateService.New(migrateRepository.NewClickHouseRepository(chConn), embedded, logger), nil
+}
+
 type App struct {
 	env    config.ENV
 	debug  bool
 	config config.Config
 	dbConn clickhouse.Conn
 	server *http.Server
-	logger *slog.Logger
+	// grpcServer is nil if gRPC is disabled
+	grpcServer *grpc.Server
+	// bookUpdates are closed on stop, so order book watch streams end
+	bookUpdates *order.BookUpdates
+	logger      *slog.Logger
+	jobs        []job
+	// jobsCtx is done when background jobs started in Run must stop
+	jobsCtx  context.Context
+	stopJobs context.CancelFunc
+	// stopStreams ends replay and export streams, Shutdown waits for them otherwise
+	stopStreams context.CancelFunc
+	// stopTracing flushes spans that are not exported yet
+	stopTracing func(ctx context.Context) error
+	health      *health.Health
+	// reloadMu guards config and serializes reloads
+	reloadMu   sync.Mutex
+	logLevel   *slog.LevelVar
+	features   *features
+	limiters   limiters
+	refreshers []authService.Refresher
 }
 
 type Params struct {
 	Config config.Config
 	Logger *slog.Logger
-	Debug  bool
+	// LogLevel of Logger handler, it is set by Reload if not nil
+	LogLevel *slog.LevelVar
+	Debug    bool
 }
 
 func New(params Params) (*App, error) {
-	chConn, err := connectToClickhouse(params.Config.Clickhouse, params.Debug)
+	stopTracing, err := tracing.Setup(
+		tracing.Params{
+			Exporter:    params.Config.Tracing.Exporter,
+			File:        params.Config.Tracing.File,
+			SampleRatio: params.Config.Tracing.SampleRatio,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("setting up tracing: %w", err)
+	}
+	chTLS, chCertificates, err := clickhouseTLS(params.Config.Clickhouse.TLS)
+	if err != nil {
+		return nil, err
+	}
+	chConn, err := connectToClickhouse(params.Config.Clickhouse, chTLS, params.Debug)
+	if err != nil {
+		return nil, err
+	}
+	serverTLSConfig, serverCertificates, err := serverTLS(params.Config.Server.TLS)
 	if err != nil {
 		return nil, err
 	}
-	orderService := order.New(ordersRepository.NewClickHouseRepository(chConn), params.Config.Server.Timeout)
+	if params.Config.Migrations.OnStartup {
+		migrator, err := setupMigrator(chConn, params.Logger)
+		if err != nil {
+			return nil, err
+		}
+		if err := migrator.Up(context.Background()); err != nil {
+			return nil, fmt.Errorf("applying migrations: %w", err)
+		}
+	}
+	appMetrics := metrics.New()
+	appMetrics.RegisterClickHouse(chConn)
+	orderRepository := ordersRepository.NewClickHouseRepository(chConn)
+	bookUpdates := order.NewBookUpdates()
+	orderService := order.NewPublishing(
+		order.NewInstrumented(order.New(orderRepository, params.Config.Server.Timeout), appMetrics),
+		bookUpdates,
+	)
+	lifecycleService := order.NewLifecycleService(
+		ordersRepository.NewClickHouseLifecycleRepository(chConn),
+		params.Config.Server.Timeout,
+	)
+	markoutsService := markoutService.New(
+		markoutsRepository.NewClickHouseRepository(chConn),
+		params.Config.Server.Timeout,
+		params.Config.Markouts.Horizons,
+	)
+	toggles := newFeatures(params.Config.Features)
+	jobs := []job{
+		markoutService.NewJob(
+			markoutsService,
+			params.Config.Markouts.Interval,
+			params.Config.Markouts.Horizons,
+			toggles.enabled(func(f config.Features) bool { return f.Markouts }),
+			params.Logger,
+		),
+	}
 	loggerMiddleware := middleware.NewLoggerMiddleware(params.Logger)
-	handler := setupRouters(orderService, params.Logger, loggerMiddleware.Log)
-	server := setupServer(params.Config.Server.Port, handler)
+	metricsMiddleware := middleware.NewMetricsMiddleware(appMetrics)
+	middlewares := routes.Middlewares{
+		Global: []middleware.Middleware{
+			middleware.Trace,
+			middleware.RequestID,
+			loggerMiddleware.Log,
+			metricsMiddleware.Observe,
+			middleware.Negotiate,
+		},
+		Optional: map[string]middleware.Middleware{
+			"/candles":  middleware.Feature(toggles.enabled(func(f config.Features) bool { return f.Candles })),
+			"/pnl":      middleware.Feature(toggles.enabled(func(f config.Features) bool { return f.PnL })),
+			"/tca":      middleware.Feature(toggles.enabled(func(f config.Features) bool { return f.TCA })),
+			"/markouts": middleware.Feature(toggles.enabled(func(f config.Features) bool { return f.Markouts })),
+			"/export":   middleware.Feature(toggles.enabled(func(f config.Features) bool { return f.Export })),
+			"/replay":   middleware.Feature(toggles.enabled(func(f config.Features) bool { return f.Replay })),
+		},
+	}
+	var refreshers []authService.Refresher
+	// authenticators of gRPC calls are in the order of HTTP auth middlewares
+	var authenticators []rpc.Authenticator
+	for _, certificates := range []*tlsconfig.Reloader{chCertificates, serverCertificates} {
+		if certificates != nil {
+			refreshJob := authService.NewRefreshJob(certificates, params.Config.Auth.RefreshInterval, params.Logger)
+			jobs = append(jobs, refreshJob)
+			refreshers = append(refreshers, refreshJob)
+		}
+	}
+	if params.Config.Signing.KeysFile != "" {
+		signatureMiddleware, refreshJob, err := setupSigning(
+			params.Config.Signing, params.Config.Auth.RefreshInterval, params.Config.Server.Timeout, params.Logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+		middlewares.Global = append(middlewares.Global, signatureMiddleware.Verify)
+		if params.Config.Signing.Required {
+			middlewares.Ingest = append(middlewares.Ingest, signatureMiddleware.Require)
+		}
+		jobs = append(jobs, refreshJob)
+		refreshers = append(refreshers, refreshJob)
+	}
+	if params.Config.Server.TLS.IdentitiesFile != "" {
+		certificateMiddleware, authenticator, refreshJob, err := setupCertificateAuth(
+			params.Config.Server.TLS.IdentitiesFile,
+			params.Config.Auth.RefreshInterval,
+			params.Config.Server.Timeout,
+			params.Logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+		middlewares.Global = append(middlewares.Global, certificateMiddleware)
+		authenticators = append(authenticators, authenticator)
+		jobs = append(jobs, refreshJob)
+		refreshers = append(refreshers, refreshJob)
+	}
+	if params.Config.JWT.KeysFile != "" {
+		jwtMiddleware, authenticator, refreshJob, err := setupJWT(
+			params.Config.JWT, params.Config.Auth.RefreshInterval, params.Config.Server.Timeout, params.Logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+		middlewares.Global = append(middlewares.Global, jwtMiddleware)
+		authenticators = append(authenticators, authenticator)
+		jobs = append(jobs, refreshJob)
+		refreshers = append(refreshers, refreshJob)
+	}
+	if params.Config.Auth.Enabled {
+		authMiddleware, authenticator, refreshJob, err := setupAuth(
+			params.Config.Auth, chConn, params.Config.Server.Timeout, params.Logger,
+		)
+		if err != nil {
+			return nil, err
+		}
+		middlewares.Global = append(middlewares.Global, authMiddleware)
+		authenticators = append(authenticators, authenticator)
+		jobs = append(jobs, refreshJob)
+		refreshers = append(refreshers, refreshJob)
+	}
+	// credentials are optional for every authenticator, so anonymous requests are rejected after all of them
+	requireIdentity := params.Config.Signing.KeysFile != "" || len(authenticators) > 0
+	if requireIdentity {
+		middlewares.Global = append(middlewares.Global, middleware.RequireIdentity)
+	}
+	readLimiter := ratelimit.NewLimiter(params.Config.RateLimit.Read.Limit())
+	writeLimiter := ratelimit.NewLimiter(params.Config.RateLimit.Write.Limit())
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(
+		readLimiter,
+		writeLimiter,
+		params.Config.RateLimit.KeyBy,
+		params.Logger,
+	)
+	middlewares.Read = append(middlewares.Read, rateLimitMiddleware.Read)
+	middlewares.Write = append(middlewares.Write, rateLimitMiddleware.Write)
+	appHealth, err := setupHealth(chConn, params.Config.Server.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	apiDocument, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+	metricsFeature := middleware.Feature(toggles.enabled(func(f config.Features) bool { return f.Metrics }))
+	streamsCtx, stopStreams := context.WithCancel(context.Background())
+	handler := setupRouters(
+		streamsCtx,
+		routes.Services{
+			Orders:    orderService,
+			Lifecycle: lifecycleService,
+			Candles: candle.New(
+				candlesRepository.NewClickHouseRepository(chConn),
+				params.Config.Server.Timeout,
+				params.Config.Candles.Intervals,
+			),
+			PnL:      pnlService.New(orderRepository, params.Config.Server.Timeout),
+			TCA:      tcaService.New(tcaRepository.NewClickHouseRepository(chConn), params.Config.Server.Timeout),
+			Markouts: markoutsService,
+			Export: exportService.New(
+				exportRepository.NewClickHouseRepository(chConn),
+				params.Config.Export.Timeout,
+			),
+			Replay: replayService.New(
+				replayRepository.NewClickHouseRepository(chConn),
+				params.Config.Server.Timeout,
+				params.Config.Replay.Chunk,
+			),
+		},
+		params.Logger,
+		middlewares,
+		routes.Handlers{Metrics: metricsFeature(appMetrics.Handler()), Health: appHealth, OpenAPI: apiDocument},
+	)
+	server := setupServer(params.Config.Server.Addr(), handler, serverTLSConfig)
+	var grpcServer *grpc.Server
+	if params.Config.GRPC.Enabled() {
+		grpcServer = rpc.NewServer(
+			rpc.Params{
+				Orders:          orderService,
+				Updates:         bookUpdates,
+				Authenticators:  authenticators,
+				RequireIdentity: requireIdentity,
+				Read:            readLimiter,
+				Write:           writeLimiter,
+				TLSConfig:       serverTLSConfig,
+				Logger:          params.Logger,
+			},
+		)
+	}
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
 	return &App{
-		env:    params.Config.ENV,
-		dbConn: chConn,
-		logger: params.Logger,
-		config: params.Config,
-		server: server,
-		debug:  params.Debug,
+		env:         params.Config.ENV,
+		dbConn:      chConn,
+		logger:      params.Logger,
+		config:      params.Config,
+		server:      server,
+		grpcServer:  grpcServer,
+		bookUpdates: bookUpdates,
+		debug:       params.Debug,
+		jobs:        jobs,
+		jobsCtx:     jobsCtx,
+		stopJobs:    stopJobs,
+		stopStreams: stopStreams,
+		stopTracing: stopTracing,
+		health:      appHealth,
+		logLevel:    params.LogLevel,
+		features:    toggles,
+		limiters:    limiters{read: readLimiter, write: writeLimiter},
+		refreshers:  refreshers,
 	}, nil
 }
 
-func setupServer(port string, handler http.Handler) *http.Server {
+// setupServer creates server, tlsConfig is nil for plain HTTP
+func setupServer(addr string, handler http.Handler, tlsConfig *tls.Config) *http.Server {
 	return &http.Server{
-		Addr:    port,
-		Handler: handler,
+		Addr:      addr,
+		Handler:   handler,
+		TLSConfig: tlsConfig,
 	}
 }
 
 func (a *App) Run() error {
+	for _, j := range a.jobs {
+		go j.Run(a.jobsCtx)
+	}
+	if a.grpcServer != nil {
+		listener, err := net.Listen("tcp", a.config.GRPC.Addr())
+		if err != nil {
+			return fmt.Errorf("listening gRPC: %w", err)
+		}
+		a.logger.Info("Running gRPC server", "address", a.config.GRPC.Addr())
+		go func() {
+			if err := a.grpcServer.Serve(listener); err != nil {
+				a.logger.Error("gRPC server stopped", "error", err)
+			}
+		}()
+	}
+	if a.server.TLSConfig != nil {
+		a.logger.Info("Running server", "address", a.server.Addr, "tls", true)
+		// certificates are provided by TLSConfig
+		return a.server.ListenAndServeTLS("", "")
+	}
 	a.logger.Info("Running server", "address", a.server.Addr)
 	return a.server.ListenAndServe()
 }
 
 // Stop
-// gracefully shuts down app with 30 seconds time out
+// gracefully shuts down app with 30 seconds time out.
+// Readiness fails for the drain delay before the server stops accepting connections
 func (a *App) Stop() {
+	a.health.SetDraining()
+	time.Sleep(a.config.Server.DrainDelay)
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 	go func() {
@@ -122,10 +568,20 @@ func (a *App) Stop() {
 			log.Fatal("graceful shutdown timeout\nForcing exit")
 		}
 	}()
+	a.stopJobs()
+	a.stopStreams()
 	if err := a.server.Shutdown(shutdownCtx); err != nil {
 		slog.Error("Error while trying to shutdown server", "error", err)
 	}
+	if a.grpcServer != nil {
+		// watch streams wait for books until updates are closed
+		a.bookUpdates.Close()
+		rpc.GracefulStop(shutdownCtx, a.grpcServer)
+	}
 	if err := a.dbConn.Close(); err != nil {
 		slog.Error("Error on db connection close", "error", err)
 	}
+	if err := a.stopTracing(shutdownCtx); err != nil {
+		slog.Error("Error while flushing spans", "error", err)
+	}
 }
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/config"
+	"github.com/plinkplenk/test-vortex/internal/ratelimit"
+	"sync/atomic"
+)
+
+// features holds feature toggles replaced on reload
+type features struct {
+	current atomic.Pointer[config.Features]
+}
+
+func newFeatures(initial config.Features) *features {
+	f := &features{}
+	f.current.Store(&initial)
+	return f
+}
+
+// enabled returns func reporting the current value of the toggle selected by get
+func (f *features) enabled(get func(config.Features) bool) func() bool {
+	return func() bool {
+		return get(*f.current.Load())
+	}
+}
+
+type limiters struct {
+	read  *ratelimit.Limiter
+	write *ratelimit.Limiter
+}
+
+// Reload applies reload fields of cfg to the running app and reloads api, signing and jwt keys.
+// Changes of other fields are logged and take effect after restart
+func (a *App) Reload(ctx context.Context, cfg config.Config) {
+	a.reloadMu.Lock()
+	defer a.reloadMu.Unlock()
+	changes := config.Diff(a.config, cfg)
+	a.config = a.config.WithReloaded(cfg)
+
+	if a.logLevel != nil {
+		a.logLevel.Set(a.config.Log.SlogLevel())
+	}
+	a.limiters.read.SetLimit(a.config.RateLimit.Read.Limit())
+	a.limiters.write.SetLimit(a.config.RateLimit.Write.Limit())
+	toggles := a.config.Features
+	a.features.current.Store(&toggles)
+	for _, refresher := range a.refreshers {
+		if err := refresher.Refresh(ctx); err != nil {
+			a.logger.ErrorContext(ctx, "Error while reloading keys", "error", err)
+		}
+	}
+
+	for _, change := range changes {
+		if change.Reload {
+			a.logger.InfoContext(ctx, "Config changed", "key", change.Key, "old", change.Old, "new", change.New)
+		} else {
+			a.logger.WarnContext(
+				ctx, "Config change requires restart", "key", change.Key, "old", change.Old, "new", change.New,
+			)
+		}
+	}
+	a.logger.InfoContext(ctx, "Config reloaded", "changes", len(changes))
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/api/middleware"
+	"github.com/plinkplenk/test-vortex/internal/api/rpc"
+	authRepository "github.com/plinkplenk/test-vortex/internal/auth/repository"
+	authService "github.com/plinkplenk/test-vortex/internal/auth/service"
+	"github.com/plinkplenk/test-vortex/internal/config"
+	"github.com/plinkplenk/test-vortex/internal/tlsconfig"
+	"log/slog"
+	"time"
+)
+
+// clickhouseTLS returns nil config and reloader if TLS to ClickHouse is disabled
+func clickhouseTLS(tlsCfg config.ClickhouseTLS) (*tls.Config, *tlsconfig.Reloader, error) {
+	if !tlsCfg.Enabled {
+		return nil, nil, nil
+	}
+	minVersion, err := tlsconfig.ParseVersion(tlsCfg.MinVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	certificates, err := tlsconfig.New(
+		tlsconfig.Files{CertFile: tlsCfg.CertFile, KeyFile: tlsCfg.KeyFile, CAFile: tlsCfg.CAFile},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading clickhouse tls files: %w", err)
+	}
+	return certificates.ClientConfig(tlsCfg.ServerName, minVersion, tlsCfg.InsecureSkipVerify), &certificates, nil
+}
+
+// serverTLS returns nil config and reloader if TLS is disabled
+func serverTLS(tlsCfg config.ServerTLS) (*tls.Config, *tlsconfig.Reloader, error) {
+	if !tlsCfg.Enabled() {
+		return nil, nil, nil
+	}
+	minVersion, err := tlsconfig.ParseVersion(tlsCfg.MinVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+	clientAuth, err := tlsconfig.ParseClientAuth(tlsCfg.ClientAuth)
+	if err != nil {
+		return nil, nil, err
+	}
+	certificates, err := tlsconfig.New(
+		tlsconfig.Files{CertFile: tlsCfg.CertFile, KeyFile: tlsCfg.KeyFile, CAFile: tlsCfg.ClientCAFile},
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading server tls files: %w", err)
+	}
+	return certificates.ServerConfig(minVersion, clientAuth), &certificates, nil
+}
+
+func setupCertificateAuth(
+	identitiesFile string, refreshInterval, timeout time.Duration, logger *slog.Logger,
+) (middleware.Middleware, rpc.Authenticator, authService.RefreshJob, error) {
+	service, err := authService.NewCertificateService(
+		context.Background(),
+		authRepository.NewCertificateFileRepository(identitiesFile),
+		timeout,
+	)
+	if err != nil {
+		return nil, nil, authService.RefreshJob{}, fmt.Errorf("loading certificate identities: %w", err)
+	}
+	certificateMiddleware := middleware.NewClientCertificateMiddleware(service, logger)
+	refreshJob := authService.NewRefreshJob(service, refreshInterval, logger)
+	return certificateMiddleware.Authenticate, rpc.CertificateAuthenticator(service, logger), refreshJob, nil
+}
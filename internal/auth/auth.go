@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+)
+
+// Wildcard in Clients or Exchanges allows any client or exchange
+const Wildcard = "*"
+
+var (
+	ErrKeyNotProvided = errors.New("api key not provided")
+	ErrInvalidKey     = errors.New("invalid api key")
+	ErrForbidden      = errors.New("client or exchange is out of api key scope")
+	// ErrNotAuthenticated is returned for requests without credentials when authentication is configured
+	ErrNotAuthenticated = errors.New("request is not authenticated")
+)
+
+// Key is an api key as it is stored, the raw key itself is never stored
+type Key struct {
+	Name      string   `json:"name"`
+	KeyHash   string   `json:"keyHash"`
+	Clients   []string `json:"clients"`
+	Exchanges []string `json:"exchanges"`
+	// Roles are reader if the field is missing, writers and admins are listed explicitly
+	Roles []string `json:"roles"`
+}
+
+// Identity is the authenticated caller
+type Identity struct {
+	Name      string
+	Clients   []string
+	Exchanges []string
+	Roles     []Role
+	// Signed is true if the request was authenticated by HMAC signature
+	Signed bool
+}
+
+// KeyRoles parses roles of api or signing key or certificate identity, those without roles are readers
+func KeyRoles(roles []string) []Role {
+	if len(roles) == 0 {
+		return []Role{RoleReader}
+	}
+	return ParseRoles(roles)
+}
+
+// HashKey returns hex encoded SHA-256 of the raw api key.
+// Keys are random high entropy strings, so a fast hash is enough to store them
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func allows(scope []string, value string) bool {
+	return slices.Contains(scope, Wildcard) || slices.Contains(scope, value)
+}
+
+// Allows reports whether identity can access data of the client on the exchange
+func (i Identity) Allows(clientName, exchangeName string) bool {
+	return allows(i.Clients, clientName) && allows(i.Exchanges, exchangeName)
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns identity stored by authentication middleware,
+// ok is false when authentication is disabled
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// Authorize returns ErrForbidden if identity in ctx is not allowed to access the client on the exchange.
+// Requests without identity are allowed because authentication is disabled for them
+func Authorize(ctx context.Context, clientName, exchangeName string) error {
+	identity, ok := IdentityFromContext(ctx)
+	if ok && !identity.Allows(clientName, exchangeName) {
+		return ErrForbidden
+	}
+	return nil
+}
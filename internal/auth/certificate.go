@@ -0,0 +1,24 @@
+package auth
+
+import (
+	"crypto/x509"
+	"errors"
+)
+
+var ErrUnknownCertificate = errors.New("client certificate subject is not mapped to an identity")
+
+// CertificateIdentity maps subject of verified client certificates to an identity
+type CertificateIdentity struct {
+	// Subject is distinguished name of the certificate like "CN=gateway-1,O=Acme", see CertificateSubject
+	Subject   string   `json:"subject"`
+	Name      string   `json:"name"`
+	Clients   []string `json:"clients"`
+	Exchanges []string `json:"exchanges"`
+	// Roles are reader if the field is missing like roles of api keys
+	Roles []string `json:"roles"`
+}
+
+// CertificateSubject is RFC 2253 distinguished name of the certificate subject
+func CertificateSubject(certificate *x509.Certificate) string {
+	return certificate.Subject.String()
+}
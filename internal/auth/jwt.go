@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid bearer token")
+	ErrNoKeys       = errors.New("no verification keys found")
+)
+
+// TokenMethods are accepted JWT signing methods, symmetric methods are not accepted
+var TokenMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+// Claims are JWT claims issued by the identity provider
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles     []string `json:"roles"`
+	Clients   []string `json:"clients"`
+	Exchanges []string `json:"exchanges"`
+}
+
+// Identity returns identity of the token subject
+func (c Claims) Identity() Identity {
+	return Identity{
+		Name:      c.Subject,
+		Clients:   c.Clients,
+		Exchanges: c.Exchanges,
+		Roles:     ParseRoles(c.Roles),
+	}
+}
+
+// VerificationKeys are public keys used to verify JWT signatures
+type VerificationKeys struct {
+	// ByID are keys with "kid", tokens with matching "kid" header are verified only by that key
+	ByID map[string]crypto.PublicKey
+	// All keys are tried for tokens without known "kid"
+	All []crypto.PublicKey
+}
+
+// Keyfunc selects verification key by token "kid" header
+func (k VerificationKeys) Keyfunc(token *jwt.Token) (any, error) {
+	if kid, ok := token.Header["kid"].(string); ok {
+		if key, ok := k.ByID[kid]; ok {
+			return key, nil
+		}
+	}
+	set := jwt.VerificationKeySet{}
+	for _, key := range k.All {
+		set.Keys = append(set.Keys, key)
+	}
+	return set, nil
+}
+
+func (k *VerificationKeys) add(kid string, key crypto.PublicKey) {
+	if kid != "" {
+		k.ByID[kid] = key
+	}
+	k.All = append(k.All, key)
+}
+
+// ParseVerificationKeys parses JWKS document or PEM encoded public keys and certificates
+func ParseVerificationKeys(content []byte) (VerificationKeys, error) {
+	keys := VerificationKeys{ByID: make(map[string]crypto.PublicKey)}
+	var err error
+	if json.Valid(content) {
+		err = keys.parseJWKS(content)
+	} else {
+		err = keys.parsePEM(content)
+	}
+	if err != nil {
+		return VerificationKeys{}, err
+	}
+	if len(keys.All) == 0 {
+		return VerificationKeys{}, ErrNoKeys
+	}
+	return keys, nil
+}
+
+func (k *VerificationKeys) parsePEM(content []byte) error {
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			return nil
+		}
+		var key crypto.PublicKey
+		var err error
+		switch block.Type {
+		case "PUBLIC KEY":
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		case "RSA PUBLIC KEY":
+			key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		case "CERTIFICATE":
+			var cert *x509.Certificate
+			cert, err = x509.ParseCertificate(block.Bytes)
+			if err == nil {
+				key = cert.PublicKey
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("parsing %s: %w", block.Type, err)
+		}
+		k.add("", key)
+	}
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *VerificationKeys) parseJWKS(content []byte) error {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(content, &set); err != nil {
+		return err
+	}
+	for _, j := range set.Keys {
+		if j.Use != "" && j.Use != "sig" {
+			continue
+		}
+		key, err := j.publicKey()
+		if err != nil {
+			return fmt.Errorf("parsing jwk %q: %w", j.Kid, err)
+		}
+		if key != nil {
+			k.add(j.Kid, key)
+		}
+	}
+	return nil
+}
+
+func decodeInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// publicKey returns nil for key types that can't verify accepted methods
+func (j jwk) publicKey() (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err := decodeInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch j.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := decodeInt(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", j.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, nil
+	}
+}
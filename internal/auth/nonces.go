@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// NonceCache remembers nonces for ttl to reject replayed requests.
+// ttl must be at least twice the allowed clock skew, older requests are rejected by timestamp
+type NonceCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	nonces    map[string]time.Time
+	lastSweep time.Time
+}
+
+func NewNonceCache(ttl time.Duration) *NonceCache {
+	return &NonceCache{ttl: ttl, nonces: make(map[string]time.Time)}
+}
+
+// Add stores nonce of the key and returns false if it is already stored
+func (c *NonceCache) Add(keyID, nonce string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Sub(c.lastSweep) > c.ttl {
+		for k, expires := range c.nonces {
+			if now.After(expires) {
+				delete(c.nonces, k)
+			}
+		}
+		c.lastSweep = now
+	}
+	k := keyID + "\n" + nonce
+	if expires, ok := c.nonces[k]; ok && !now.After(expires) {
+		return false
+	}
+	c.nonces[k] = now.Add(c.ttl)
+	return true
+}
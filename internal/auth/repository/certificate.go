@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"os"
+)
+
+type CertificateRepository interface {
+	// GetCertificateIdentities returns identities of client certificate subjects
+	GetCertificateIdentities(ctx context.Context) ([]auth.CertificateIdentity, error)
+}
+
+type certificateIdentitiesFile struct {
+	Identities []auth.CertificateIdentity `json:"identities"`
+}
+
+type certificateFileRepository struct {
+	path string
+}
+
+// NewCertificateFileRepository reads identities from JSON file of the form {"identities": [{"subject": ..., "clients": [...]}]}
+func NewCertificateFileRepository(path string) CertificateRepository {
+	return certificateFileRepository{
+		path: path,
+	}
+}
+
+func (r certificateFileRepository) GetCertificateIdentities(_ context.Context) ([]auth.CertificateIdentity, error) {
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+	var file certificateIdentitiesFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	return file.Identities, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+)
+
+type clickHouseRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseRepository(db clickhouse.Conn) Repository {
+	return clickHouseRepository{
+		db: db,
+	}
+}
+
+func (r clickHouseRepository) GetKeys(ctx context.Context) ([]auth.Key, error) {
+	query := `SELECT name, key_hash, clients, exchanges, roles FROM api_keys FINAL WHERE NOT disabled`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []auth.Key
+	for rows.Next() {
+		var key auth.Key
+		if err := rows.Scan(&key.Name, &key.KeyHash, &key.Clients, &key.Exchanges, &key.Roles); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"os"
+)
+
+type keysFile struct {
+	Keys []auth.Key `json:"keys"`
+}
+
+type fileRepository struct {
+	path string
+}
+
+// NewFileRepository reads api keys from JSON file of the form {"keys": [{"name": ..., "keyHash": ...}]}.
+// The file is read on every GetKeys call, so changes are picked up on the next refresh
+func NewFileRepository(path string) Repository {
+	return fileRepository{
+		path: path,
+	}
+}
+
+func (r fileRepository) GetKeys(_ context.Context) ([]auth.Key, error) {
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+	var file keysFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	return file.Keys, nil
+}
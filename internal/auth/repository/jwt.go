@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"os"
+)
+
+type VerificationKeysRepository interface {
+	// GetVerificationKeys returns public keys used to verify bearer tokens
+	GetVerificationKeys(ctx context.Context) (auth.VerificationKeys, error)
+}
+
+type verificationKeysFileRepository struct {
+	path string
+}
+
+// NewVerificationKeysFileRepository reads verification keys from JWKS document or PEM file
+// with public keys or certificates, the format is detected by content
+func NewVerificationKeysFileRepository(path string) VerificationKeysRepository {
+	return verificationKeysFileRepository{
+		path: path,
+	}
+}
+
+func (r verificationKeysFileRepository) GetVerificationKeys(_ context.Context) (auth.VerificationKeys, error) {
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return auth.VerificationKeys{}, err
+	}
+	return auth.ParseVerificationKeys(content)
+}
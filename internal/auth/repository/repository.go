@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+)
+
+type Repository interface {
+	// GetKeys returns all active api keys
+	GetKeys(ctx context.Context) ([]auth.Key, error)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"os"
+)
+
+type SigningRepository interface {
+	// GetSigningKeys returns all signing keys with their active secrets
+	GetSigningKeys(ctx context.Context) ([]auth.SigningKey, error)
+}
+
+type signingKeysFile struct {
+	Keys []auth.SigningKey `json:"keys"`
+}
+
+type signingFileRepository struct {
+	path string
+}
+
+// NewSigningFileRepository reads signing keys from JSON file of the form {"keys": [{"keyId": ..., "secrets": [...]}]}.
+// Secrets can't be hashed because they are needed to compute signatures, so they are kept out of the database
+func NewSigningFileRepository(path string) SigningRepository {
+	return signingFileRepository{
+		path: path,
+	}
+}
+
+func (r signingFileRepository) GetSigningKeys(_ context.Context) ([]auth.SigningKey, error) {
+	content, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+	var file signingKeysFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+	return file.Keys, nil
+}
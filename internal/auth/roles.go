@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+type Role string
+
+// Roles are ordered, every role includes permissions of the previous ones
+const (
+	RoleReader Role = "reader"
+	RoleWriter Role = "writer"
+	RoleAdmin  Role = "admin"
+)
+
+var roleLevels = map[Role]int{
+	RoleReader: 1,
+	RoleWriter: 2,
+	RoleAdmin:  3,
+}
+
+var ErrInsufficientRole = errors.New("role is not allowed to call this route")
+
+// ParseRoles keeps known roles and drops everything else
+func ParseRoles(roles []string) []Role {
+	var result []Role
+	for _, role := range roles {
+		if _, ok := roleLevels[Role(role)]; ok && !slices.Contains(result, Role(role)) {
+			result = append(result, Role(role))
+		}
+	}
+	return result
+}
+
+// HasRole reports whether identity has the role or a role that includes it
+func (i Identity) HasRole(required Role) bool {
+	for _, role := range i.Roles {
+		if roleLevels[role] >= roleLevels[required] {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizeRole returns ErrInsufficientRole if identity in ctx doesn't have the role.
+// Requests without identity are allowed because authentication is disabled for them
+func AuthorizeRole(ctx context.Context, role Role) error {
+	identity, ok := IdentityFromContext(ctx)
+	if ok && !identity.HasRole(role) {
+		return ErrInsufficientRole
+	}
+	return nil
+}
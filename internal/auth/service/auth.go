@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authRepository "github.com/plinkplenk/test-vortex/internal/auth/repository"
+	"sync/atomic"
+	"time"
+)
+
+//go:generate mockgen -source=auth.go -destination=mocks/mock.go
+type AuthService interface {
+	// Authenticate returns identity of the raw api key or auth.ErrInvalidKey
+	Authenticate(ctx context.Context, key string) (auth.Identity, error)
+	// Refresh reloads api keys from the repository
+	Refresh(ctx context.Context) error
+}
+
+type authService struct {
+	repository authRepository.Repository
+	timeout    time.Duration
+	// identities by key hash, swapped as a whole on refresh
+	identities *atomic.Pointer[map[string]auth.Identity]
+}
+
+// New creates auth service and loads api keys
+func New(ctx context.Context, repository authRepository.Repository, timeout time.Duration) (AuthService, error) {
+	s := authService{
+		repository: repository,
+		timeout:    timeout,
+		identities: &atomic.Pointer[map[string]auth.Identity]{},
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s authService) Authenticate(_ context.Context, key string) (auth.Identity, error) {
+	if key == "" {
+		return auth.Identity{}, auth.ErrKeyNotProvided
+	}
+	identity, ok := (*s.identities.Load())[auth.HashKey(key)]
+	if !ok {
+		return auth.Identity{}, auth.ErrInvalidKey
+	}
+	return identity, nil
+}
+
+func (s authService) Refresh(ctx context.Context) error {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	keys, err := s.repository.GetKeys(c)
+	if err != nil {
+		return err
+	}
+	identities := make(map[string]auth.Identity, len(keys))
+	for _, key := range keys {
+		identities[key.KeyHash] = auth.Identity{
+			Name:      key.Name,
+			Clients:   key.Clients,
+			Exchanges: key.Exchanges,
+			Roles:     auth.KeyRoles(key.Roles),
+		}
+	}
+	s.identities.Store(&identities)
+	return nil
+}
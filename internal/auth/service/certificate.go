@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authRepository "github.com/plinkplenk/test-vortex/internal/auth/repository"
+	"sync/atomic"
+	"time"
+)
+
+//go:generate mockgen -source=certificate.go -destination=mocks/certificate.go
+type CertificateService interface {
+	// Identify returns identity mapped to subject of the verified client certificate
+	Identify(ctx context.Context, certificate *x509.Certificate) (auth.Identity, error)
+	// Refresh reloads certificate identities from the repository
+	Refresh(ctx context.Context) error
+}
+
+type certificateService struct {
+	repository authRepository.CertificateRepository
+	timeout    time.Duration
+	identities *atomic.Pointer[map[string]auth.CertificateIdentity]
+}
+
+// NewCertificateService creates certificate service and loads certificate identities
+func NewCertificateService(
+	ctx context.Context, repository authRepository.CertificateRepository, timeout time.Duration,
+) (CertificateService, error) {
+	s := certificateService{
+		repository: repository,
+		timeout:    timeout,
+		identities: &atomic.Pointer[map[string]auth.CertificateIdentity]{},
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s certificateService) Identify(_ context.Context, certificate *x509.Certificate) (auth.Identity, error) {
+	subject := auth.CertificateSubject(certificate)
+	identity, ok := (*s.identities.Load())[subject]
+	if !ok {
+		return auth.Identity{}, auth.ErrUnknownCertificate
+	}
+	name := identity.Name
+	if name == "" {
+		name = subject
+	}
+	return auth.Identity{
+		Name:      name,
+		Clients:   identity.Clients,
+		Exchanges: identity.Exchanges,
+		Roles:     auth.KeyRoles(identity.Roles),
+	}, nil
+}
+
+func (s certificateService) Refresh(ctx context.Context) error {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	identities, err := s.repository.GetCertificateIdentities(c)
+	if err != nil {
+		return err
+	}
+	bySubject := make(map[string]auth.CertificateIdentity, len(identities))
+	for _, identity := range identities {
+		bySubject[identity.Subject] = identity
+	}
+	s.identities.Store(&bySubject)
+	return nil
+}
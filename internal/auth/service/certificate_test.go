@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type certificateRepositoryStub []auth.CertificateIdentity
+
+func (r certificateRepositoryStub) GetCertificateIdentities(_ context.Context) ([]auth.CertificateIdentity, error) {
+	return r, nil
+}
+
+func TestCertificateService_Identify(t *testing.T) {
+	service, err := NewCertificateService(
+		context.Background(),
+		certificateRepositoryStub{
+			{
+				Subject:   "CN=gateway-1,O=Acme",
+				Clients:   []string{"client"},
+				Exchanges: []string{auth.Wildcard},
+				Roles:     []string{"writer"},
+			},
+			{Subject: "CN=reporting", Name: "reporting", Roles: []string{"reader"}},
+		},
+		time.Second,
+	)
+	assert.NoError(t, err)
+
+	testTable := []struct {
+		name        string
+		subject     pkix.Name
+		expected    auth.Identity
+		expectedErr error
+	}{
+		{
+			name:    "MAPPED SUBJECT",
+			subject: pkix.Name{CommonName: "gateway-1", Organization: []string{"Acme"}},
+			expected: auth.Identity{
+				Name:      "CN=gateway-1,O=Acme",
+				Clients:   []string{"client"},
+				Exchanges: []string{auth.Wildcard},
+				Roles:     []auth.Role{auth.RoleWriter},
+			},
+		},
+		{
+			name:     "NAMED IDENTITY",
+			subject:  pkix.Name{CommonName: "reporting"},
+			expected: auth.Identity{Name: "reporting", Roles: []auth.Role{auth.RoleReader}},
+		},
+		{
+			name:        "OTHER ORGANIZATION",
+			subject:     pkix.Name{CommonName: "gateway-1", Organization: []string{"Other"}},
+			expectedErr: auth.ErrUnknownCertificate,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				identity, err := service.Identify(context.Background(), &x509.Certificate{Subject: test.subject})
+				assert.ErrorIs(t, err, test.expectedErr)
+				assert.Equal(t, test.expected, identity)
+			},
+		)
+	}
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Refresher is implemented by services holding keys in memory
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// RefreshJob periodically reloads keys, so added or revoked keys apply without restart
+type RefreshJob struct {
+	service  Refresher
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+func NewRefreshJob(service Refresher, interval time.Duration, logger *slog.Logger) RefreshJob {
+	return RefreshJob{service: service, interval: interval, logger: logger}
+}
+
+// Refresh reloads keys immediately
+func (j RefreshJob) Refresh(ctx context.Context) error {
+	return j.service.Refresh(ctx)
+}
+
+// Run blocks until ctx is done. Previously loaded keys are kept if refresh fails
+func (j RefreshJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.service.Refresh(ctx); err != nil {
+				j.logger.Error("Error while refreshing keys", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authRepository "github.com/plinkplenk/test-vortex/internal/auth/repository"
+	"sync/atomic"
+	"time"
+)
+
+//go:generate mockgen -source=jwt.go -destination=mocks/jwt.go
+type JWTService interface {
+	// Verify validates bearer token and returns identity built from its claims
+	Verify(ctx context.Context, token string) (auth.Identity, error)
+	// Refresh reloads verification keys from the repository
+	Refresh(ctx context.Context) error
+}
+
+type jwtService struct {
+	repository authRepository.VerificationKeysRepository
+	timeout    time.Duration
+	issuer     string
+	audience   string
+	leeway     time.Duration
+	keys       *atomic.Pointer[auth.VerificationKeys]
+	now        func() time.Time
+}
+
+// NewJWTService creates JWT service and loads verification keys.
+// Empty issuer or audience disables the corresponding claim check
+func NewJWTService(
+	ctx context.Context,
+	repository authRepository.VerificationKeysRepository,
+	timeout time.Duration,
+	issuer, audience string,
+	leeway time.Duration,
+) (JWTService, error) {
+	s := jwtService{
+		repository: repository,
+		timeout:    timeout,
+		issuer:     issuer,
+		audience:   audience,
+		leeway:     leeway,
+		keys:       &atomic.Pointer[auth.VerificationKeys]{},
+		now:        time.Now,
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s jwtService) Verify(_ context.Context, token string) (auth.Identity, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(auth.TokenMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(s.leeway),
+		jwt.WithTimeFunc(s.now),
+	}
+	if s.issuer != "" {
+		options = append(options, jwt.WithIssuer(s.issuer))
+	}
+	if s.audience != "" {
+		options = append(options, jwt.WithAudience(s.audience))
+	}
+	var claims auth.Claims
+	if _, err := jwt.ParseWithClaims(token, &claims, s.keys.Load().Keyfunc, options...); err != nil {
+		return auth.Identity{}, auth.ErrInvalidToken
+	}
+	if claims.Subject == "" {
+		return auth.Identity{}, auth.ErrInvalidToken
+	}
+	return claims.Identity(), nil
+}
+
+func (s jwtService) Refresh(ctx context.Context) error {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	keys, err := s.repository.GetVerificationKeys(c)
+	if err != nil {
+		return err
+	}
+	s.keys.Store(&keys)
+	return nil
+}
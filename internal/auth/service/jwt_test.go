@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type verificationKeysRepositoryStub auth.VerificationKeys
+
+func (r verificationKeysRepositoryStub) GetVerificationKeys(_ context.Context) (auth.VerificationKeys, error) {
+	return auth.VerificationKeys(r), nil
+}
+
+func TestJWTService_Verify(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	ecPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks := fmt.Sprintf(
+		`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"ed","x":%q},{"kty":"EC","crv":"P-256","x":%q,"y":%q}]}`,
+		b64(edPublic), b64(ecPrivate.X.FillBytes(make([]byte, 32))), b64(ecPrivate.Y.FillBytes(make([]byte, 32))),
+	)
+	keys, err := auth.ParseVerificationKeys([]byte(jwks))
+	assert.NoError(t, err)
+
+	claims := func(modify func(c *auth.Claims)) auth.Claims {
+		c := auth.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "algo-desk",
+				Issuer:    "idp",
+				Audience:  jwt.ClaimStrings{"orders"},
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			},
+			Roles:     []string{"writer", "unknown"},
+			Clients:   []string{"client"},
+			Exchanges: []string{auth.Wildcard},
+		}
+		if modify != nil {
+			modify(&c)
+		}
+		return c
+	}
+	sign := func(method jwt.SigningMethod, kid string, key any, c auth.Claims) string {
+		token := jwt.NewWithClaims(method, c)
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		s, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return s
+	}
+
+	s, err := NewJWTService(
+		context.Background(), verificationKeysRepositoryStub(keys), time.Second, "idp", "orders", 0,
+	)
+	assert.NoError(t, err)
+	service := s.(jwtService)
+	service.now = func() time.Time { return now }
+
+	expected := auth.Identity{
+		Name:      "algo-desk",
+		Clients:   []string{"client"},
+		Exchanges: []string{auth.Wildcard},
+		Roles:     []auth.Role{auth.RoleWriter},
+	}
+	testTable := []struct {
+		name        string
+		token       string
+		expectedErr error
+	}{
+		{name: "SUCCESS BY KID", token: sign(jwt.SigningMethodEdDSA, "ed", edPrivate, claims(nil))},
+		{name: "SUCCESS WITHOUT KID", token: sign(jwt.SigningMethodES256, "", ecPrivate, claims(nil))},
+		{
+			name:        "KID OF OTHER KEY",
+			token:       sign(jwt.SigningMethodES256, "ed", ecPrivate, claims(nil)),
+			expectedErr: auth.ErrInvalidToken,
+		},
+		{
+			name:        "SYMMETRIC METHOD",
+			token:       sign(jwt.SigningMethodHS256, "", []byte("secret"), claims(nil)),
+			expectedErr: auth.ErrInvalidToken,
+		},
+		{
+			name: "EXPIRED",
+			token: sign(
+				jwt.SigningMethodEdDSA, "ed", edPrivate,
+				claims(func(c *auth.Claims) { c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Second)) }),
+			),
+			expectedErr: auth.ErrInvalidToken,
+		},
+		{
+			name: "NO EXPIRATION",
+			token: sign(
+				jwt.SigningMethodEdDSA, "ed", edPrivate, claims(func(c *auth.Claims) { c.ExpiresAt = nil }),
+			),
+			expectedErr: auth.ErrInvalidToken,
+		},
+		{
+			name: "WRONG ISSUER",
+			token: sign(
+				jwt.SigningMethodEdDSA, "ed", edPrivate, claims(func(c *auth.Claims) { c.Issuer = "other" }),
+			),
+			expectedErr: auth.ErrInvalidToken,
+		},
+		{
+			name: "WRONG AUDIENCE",
+			token: sign(
+				jwt.SigningMethodEdDSA, "ed", edPrivate,
+				claims(func(c *auth.Claims) { c.Audience = jwt.ClaimStrings{"other"} }),
+			),
+			expectedErr: auth.ErrInvalidToken,
+		},
+		{
+			name: "NO SUBJECT",
+			token: sign(
+				jwt.SigningMethodEdDSA, "ed", edPrivate, claims(func(c *auth.Claims) { c.Subject = "" }),
+			),
+			expectedErr: auth.ErrInvalidToken,
+		},
+		{name: "MALFORMED", token: "not-a-token", expectedErr: auth.ErrInvalidToken},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				identity, err := service.Verify(context.Background(), test.token)
+				if test.expectedErr != nil {
+					assert.ErrorIs(t, err, test.expectedErr)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, expected, identity)
+			},
+		)
+	}
+}
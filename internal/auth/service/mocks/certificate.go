@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: certificate.go
+//
+// Generated by this command:
+//
+//	mockgen -source=certificate.go -destination=mocks/certificate.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	x509 "crypto/x509"
+	reflect "reflect"
+
+	auth "github.com/plinkplenk/test-vortex/internal/auth"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCertificateService is a mock of CertificateService interface.
+type MockCertificateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCertificateServiceMockRecorder
+}
+
+// MockCertificateServiceMockRecorder is the mock recorder for MockCertificateService.
+type MockCertificateServiceMockRecorder struct {
+	mock *MockCertificateService
+}
+
+// NewMockCertificateService creates a new mock instance.
+func NewMockCertificateService(ctrl *gomock.Controller) *MockCertificateService {
+	mock := &MockCertificateService{ctrl: ctrl}
+	mock.recorder = &MockCertificateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCertificateService) EXPECT() *MockCertificateServiceMockRecorder {
+	return m.recorder
+}
+
+// Identify mocks base method.
+func (m *MockCertificateService) Identify(ctx context.Context, certificate *x509.Certificate) (auth.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Identify", ctx, certificate)
+	ret0, _ := ret[0].(auth.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Identify indicates an expected call of Identify.
+func (mr *MockCertificateServiceMockRecorder) Identify(ctx, certificate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Identify", reflect.TypeOf((*MockCertificateService)(nil).Identify), ctx, certificate)
+}
+
+// Refresh mocks base method.
+func (m *MockCertificateService) Refresh(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockCertificateServiceMockRecorder) Refresh(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockCertificateService)(nil).Refresh), ctx)
+}
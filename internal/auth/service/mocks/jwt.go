@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: jwt.go
+//
+// Generated by this command:
+//
+//	mockgen -source=jwt.go -destination=mocks/jwt.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/plinkplenk/test-vortex/internal/auth"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJWTService is a mock of JWTService interface.
+type MockJWTService struct {
+	ctrl     *gomock.Controller
+	recorder *MockJWTServiceMockRecorder
+}
+
+// MockJWTServiceMockRecorder is the mock recorder for MockJWTService.
+type MockJWTServiceMockRecorder struct {
+	mock *MockJWTService
+}
+
+// NewMockJWTService creates a new mock instance.
+func NewMockJWTService(ctrl *gomock.Controller) *MockJWTService {
+	mock := &MockJWTService{ctrl: ctrl}
+	mock.recorder = &MockJWTServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJWTService) EXPECT() *MockJWTServiceMockRecorder {
+	return m.recorder
+}
+
+// Refresh mocks base method.
+func (m *MockJWTService) Refresh(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockJWTServiceMockRecorder) Refresh(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockJWTService)(nil).Refresh), ctx)
+}
+
+// Verify mocks base method.
+func (m *MockJWTService) Verify(ctx context.Context, token string) (auth.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, token)
+	ret0, _ := ret[0].(auth.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockJWTServiceMockRecorder) Verify(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockJWTService)(nil).Verify), ctx, token)
+}
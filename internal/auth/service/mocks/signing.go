@@ -0,0 +1,70 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: signing.go
+//
+// Generated by this command:
+//
+//	mockgen -source=signing.go -destination=mocks/signing.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	auth "github.com/plinkplenk/test-vortex/internal/auth"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSigningService is a mock of SigningService interface.
+type MockSigningService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSigningServiceMockRecorder
+}
+
+// MockSigningServiceMockRecorder is the mock recorder for MockSigningService.
+type MockSigningServiceMockRecorder struct {
+	mock *MockSigningService
+}
+
+// NewMockSigningService creates a new mock instance.
+func NewMockSigningService(ctrl *gomock.Controller) *MockSigningService {
+	mock := &MockSigningService{ctrl: ctrl}
+	mock.recorder = &MockSigningServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSigningService) EXPECT() *MockSigningServiceMockRecorder {
+	return m.recorder
+}
+
+// Refresh mocks base method.
+func (m *MockSigningService) Refresh(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockSigningServiceMockRecorder) Refresh(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockSigningService)(nil).Refresh), ctx)
+}
+
+// Verify mocks base method.
+func (m *MockSigningService) Verify(ctx context.Context, request auth.SignedRequest) (auth.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx, request)
+	ret0, _ := ret[0].(auth.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockSigningServiceMockRecorder) Verify(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSigningService)(nil).Verify), ctx, request)
+}
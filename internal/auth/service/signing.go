@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	authRepository "github.com/plinkplenk/test-vortex/internal/auth/repository"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+//go:generate mockgen -source=signing.go -destination=mocks/signing.go
+type SigningService interface {
+	// Verify checks request signature, timestamp and nonce and returns identity of the signing key
+	Verify(ctx context.Context, request auth.SignedRequest) (auth.Identity, error)
+	// Refresh reloads signing keys from the repository
+	Refresh(ctx context.Context) error
+}
+
+type signingService struct {
+	repository authRepository.SigningRepository
+	timeout    time.Duration
+	maxSkew    time.Duration
+	nonces     *auth.NonceCache
+	keys       *atomic.Pointer[map[string]auth.SigningKey]
+	now        func() time.Time
+}
+
+// NewSigningService creates signing service and loads signing keys.
+// Requests with timestamp further than maxSkew from the server time are rejected
+func NewSigningService(
+	ctx context.Context, repository authRepository.SigningRepository, timeout, maxSkew time.Duration,
+) (SigningService, error) {
+	s := signingService{
+		repository: repository,
+		timeout:    timeout,
+		maxSkew:    maxSkew,
+		nonces:     auth.NewNonceCache(2 * maxSkew),
+		keys:       &atomic.Pointer[map[string]auth.SigningKey]{},
+		now:        time.Now,
+	}
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s signingService) Verify(_ context.Context, request auth.SignedRequest) (auth.Identity, error) {
+	if request.KeyID == "" || request.Signature == "" || request.Timestamp == "" || request.Nonce == "" {
+		return auth.Identity{}, auth.ErrSignatureNotProvided
+	}
+	key, ok := (*s.keys.Load())[request.KeyID]
+	if !ok {
+		return auth.Identity{}, auth.ErrInvalidSignature
+	}
+	timestamp, err := strconv.ParseInt(request.Timestamp, 10, 64)
+	if err != nil {
+		return auth.Identity{}, auth.ErrStaleTimestamp
+	}
+	now := s.now()
+	if skew := now.Sub(time.Unix(timestamp, 0)).Abs(); skew > s.maxSkew {
+		return auth.Identity{}, auth.ErrStaleTimestamp
+	}
+	if !auth.VerifySignature(key.Secrets, request) {
+		return auth.Identity{}, auth.ErrInvalidSignature
+	}
+	// nonce is stored only for valid signatures, so forged requests can't burn nonces
+	if !s.nonces.Add(request.KeyID, request.Nonce, now) {
+		return auth.Identity{}, auth.ErrReplayedNonce
+	}
+	return auth.Identity{
+		Name:      key.KeyID,
+		Clients:   key.Clients,
+		Exchanges: key.Exchanges,
+		Roles:     auth.KeyRoles(key.Roles),
+		Signed:    true,
+	}, nil
+}
+
+func (s signingService) Refresh(ctx context.Context) error {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	keys, err := s.repository.GetSigningKeys(c)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]auth.SigningKey, len(keys))
+	for _, key := range keys {
+		byID[key.KeyID] = key
+	}
+	s.keys.Store(&byID)
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/auth"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type signingRepositoryStub []auth.SigningKey
+
+func (r signingRepositoryStub) GetSigningKeys(_ context.Context) ([]auth.SigningKey, error) {
+	return r, nil
+}
+
+func TestSigningService_Verify(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := auth.SigningKey{
+		KeyID:     "gateway",
+		Secrets:   []string{"new-secret", "old-secret"},
+		Clients:   []string{"client"},
+		Exchanges: []string{auth.Wildcard},
+	}
+	signed := func(secret string, timestamp time.Time, nonce string) auth.SignedRequest {
+		r := auth.SignedRequest{
+			KeyID:     key.KeyID,
+			Timestamp: strconv.FormatInt(timestamp.Unix(), 10),
+			Nonce:     nonce,
+			Method:    "POST",
+			Path:      "/orders/history/",
+			Body:      []byte(`{"client":{}}`),
+		}
+		r.Signature = auth.Sign(secret, r)
+		return r
+	}
+	tampered := signed("new-secret", now, "tampered")
+	tampered.Body = []byte(`{"client":{"clientName":"other"}}`)
+	unknownKey := signed("new-secret", now, "unknown-key")
+	unknownKey.KeyID = "unknown"
+
+	s, err := NewSigningService(context.Background(), signingRepositoryStub{key}, time.Second, 30*time.Second)
+	assert.NoError(t, err)
+	service := s.(signingService)
+	service.now = func() time.Time { return now }
+
+	testTable := []struct {
+		name        string
+		request     auth.SignedRequest
+		expectedErr error
+	}{
+		{name: "SUCCESS", request: signed("new-secret", now, "1")},
+		{name: "ROTATED SECRET", request: signed("old-secret", now, "2")},
+		{name: "CLOCK SKEW", request: signed("new-secret", now.Add(-20*time.Second), "3")},
+		{name: "REPLAYED NONCE", request: signed("new-secret", now, "1"), expectedErr: auth.ErrReplayedNonce},
+		{
+			name:        "STALE TIMESTAMP",
+			request:     signed("new-secret", now.Add(-time.Minute), "4"),
+			expectedErr: auth.ErrStaleTimestamp,
+		},
+		{name: "WRONG SECRET", request: signed("revoked-secret", now, "5"), expectedErr: auth.ErrInvalidSignature},
+		{name: "TAMPERED BODY", request: tampered, expectedErr: auth.ErrInvalidSignature},
+		{name: "UNKNOWN KEY", request: unknownKey, expectedErr: auth.ErrInvalidSignature},
+		{name: "NOT SIGNED", request: auth.SignedRequest{}, expectedErr: auth.ErrSignatureNotProvided},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				identity, err := service.Verify(context.Background(), test.request)
+				if test.expectedErr != nil {
+					assert.ErrorIs(t, err, test.expectedErr)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(
+					t,
+					auth.Identity{
+						Name:      "gateway",
+						Clients:   key.Clients,
+						Exchanges: key.Exchanges,
+						Roles:     []auth.Role{auth.RoleReader},
+						Signed:    true,
+					},
+					identity,
+				)
+			},
+		)
+	}
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrSignatureNotProvided = errors.New("request signature not provided")
+	ErrInvalidSignature     = errors.New("invalid request signature")
+	ErrStaleTimestamp       = errors.New("request timestamp is outside of allowed clock skew")
+	ErrReplayedNonce        = errors.New("request nonce was already used")
+)
+
+// SigningKey holds all active secrets of a key id, several secrets allow rotation without downtime
+type SigningKey struct {
+	KeyID     string   `json:"keyId"`
+	Secrets   []string `json:"secrets"`
+	Clients   []string `json:"clients"`
+	Exchanges []string `json:"exchanges"`
+	// Roles are reader if the field is missing like roles of api keys
+	Roles []string `json:"roles"`
+}
+
+type SignedRequest struct {
+	KeyID     string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Method    string
+	// Path is request path with query string
+	Path string
+	Body []byte
+}
+
+// StringToSign joins method, path, timestamp, nonce and hex encoded SHA-256 of the body with new lines
+func StringToSign(method, path, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.Join(
+		[]string{strings.ToUpper(method), path, timestamp, nonce, hex.EncodeToString(bodyHash[:])},
+		"\n",
+	)
+}
+
+// Sign returns hex encoded HMAC-SHA256 of the request string to sign
+func Sign(secret string, r SignedRequest) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(StringToSign(r.Method, r.Path, r.Timestamp, r.Nonce, r.Body)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether request is signed with any of the secrets
+func VerifySignature(secrets []string, r SignedRequest) bool {
+	signature, err := hex.DecodeString(r.Signature)
+	if err != nil {
+		return false
+	}
+	for _, secret := range secrets {
+		expected, _ := hex.DecodeString(Sign(secret, r))
+		if hmac.Equal(signature, expected) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package candles
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Candle struct {
+	Time        time.Time `json:"time"`
+	Open        float64   `json:"open"`
+	High        float64   `json:"high"`
+	Low         float64   `json:"low"`
+	Close       float64   `json:"close"`
+	BaseVolume  float64   `json:"baseVolume"`
+	QuoteVolume float64   `json:"quoteVolume"`
+	Trades      uint64    `json:"trades"`
+}
+
+type Query struct {
+	ExchangeName string
+	Pair         string
+	Interval     time.Duration
+	From         time.Time
+	To           time.Time
+}
+
+// ParseInterval parses candle interval like "1m", "5m", "1h" or "1d".
+// Everything time.ParseDuration accepts is valid as well as the "d" (day) unit
+func ParseInterval(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid interval %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/plinkplenk/test-vortex/internal/candles"
+)
+
+type clickHouseRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseRepository(db clickhouse.Conn) Repository {
+	return clickHouseRepository{
+		db: db,
+	}
+}
+
+func (r clickHouseRepository) GetCandles(ctx context.Context, query candles.Query) ([]candles.Candle, error) {
+	q := `
+		SELECT toStartOfInterval(bucket, INTERVAL ? SECOND) AS time,
+		       argMinMerge(open),
+		       max(high),
+		       min(low),
+		       argMaxMerge(close),
+		       sum(base_volume),
+		       sum(quote_volume),
+		       sum(trades)
+		FROM order_history_candles_1m
+		WHERE exchange_name = ? AND pair = ? AND bucket >= ? AND bucket < ?
+		GROUP BY time
+		ORDER BY time`
+	rows, err := r.db.Query(
+		ctx, q, int64(query.Interval.Seconds()), query.ExchangeName, query.Pair, query.From, query.To,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanCandles(rows)
+}
+
+func (r clickHouseRepository) GetCandlesFromHistory(ctx context.Context, query candles.Query) (
+	[]candles.Candle, error,
+) {
+	q := `
+		SELECT toStartOfInterval(time_placed, INTERVAL ? SECOND) AS time,
+		       argMin(price, time_placed),
+		       max(price),
+		       min(price),
+		       argMax(price, time_placed),
+		       sum(base_qty),
+		       sum(base_qty * price),
+		       count()
+		FROM order_history
+		WHERE exchange_name = ? AND pair = ? AND time_placed >= ? AND time_placed < ?
+		GROUP BY time
+		ORDER BY time`
+	rows, err := r.db.Query(
+		ctx, q, int64(query.Interval.Seconds()), query.ExchangeName, query.Pair, query.From, query.To,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanCandles(rows)
+}
+
+func scanCandles(rows driver.Rows) ([]candles.Candle, error) {
+	defer rows.Close()
+	var result []candles.Candle
+	for rows.Next() {
+		var c candles.Candle
+		if err := rows.Scan(
+			&c.Time,
+			&c.Open,
+			&c.High,
+			&c.Low,
+			&c.Close,
+			&c.BaseVolume,
+			&c.QuoteVolume,
+			&c.Trades,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, c)
+	}
+	return result, rows.Err()
+}
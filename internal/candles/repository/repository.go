@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/candles"
+)
+
+type Repository interface {
+	// GetCandles returns candles rolled up from the one minute materialized view.
+	// Query interval must be a multiple of a minute
+	GetCandles(ctx context.Context, query candles.Query) ([]candles.Candle, error)
+	// GetCandlesFromHistory aggregates candles directly from order history for any interval
+	GetCandlesFromHistory(ctx context.Context, query candles.Query) ([]candles.Candle, error)
+}
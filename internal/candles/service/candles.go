@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/candles"
+	candlesRepository "github.com/plinkplenk/test-vortex/internal/candles/repository"
+	"slices"
+	"time"
+)
+
+//go:generate mockgen -source=candles.go -destination=mocks/mock.go
+type CandlesService interface {
+	GetCandles(ctx context.Context, query candles.Query) ([]candles.Candle, error)
+}
+
+type candleService struct {
+	repository candlesRepository.Repository
+	timeout    time.Duration
+	// intervals served from the materialized view, others are aggregated on the fly
+	intervals []time.Duration
+}
+
+func New(repository candlesRepository.Repository, timeout time.Duration, intervals []time.Duration) CandlesService {
+	return candleService{
+		repository: repository,
+		timeout:    timeout,
+		intervals:  intervals,
+	}
+}
+
+func (s candleService) GetCandles(ctx context.Context, query candles.Query) ([]candles.Candle, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if query.Interval%time.Minute == 0 && slices.Contains(s.intervals, query.Interval) {
+		return s.repository.GetCandles(c, query)
+	}
+	return s.repository.GetCandlesFromHistory(c, query)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: candles.go
+//
+// Generated by this command:
+//
+//	mockgen -source=candles.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	candles "github.com/plinkplenk/test-vortex/internal/candles"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCandlesService is a mock of CandlesService interface.
+type MockCandlesService struct {
+	ctrl     *gomock.Controller
+	recorder *MockCandlesServiceMockRecorder
+}
+
+// MockCandlesServiceMockRecorder is the mock recorder for MockCandlesService.
+type MockCandlesServiceMockRecorder struct {
+	mock *MockCandlesService
+}
+
+// NewMockCandlesService creates a new mock instance.
+func NewMockCandlesService(ctrl *gomock.Controller) *MockCandlesService {
+	mock := &MockCandlesService{ctrl: ctrl}
+	mock.recorder = &MockCandlesServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCandlesService) EXPECT() *MockCandlesServiceMockRecorder {
+	return m.recorder
+}
+
+// GetCandles mocks base method.
+func (m *MockCandlesService) GetCandles(ctx context.Context, query candles.Query) ([]candles.Candle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCandles", ctx, query)
+	ret0, _ := ret[0].([]candles.Candle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCandles indicates an expected call of GetCandles.
+func (mr *MockCandlesServiceMockRecorder) GetCandles(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCandles", reflect.TypeOf((*MockCandlesService)(nil).GetCandles), ctx, query)
+}
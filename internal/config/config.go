@@ -1,7 +1,15 @@
 package config
 
 import (
-	"os"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/ratelimit"
+	"github.com/plinkplenk/test-vortex/internal/tlsconfig"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
 	"time"
 )
 
@@ -12,63 +20,368 @@ const (
 	ENVProd  ENV = "prod"
 )
 
-func strToENV(env string) ENV {
-	if e := ENV(env); e == ENVLocal || e == ENVProd {
-		return e
-	}
-	return ENVProd
-}
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Fields are described by struct tags:
+//   - config is the key in config file, nested keys are joined with "." and used as command line flag names
+//   - env is the environment variable, empty variables are ignored.
+//     env of a nested struct is a prefix of its fields variables
+//   - default is the value used when the field is not set by any source
+//   - secret fields are redacted by Print and Diff
+//   - reload fields are applied by running app when config is reloaded, reload of a nested struct applies to its fields
 
 type Clickhouse struct {
-	User     string
-	Password string
-	Host     string
-	Port     string
+	User     string `config:"user" env:"CLICKHOUSE_ADMIN_USER" default:"clickhouse"`
+	Password string `config:"password" env:"CLICKHOUSE_ADMIN_PASSWORD" default:"clickhouse" secret:"true"`
+	Host     string `config:"host" env:"CLICKHOUSE_HOST" default:"localhost"`
+	Port     string `config:"port" env:"CLICKHOUSE_PORT" default:"9000"`
+	Database string `config:"database" env:"CLICKHOUSE_DATABASE" default:"default"`
+	// Compression is "none", "lz4", "zstd", "gzip", "deflate" or "br"
+	Compression          string        `config:"compression" env:"CLICKHOUSE_COMPRESSION" default:"lz4"`
+	MaxCompressionBuffer int           `config:"max_compression_buffer" env:"CLICKHOUSE_MAX_COMPRESSION_BUFFER" default:"10240"`
+	DialTimeout          time.Duration `config:"dial_timeout" env:"CLICKHOUSE_DIAL_TIMEOUT" default:"10s"`
+	MaxOpenConns         int           `config:"max_open_conns" env:"CLICKHOUSE_MAX_OPEN_CONNS" default:"300"`
+	MaxIdleConns         int           `config:"max_idle_conns" env:"CLICKHOUSE_MAX_IDLE_CONNS" default:"10"`
+	ConnMaxLifetime      time.Duration `config:"conn_max_lifetime" env:"CLICKHOUSE_CONN_MAX_LIFETIME" default:"30s"`
+	BlockBufferSize      int           `config:"block_buffer_size" env:"CLICKHOUSE_BLOCK_BUFFER_SIZE" default:"10"`
+	// MaxExecutionTime is ClickHouse max_execution_time setting, it is rounded to seconds
+	MaxExecutionTime time.Duration `config:"max_execution_time" env:"CLICKHOUSE_MAX_EXECUTION_TIME" default:"60s"`
+	TLS              ClickhouseTLS `config:"tls" env:"CLICKHOUSE_TLS"`
+}
+
+type ClickhouseTLS struct {
+	Enabled bool `config:"enabled" env:"ENABLED" default:"false"`
+	// CAFile verifies server certificate, system roots are used if it is empty
+	CAFile string `config:"ca_file" env:"CA_FILE"`
+	// CertFile and KeyFile are client certificate sent if server requests it
+	CertFile string `config:"cert_file" env:"CERT_FILE"`
+	KeyFile  string `config:"key_file" env:"KEY_FILE"`
+	// ServerName overrides host the server certificate is verified for
+	ServerName         string `config:"server_name" env:"SERVER_NAME"`
+	MinVersion         string `config:"min_version" env:"MIN_VERSION" default:"1.2"`
+	InsecureSkipVerify bool   `config:"insecure_skip_verify" env:"INSECURE_SKIP_VERIFY" default:"false"`
+}
+
+type Log struct {
+	// Level is "debug", "info", "warn" or "error"
+	Level string `config:"level" env:"LOG_LEVEL" default:"info"`
+}
+
+// SlogLevel is Level as slog.Level, Level must be valid
+func (l Log) SlogLevel() slog.Level {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(l.Level))
+	return level
 }
+
 type Server struct {
-	Port    string
-	Timeout time.Duration
+	Port    string        `config:"port" env:"SERVER_PORT" default:"8080"`
+	Timeout time.Duration `config:"timeout" env:"TIMEOUT" default:"10s"`
+	// DrainDelay is how long the server keeps serving after /readyz starts failing on shutdown,
+	// so load balancers stop sending new requests before connections are closed
+	DrainDelay time.Duration `config:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY" default:"0s"`
+	// ConfigWatchInterval is how often config file is checked for changes, zero disables watching
+	ConfigWatchInterval time.Duration `config:"config_watch_interval" env:"CONFIG_WATCH_INTERVAL" default:"10s"`
+	TLS                 ServerTLS     `config:"tls" env:"SERVER_TLS"`
+}
+
+// ServerTLS is enabled when CertFile and KeyFile are set
+type ServerTLS struct {
+	CertFile   string `config:"cert_file" env:"CERT_FILE"`
+	KeyFile    string `config:"key_file" env:"KEY_FILE"`
+	MinVersion string `config:"min_version" env:"MIN_VERSION" default:"1.2"`
+	// ClientAuth is "none", "request", "verify_if_given" or "require", see tlsconfig.ParseClientAuth
+	ClientAuth string `config:"client_auth" env:"CLIENT_AUTH" default:"none"`
+	// ClientCAFile verifies client certificates
+	ClientCAFile string `config:"client_ca_file" env:"CLIENT_CA_FILE"`
+	// IdentitiesFile is JSON file mapping verified client certificate subjects to identities,
+	// client certificates don't authenticate requests if it is empty
+	IdentitiesFile string `config:"identities_file" env:"IDENTITIES_FILE"`
+}
+
+func (t ServerTLS) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+// Addr is the address server listens on
+func (s Server) Addr() string {
+	return ":" + s.Port
+}
+
+type GRPC struct {
+	// Port of the gRPC server, the server is disabled if it is empty.
+	// It uses TLS, auth and rate limits of the HTTP server
+	Port string `config:"port" env:"GRPC_PORT"`
+}
+
+// Enabled reports whether gRPC server is started
+func (g GRPC) Enabled() bool {
+	return g.Port != ""
+}
+
+// Addr is the address gRPC server listens on
+func (g GRPC) Addr() string {
+	return ":" + g.Port
+}
+
+type Candles struct {
+	// Intervals served from the order_history_candles_1m materialized view
+	Intervals []time.Duration `config:"intervals" env:"CANDLE_INTERVALS" default:"1m,5m,1h,1d"`
+}
+
+type Markouts struct {
+	// Horizons after time placed at which fills are marked against order book mid
+	Horizons []time.Duration `config:"horizons" env:"MARKOUT_HORIZONS" default:"1s,10s,1m"`
+	// Interval of the background markouts computation
+	Interval time.Duration `config:"interval" env:"MARKOUT_INTERVAL" default:"1m"`
+}
+
+type Export struct {
+	// Timeout of a whole order history export, it replaces clickhouse.max_execution_time for export queries
+	Timeout time.Duration `config:"timeout" env:"EXPORT_TIMEOUT" default:"10m"`
+}
+
+type Replay struct {
+	// Chunk is the window of events loaded at once, each chunk is loaded within server.timeout
+	Chunk time.Duration `config:"chunk" env:"REPLAY_CHUNK" default:"1m"`
+}
+
+type Auth struct {
+	Enabled bool `config:"enabled" env:"AUTH_ENABLED" default:"false"`
+	// KeysFile is JSON file with api keys, keys are read from ClickHouse if it is empty
+	KeysFile string `config:"keys_file" env:"AUTH_KEYS_FILE"`
+	// RefreshInterval is how often api keys, signing keys, jwt keys and TLS certificates are reloaded
+	RefreshInterval time.Duration `config:"refresh_interval" env:"AUTH_REFRESH_INTERVAL" default:"1m"`
+}
+
+type Signing struct {
+	// KeysFile is JSON file with HMAC signing keys, request signing is disabled if it is empty
+	KeysFile string `config:"keys_file" env:"SIGNING_KEYS_FILE"`
+	// MaxSkew is maximum allowed difference between request timestamp and server time
+	MaxSkew time.Duration `config:"max_skew" env:"SIGNING_MAX_SKEW" default:"30s"`
+	// MaxBody is maximum size in bytes of signed request bodies, they are read whole before authentication
+	MaxBody int `config:"max_body" env:"SIGNING_MAX_BODY" default:"1048576"`
+	// Required rejects unsigned requests to order book and order history write endpoints
+	Required bool `config:"required" env:"SIGNING_REQUIRED" default:"false"`
+}
+
+type JWT struct {
+	// KeysFile is JWKS or PEM file with token verification keys, bearer tokens are disabled if it is empty
+	KeysFile string `config:"keys_file" env:"JWT_KEYS_FILE"`
+	// Issuer and Audience are required values of "iss" and "aud" claims, empty values are not checked
+	Issuer   string `config:"issuer" env:"JWT_ISSUER"`
+	Audience string `config:"audience" env:"JWT_AUDIENCE"`
+	// Leeway is allowed clock skew for "exp", "nbf" and "iat" claims
+	Leeway time.Duration `config:"leeway" env:"JWT_LEEWAY" default:"30s"`
+}
+
+type Limit struct {
+	// RPS is requests per second, zero disables the limit
+	RPS float64 `config:"rps" env:"RPS" default:"0"`
+	// Burst is bucket size, zero means RPS rounded up
+	Burst int `config:"burst" env:"BURST" default:"0"`
+}
+
+func (l Limit) Limit() ratelimit.Limit {
+	burst := l.Burst
+	if burst == 0 {
+		burst = int(math.Ceil(l.RPS))
+	}
+	return ratelimit.Limit{Rate: l.RPS, Burst: burst}
+}
+
+type RateLimit struct {
+	// KeyBy is "key", "client" or "ip", see middleware.RateLimitByKey
+	KeyBy string `config:"key_by" env:"RATE_LIMIT_KEY_BY" default:"key"`
+	// Read and Write limits are applied to GET and POST routes
+	Read  Limit `config:"read" env:"RATE_LIMIT_READ" reload:"true"`
+	Write Limit `config:"write" env:"RATE_LIMIT_WRITE" reload:"true"`
+}
+
+type Tracing struct {
+	// Exporter is "none", "stdout" or "file"
+	Exporter string `config:"exporter" env:"TRACING_EXPORTER" default:"none"`
+	// File spans are appended to by "file" exporter
+	File string `config:"file" env:"TRACING_FILE" default:"traces.json"`
+	// SampleRatio is fraction of traces started by the service that are sampled
+	SampleRatio float64 `config:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+type Migrations struct {
+	// OnStartup applies embedded migrations before the server starts,
+	// it should be enabled for a single instance only because migrations are not locked
+	OnStartup bool `config:"on_startup" env:"MIGRATE_ON_STARTUP" default:"false"`
+}
+
+// Features turn optional subsystems on and off
+type Features struct {
+	// Candles, PnL and TCA enable endpoints of the analytics subsystems
+	Candles bool `config:"candles" env:"FEATURE_CANDLES" default:"true"`
+	PnL     bool `config:"pnl" env:"FEATURE_PNL" default:"true"`
+	TCA     bool `config:"tca" env:"FEATURE_TCA" default:"true"`
+	// Markouts enables markouts endpoints and the background markouts computation
+	Markouts bool `config:"markouts" env:"FEATURE_MARKOUTS" default:"true"`
+	// Export enables order history export endpoint
+	Export bool `config:"export" env:"FEATURE_EXPORT" default:"true"`
+	// Replay enables order book replay endpoint
+	Replay bool `config:"replay" env:"FEATURE_REPLAY" default:"true"`
+	// Metrics enables /metrics endpoint
+	Metrics bool `config:"metrics" env:"FEATURE_METRICS" default:"true"`
 }
 
 type Config struct {
-	ENV        ENV
-	Clickhouse Clickhouse
-	Server     Server
+	ENV        ENV        `config:"env" env:"ENV" default:"prod"`
+	Log        Log        `config:"log" reload:"true"`
+	Clickhouse Clickhouse `config:"clickhouse"`
+	Server     Server     `config:"server"`
+	GRPC       GRPC       `config:"grpc"`
+	Candles    Candles    `config:"candles"`
+	Markouts   Markouts   `config:"markouts"`
+	Export     Export     `config:"export"`
+	Replay     Replay     `config:"replay"`
+	Auth       Auth       `config:"auth"`
+	Signing    Signing    `config:"signing"`
+	JWT        JWT        `config:"jwt"`
+	RateLimit  RateLimit  `config:"rate_limit"`
+	Tracing    Tracing    `config:"tracing"`
+	Migrations Migrations `config:"migrations"`
+	Features   Features   `config:"features" reload:"true"`
+}
+
+var (
+	compressionMethods = []string{"none", "lz4", "zstd", "gzip", "deflate", "br"}
+	rateLimitKeys      = []string{"key", "client", "ip"}
+	tracingExporters   = []string{"none", "stdout", "file"}
+)
+
+// fieldError is an invalid value of field with the key
+type fieldError struct {
+	key     string
+	message string
+}
+
+func (e fieldError) Error() string {
+	return e.key + ": " + e.message
 }
 
-func getENV(key string, defaultValue string) string {
-	val := os.Getenv(key)
-	if val == "" {
-		val = defaultValue
+// Validate returns error describing every invalid field
+func (c Config) Validate() error {
+	if errs := c.validate(); len(errs) > 0 {
+		return fmt.Errorf("%w:\n%w", ErrInvalidConfig, errors.Join(errs...))
 	}
-	return val
+	return nil
 }
 
-func Setup() Config {
-	env := strToENV(getENV("ENV", "prod"))
+func (c Config) validate() []error {
+	var errs []error
+	check := func(ok bool, key string, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fieldError{key: key, message: fmt.Sprintf(format, args...)})
+		}
+	}
+	positive := func(d time.Duration, key string) {
+		check(d > 0, key, "must be positive, got %s", d)
+	}
+	port := func(p string, key string) {
+		n, err := strconv.Atoi(p)
+		check(err == nil && n > 0 && n <= math.MaxUint16, key, "invalid port %q", p)
+	}
+	oneOf := func(value string, allowed []string, key string) {
+		check(slices.Contains(allowed, value), key, "must be one of %v, got %q", allowed, value)
+	}
 
-	serverPort := ":" + getENV("SERVER_PORT", "8080")
-	timeout, err := time.ParseDuration(getENV("TIMEOUT", "10s"))
-	if err != nil {
-		timeout = 10 * time.Second
+	check(c.ENV == ENVLocal || c.ENV == ENVProd, "env", "must be %q or %q, got %q", ENVLocal, ENVProd, c.ENV)
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level", "invalid level %q", c.Log.Level)
+
+	port(c.Clickhouse.Port, "clickhouse.port")
+	check(c.Clickhouse.Host != "", "clickhouse.host", "must not be empty")
+	check(c.Clickhouse.Database != "", "clickhouse.database", "must not be empty")
+	oneOf(c.Clickhouse.Compression, compressionMethods, "clickhouse.compression")
+	check(c.Clickhouse.MaxCompressionBuffer > 0, "clickhouse.max_compression_buffer", "must be positive")
+	positive(c.Clickhouse.DialTimeout, "clickhouse.dial_timeout")
+	check(c.Clickhouse.MaxOpenConns > 0, "clickhouse.max_open_conns", "must be positive")
+	check(
+		c.Clickhouse.MaxIdleConns >= 0 && c.Clickhouse.MaxIdleConns <= c.Clickhouse.MaxOpenConns,
+		"clickhouse.max_idle_conns", "must be between 0 and max_open_conns",
+	)
+	positive(c.Clickhouse.ConnMaxLifetime, "clickhouse.conn_max_lifetime")
+	check(
+		c.Clickhouse.BlockBufferSize > 0 && c.Clickhouse.BlockBufferSize <= math.MaxUint8,
+		"clickhouse.block_buffer_size", "must be between 1 and %d", math.MaxUint8,
+	)
+	check(c.Clickhouse.MaxExecutionTime >= time.Second, "clickhouse.max_execution_time", "must be at least 1s")
+	if c.Clickhouse.TLS.Enabled {
+		_, err := tlsconfig.ParseVersion(c.Clickhouse.TLS.MinVersion)
+		check(err == nil, "clickhouse.tls.min_version", "%v", err)
+		check(
+			(c.Clickhouse.TLS.CertFile == "") == (c.Clickhouse.TLS.KeyFile == ""),
+			"clickhouse.tls.key_file", "cert_file and key_file must be set together",
+		)
 	}
 
-	clickhousePort := getENV("CLICKHOUSE_PORT", "9000")
-	clickhouseHost := getENV("CLICKHOUSE_HOST", "localhost")
-	clickhouseUser := getENV("CLICKHOUSE_ADMIN_USER", "clickhouse")
-	clickhousePassword := getENV("CLICKHOUSE_ADMIN_PASSWORD", "clickhouse")
-
-	return Config{
-		ENV: env,
-		Clickhouse: Clickhouse{
-			User:     clickhouseUser,
-			Password: clickhousePassword,
-			Host:     clickhouseHost,
-			Port:     clickhousePort,
-		},
-		Server: Server{
-			Port:    serverPort,
-			Timeout: timeout,
-		},
+	port(c.Server.Port, "server.port")
+	positive(c.Server.Timeout, "server.timeout")
+	check(c.Server.DrainDelay >= 0, "server.drain_delay", "must not be negative")
+	check(c.Server.ConfigWatchInterval >= 0, "server.config_watch_interval", "must not be negative")
+	serverTLS := c.Server.TLS
+	check(
+		(serverTLS.CertFile == "") == (serverTLS.KeyFile == ""),
+		"server.tls.key_file", "cert_file and key_file must be set together",
+	)
+	_, err := tlsconfig.ParseVersion(serverTLS.MinVersion)
+	check(err == nil, "server.tls.min_version", "%v", err)
+	clientAuth, err := tlsconfig.ParseClientAuth(serverTLS.ClientAuth)
+	check(err == nil, "server.tls.client_auth", "%v", err)
+	if serverTLS.ClientAuth != "none" {
+		check(serverTLS.Enabled(), "server.tls.client_auth", "requires cert_file and key_file")
 	}
+	check(
+		clientAuth <= tls.RequestClientCert || serverTLS.ClientCAFile != "",
+		"server.tls.client_ca_file", "must be set to verify client certificates",
+	)
+	check(
+		serverTLS.IdentitiesFile == "" || clientAuth > tls.RequestClientCert,
+		"server.tls.identities_file", `requires client_auth "verify_if_given" or "require"`,
+	)
+
+	if c.GRPC.Enabled() {
+		port(c.GRPC.Port, "grpc.port")
+		check(c.GRPC.Port != c.Server.Port, "grpc.port", "must differ from server.port")
+		// requests are signed over HTTP method, path and body, gRPC calls can't be signed
+		check(!c.Signing.Required, "grpc.port", "gRPC server can't be enabled with signing.required")
+	}
+
+	check(len(c.Candles.Intervals) > 0, "candles.intervals", "must not be empty")
+	for _, interval := range c.Candles.Intervals {
+		positive(interval, "candles.intervals")
+	}
+	check(len(c.Markouts.Horizons) > 0, "markouts.horizons", "must not be empty")
+	for _, horizon := range c.Markouts.Horizons {
+		positive(horizon, "markouts.horizons")
+	}
+	positive(c.Markouts.Interval, "markouts.interval")
+	positive(c.Export.Timeout, "export.timeout")
+	positive(c.Replay.Chunk, "replay.chunk")
+
+	positive(c.Auth.RefreshInterval, "auth.refresh_interval")
+	positive(c.Signing.MaxSkew, "signing.max_skew")
+	check(c.Signing.MaxBody > 0, "signing.max_body", "must be positive")
+	check(!c.Signing.Required || c.Signing.KeysFile != "", "signing.required", "requires signing.keys_file")
+	check(c.JWT.Leeway >= 0, "jwt.leeway", "must not be negative")
+
+	oneOf(c.RateLimit.KeyBy, rateLimitKeys, "rate_limit.key_by")
+	check(c.RateLimit.Read.RPS >= 0, "rate_limit.read.rps", "must not be negative")
+	check(c.RateLimit.Read.Burst >= 0, "rate_limit.read.burst", "must not be negative")
+	check(c.RateLimit.Write.RPS >= 0, "rate_limit.write.rps", "must not be negative")
+	check(c.RateLimit.Write.Burst >= 0, "rate_limit.write.burst", "must not be negative")
+
+	oneOf(c.Tracing.Exporter, tracingExporters, "tracing.exporter")
+	check(c.Tracing.Exporter != "file" || c.Tracing.File != "", "tracing.file", "must be set for file exporter")
+	check(
+		c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"tracing.sample_ratio", "must be between 0 and 1, got %v", c.Tracing.SampleRatio,
+	)
+
+	return errs
 }
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envStub(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := Load(Sources{LookupEnv: envStub(nil)})
+	assert.NoError(t, err)
+	assert.Equal(t, ENVProd, cfg.ENV)
+	assert.Equal(t, ":8080", cfg.Server.Addr())
+	assert.Equal(t, 10*time.Second, cfg.Server.Timeout)
+	assert.Equal(t, "default", cfg.Clickhouse.Database)
+	assert.Equal(t, 300, cfg.Clickhouse.MaxOpenConns)
+	assert.Equal(t, []time.Duration{time.Minute, 5 * time.Minute, time.Hour, 24 * time.Hour}, cfg.Candles.Intervals)
+	assert.True(t, cfg.Features.Candles)
+	assert.False(t, cfg.GRPC.Enabled())
+}
+
+func TestLoad_Layers(t *testing.T) {
+	testTable := []struct {
+		name string
+		file string
+		env  map[string]string
+		flag map[string]string
+		// expectedTimeout is server.timeout, set by every layer
+		expectedTimeout time.Duration
+	}{
+		{
+			name:            "YAML FILE",
+			file:            writeFile(t, "config.yaml", "server:\n  timeout: 5s\n  port: 9090\n"),
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			name:            "TOML FILE",
+			file:            writeFile(t, "config.toml", "[server]\ntimeout = \"5s\"\nport = 9090\n"),
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			name:            "ENV OVERRIDES FILE",
+			file:            writeFile(t, "config.yml", "server:\n  timeout: 5s\n  port: 9090\n"),
+			env:             map[string]string{"TIMEOUT": "15s", "SERVER_PORT": ""},
+			expectedTimeout: 15 * time.Second,
+		},
+		{
+			name:            "FLAG OVERRIDES ENV",
+			file:            writeFile(t, "config.yaml", "server:\n  timeout: 5s\n  port: 9090\n"),
+			env:             map[string]string{"TIMEOUT": "15s"},
+			flag:            map[string]string{"server.timeout": "20s"},
+			expectedTimeout: 20 * time.Second,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				cfg, err := Load(Sources{File: test.file, Flags: test.flag, LookupEnv: envStub(test.env)})
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedTimeout, cfg.Server.Timeout)
+				assert.Equal(t, "9090", cfg.Server.Port, "empty env doesn't override file")
+			},
+		)
+	}
+}
+
+func TestLoad_NestedEnvAndLists(t *testing.T) {
+	file := writeFile(t, "config.yaml", "candles:\n  intervals: [1m, 1d]\nrate_limit:\n  read:\n    rps: 2.5\n")
+	cfg, err := Load(
+		Sources{File: file, LookupEnv: envStub(map[string]string{"RATE_LIMIT_WRITE_RPS": "10"})},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Minute, 24 * time.Hour}, cfg.Candles.Intervals)
+	assert.Equal(t, 3, cfg.RateLimit.Read.Limit().Burst)
+	assert.Equal(t, 10.0, cfg.RateLimit.Write.RPS)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	file := writeFile(t, "config.yaml", "server:\n  timeout: soon\n  unknown: 1\n")
+	_, err := Load(Sources{File: file, LookupEnv: envStub(map[string]string{"CLICKHOUSE_MAX_OPEN_CONNS": "many"})})
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorContains(t, err, "server.timeout")
+	assert.ErrorContains(t, err, "server.unknown")
+	assert.ErrorContains(t, err, "env CLICKHOUSE_MAX_OPEN_CONNS")
+	assert.NotContains(t, err.Error(), "server.timeout: must be positive", "parse error hides validation error")
+	assert.ErrorContains(t, err, "clickhouse.max_idle_conns: must be between", "other fields are validated")
+
+	_, err = Load(
+		Sources{
+			LookupEnv: envStub(
+				map[string]string{
+					"ENV":                        "staging",
+					"TRACING_SAMPLE_RATIO":       "2",
+					"SIGNING_REQUIRED":           "true",
+					"GRPC_PORT":                  "8080",
+					"SERVER_TLS_CLIENT_AUTH":     "require",
+					"SERVER_TLS_IDENTITIES_FILE": "identities.json",
+					"CLICKHOUSE_TLS_ENABLED":     "true",
+					"CLICKHOUSE_TLS_MIN_VERSION": "1.1",
+					"CLICKHOUSE_TLS_CERT_FILE":   "client.crt",
+					"CLICKHOUSE_TLS_SERVER_NAME": "clickhouse",
+					"CLICKHOUSE_TLS_CA_FILE":     "ca.pem",
+				},
+			),
+		},
+	)
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	for _, key := range []string{
+		"env", "tracing.sample_ratio", "signing.required", "server.tls.client_auth", "server.tls.client_ca_file",
+		"clickhouse.tls.min_version", "clickhouse.tls.key_file", "grpc.port",
+	} {
+		assert.ErrorContains(t, err, key+":")
+	}
+
+	_, err = Load(Sources{File: writeFile(t, "config.json", "{}"), LookupEnv: envStub(nil)})
+	assert.ErrorIs(t, err, ErrUnsupportedFile)
+}
+
+func TestPrint(t *testing.T) {
+	cfg, err := Load(Sources{LookupEnv: envStub(map[string]string{"CLICKHOUSE_ADMIN_PASSWORD": "secret"})})
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	assert.NoError(t, Print(&out, cfg))
+	assert.NotContains(t, out.String(), "secret")
+	assert.Contains(t, out.String(), "password: "+redacted)
+	assert.Contains(t, out.String(), "intervals: 1m,5m,1h,1d\n")
+	assert.True(t, strings.HasPrefix(out.String(), "env: prod\nlog:\n  level: info\nclickhouse:\n  user: clickhouse\n"))
+
+	reloaded, err := Load(
+		Sources{
+			File:      writeFile(t, "config.yaml", out.String()),
+			Flags:     map[string]string{"clickhouse.password": "secret"},
+			LookupEnv: envStub(nil),
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, cfg, reloaded)
+}
@@ -0,0 +1,324 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"github.com/plinkplenk/test-vortex/internal/candles"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnsupportedFile = errors.New("unsupported config file, expected .yaml, .yml or .toml")
+
+// Sources of config values, each source overrides the previous one:
+// field defaults, config file, environment variables and command line flags
+type Sources struct {
+	// File is YAML or TOML config file, it is not read if empty
+	File string
+	// Flags are values of command line flags by field key, see Flags
+	Flags map[string]string
+	// LookupEnv is os.LookupEnv if nil
+	LookupEnv func(key string) (string, bool)
+}
+
+// field is a leaf of Config
+type field struct {
+	// key is the path in config file like "server.timeout"
+	key          string
+	env          string
+	defaultValue string
+	secret       bool
+	reload       bool
+	value        reflect.Value
+}
+
+// fields returns leaves of v in declaration order
+func fields(v reflect.Value, keyPrefix, envPrefix string) []field {
+	return walk(v, keyPrefix, envPrefix, false)
+}
+
+func walk(v reflect.Value, keyPrefix, envPrefix string, reload bool) []field {
+	var result []field
+	t := v.Type()
+	for i := range t.NumField() {
+		structField := t.Field(i)
+		key := structField.Tag.Get("config")
+		if key == "" {
+			continue
+		}
+		if keyPrefix != "" {
+			key = keyPrefix + "." + key
+		}
+		env := structField.Tag.Get("env")
+		if envPrefix != "" && env != "" {
+			env = envPrefix + "_" + env
+		}
+		fieldReload := reload || structField.Tag.Get("reload") == "true"
+		if structField.Type.Kind() == reflect.Struct {
+			result = append(result, walk(v.Field(i), key, env, fieldReload)...)
+			continue
+		}
+		result = append(
+			result, field{
+				key:          key,
+				env:          env,
+				defaultValue: structField.Tag.Get("default"),
+				secret:       structField.Tag.Get("secret") == "true",
+				reload:       fieldReload,
+				value:        v.Field(i),
+			},
+		)
+	}
+	return result
+}
+
+func (f field) set(s string) error {
+	var err error
+	switch value := f.value.Addr().Interface().(type) {
+	case *string:
+		*value = s
+	case *ENV:
+		*value = ENV(s)
+	case *bool:
+		*value, err = strconv.ParseBool(s)
+	case *int:
+		*value, err = strconv.Atoi(s)
+	case *float64:
+		*value, err = strconv.ParseFloat(s, 64)
+	case *time.Duration:
+		*value, err = time.ParseDuration(s)
+	case *[]time.Duration:
+		*value, err = parseIntervals(s)
+	default:
+		panic(fmt.Sprintf("config: unsupported type %T of %s", value, f.key))
+	}
+	return err
+}
+
+func (f field) String() string {
+	switch value := f.value.Interface().(type) {
+	case string:
+		return value
+	case ENV:
+		return string(value)
+	case bool:
+		return strconv.FormatBool(value)
+	case int:
+		return strconv.Itoa(value)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case time.Duration:
+		return formatDuration(value)
+	case []time.Duration:
+		intervals := make([]string, len(value))
+		for i, interval := range value {
+			intervals[i] = formatInterval(interval)
+		}
+		return strings.Join(intervals, ",")
+	default:
+		panic(fmt.Sprintf("config: unsupported type %T of %s", value, f.key))
+	}
+}
+
+// redacted is String of the field, or a placeholder if the field is a secret
+func (f field) redacted() string {
+	if value := f.String(); !f.secret || value == "" {
+		return value
+	}
+	return redacted
+}
+
+func parseIntervals(intervals string) ([]time.Duration, error) {
+	var result []time.Duration
+	for _, s := range strings.Split(intervals, ",") {
+		interval, err := candles.ParseInterval(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, interval)
+	}
+	return result, nil
+}
+
+// formatDuration is time.Duration.String without zero minutes and seconds, "1h" instead of "1h0m0s"
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// formatInterval is inverse of candles.ParseInterval
+func formatInterval(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d >= day && d%day == 0 {
+		return strconv.Itoa(int(d/day)) + "d"
+	}
+	return formatDuration(d)
+}
+
+// Load builds Config from sources and validates it.
+// Returned error describes every invalid or unknown field
+func Load(sources Sources) (Config, error) {
+	lookupEnv := sources.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	var fileValues map[string]string
+	if sources.File != "" {
+		var err error
+		if fileValues, err = readFile(sources.File); err != nil {
+			return Config{}, fmt.Errorf("reading config file: %w", err)
+		}
+	}
+
+	var cfg Config
+	var errs []error
+	known := make(map[string]bool)
+	for _, f := range fields(reflect.ValueOf(&cfg).Elem(), "", "") {
+		known[f.key] = true
+		value, source := f.defaultValue, "default"
+		if v, ok := fileValues[f.key]; ok {
+			value, source = v, sources.File
+		}
+		if v, ok := lookupEnv(f.env); ok && f.env != "" && v != "" {
+			value, source = v, "env "+f.env
+		}
+		if v, ok := sources.Flags[f.key]; ok {
+			value, source = v, "flag -"+f.key
+		}
+		if value == "" {
+			continue
+		}
+		if err := f.set(value); err != nil {
+			errs = append(errs, fieldError{key: f.key, message: fmt.Sprintf("invalid value %q from %s", value, source)})
+		}
+	}
+	for key := range fileValues {
+		if !known[key] {
+			errs = append(errs, fieldError{key: key, message: "unknown field in " + sources.File})
+		}
+	}
+	// fields that failed to parse are zero, so their validation errors are not reported
+	for _, err := range cfg.validate() {
+		if !slices.ContainsFunc(errs, func(e error) bool { return e.(fieldError).key == err.(fieldError).key }) {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return Config{}, fmt.Errorf("%w:\n%w", ErrInvalidConfig, errors.Join(errs...))
+	}
+	return cfg, nil
+}
+
+// Flags defines command line flag for every field, named after its key.
+// Returned map is filled with values of the flags set when fs is parsed
+func Flags(fs *flag.FlagSet) map[string]string {
+	values := make(map[string]string)
+	for _, f := range fields(reflect.ValueOf(&Config{}).Elem(), "", "") {
+		usage := "overrides config file"
+		if f.env != "" {
+			usage += " and " + f.env
+		}
+		fs.Func(
+			f.key, usage, func(s string) error {
+				values[f.key] = s
+				return nil
+			},
+		)
+	}
+	return values
+}
+
+// readFile reads config file into values by field key
+func readFile(name string) (map[string]string, error) {
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]any
+	switch filepath.Ext(name) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &tree)
+	case ".toml":
+		err = toml.Unmarshal(content, &tree)
+	default:
+		return nil, ErrUnsupportedFile
+	}
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string)
+	flatten(tree, "", values)
+	return values, nil
+}
+
+// flatten joins nested keys with "." and lists with ","
+func flatten(tree map[string]any, prefix string, values map[string]string) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case map[string]any:
+			flatten(value, key, values)
+		case []any:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(value)
+		}
+	}
+}
+
+const redacted = "<redacted>"
+
+// Print writes cfg as YAML config file, values of secret fields are redacted
+func Print(w io.Writer, cfg Config) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := map[string]*yaml.Node{"": root}
+	for _, f := range fields(reflect.ValueOf(&cfg).Elem(), "", "") {
+		parent := root
+		path := strings.Split(f.key, ".")
+		for i := range len(path) - 1 {
+			prefix := strings.Join(path[:i+1], ".")
+			section, ok := sections[prefix]
+			if !ok {
+				section = &yaml.Node{Kind: yaml.MappingNode}
+				sections[prefix] = section
+				parent.Content = append(parent.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: path[i]}, section)
+			}
+			parent = section
+		}
+		value := f.redacted()
+		parent.Content = append(
+			parent.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: path[len(path)-1]},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value},
+		)
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Change of a field between two configs
+type Change struct {
+	Key string
+	// Old and New are field values, secrets are redacted
+	Old string
+	New string
+	// Reload is true if the change is applied without restart
+	Reload bool
+}
+
+// Diff returns changed fields of next compared to prev
+func Diff(prev, next Config) []Change {
+	prevFields := fields(reflect.ValueOf(&prev).Elem(), "", "")
+	nextFields := fields(reflect.ValueOf(&next).Elem(), "", "")
+	var changes []Change
+	for i, f := range prevFields {
+		if reflect.DeepEqual(f.value.Interface(), nextFields[i].value.Interface()) {
+			continue
+		}
+		changes = append(
+			changes, Change{Key: f.key, Old: f.redacted(), New: nextFields[i].redacted(), Reload: f.reload},
+		)
+	}
+	return changes
+}
+
+// WithReloaded returns c with reload fields taken from next, other fields are kept until restart
+func (c Config) WithReloaded(next Config) Config {
+	nextFields := fields(reflect.ValueOf(&next).Elem(), "", "")
+	for i, f := range fields(reflect.ValueOf(&c).Elem(), "", "") {
+		if f.reload {
+			f.value.Set(nextFields[i].value)
+		}
+	}
+	return c
+}
+
+// Watch calls onChange every time modification time or size of the file changes, it blocks until ctx is done.
+// The file is checked every interval
+func Watch(ctx context.Context, file string, interval time.Duration, onChange func()) {
+	stat := func() (time.Time, int64) {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return info.ModTime(), info.Size()
+	}
+	modified, size := stat()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if m, s := stat(); !m.Equal(modified) || s != size {
+				modified, size = m, s
+				onChange()
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	prev, err := Load(Sources{LookupEnv: envStub(nil)})
+	assert.NoError(t, err)
+	next, err := Load(
+		Sources{
+			LookupEnv: envStub(
+				map[string]string{
+					"LOG_LEVEL":                 "debug",
+					"RATE_LIMIT_READ_RPS":       "5",
+					"CLICKHOUSE_ADMIN_PASSWORD": "new",
+					"FEATURE_TCA":               "false",
+				},
+			),
+		},
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t, []Change{
+			{Key: "log.level", Old: "info", New: "debug", Reload: true},
+			{Key: "clickhouse.password", Old: redacted, New: redacted},
+			{Key: "rate_limit.read.rps", Old: "0", New: "5", Reload: true},
+			{Key: "features.tca", Old: "true", New: "false", Reload: true},
+		},
+		Diff(prev, next),
+	)
+
+	reloaded := prev.WithReloaded(next)
+	assert.Equal(t, "debug", reloaded.Log.Level)
+	assert.Equal(t, 5.0, reloaded.RateLimit.Read.RPS)
+	assert.False(t, reloaded.Features.TCA)
+	assert.Equal(t, "clickhouse", reloaded.Clickhouse.Password, "restart is required")
+	assert.Equal(t, "clickhouse", prev.Clickhouse.Password)
+	assert.True(t, prev.Features.TCA, "previous config is not modified")
+}
+
+func TestWatch(t *testing.T) {
+	file := writeFile(t, "config.yaml", "log:\n  level: info\n")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changed := make(chan struct{}, 1)
+	go Watch(
+		ctx, file, 10*time.Millisecond, func() {
+			changed <- struct{}{}
+		},
+	)
+
+	time.Sleep(30 * time.Millisecond)
+	if err := os.WriteFile(file, []byte("log:\n  level: debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatal("change is not detected")
+	}
+}
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format, use csv or parquet")
+	ErrUnknownColumn = errors.New("unknown column")
+)
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindFloat
+	kindTime
+)
+
+// column of order_history, field returns pointer to the field of the history the column is scanned to
+type column struct {
+	name  string
+	kind  kind
+	field func(h *orders.History) any
+}
+
+var columns = []column{
+	{name: "client_name", kind: kindString, field: func(h *orders.History) any { return &h.ClientName }},
+	{name: "exchange_name", kind: kindString, field: func(h *orders.History) any { return &h.ExchangeName }},
+	{name: "label", kind: kindString, field: func(h *orders.History) any { return &h.Label }},
+	{name: "pair", kind: kindString, field: func(h *orders.History) any { return &h.Pair }},
+	{name: "side", kind: kindString, field: func(h *orders.History) any { return &h.Side }},
+	{name: "type", kind: kindString, field: func(h *orders.History) any { return &h.Type }},
+	{name: "base_qty", kind: kindFloat, field: func(h *orders.History) any { return &h.BaseQty }},
+	{name: "price", kind: kindFloat, field: func(h *orders.History) any { return &h.Price }},
+	{
+		name: "algorithm_name_placed", kind: kindString,
+		field: func(h *orders.History) any { return &h.AlgorithmNamePlaced },
+	},
+	{name: "lowest_sell_prc", kind: kindFloat, field: func(h *orders.History) any { return &h.LowestSellPrc }},
+	{name: "highest_buy_prc", kind: kindFloat, field: func(h *orders.History) any { return &h.HighestBuyPrc }},
+	{
+		name: "commission_quote_qty", kind: kindFloat,
+		field: func(h *orders.History) any { return &h.CommissionQuoteQty },
+	},
+	{name: "time_placed", kind: kindTime, field: func(h *orders.History) any { return &h.TimePlaced }},
+}
+
+// Columns are names of order_history columns in table order, all of them are exported if none are selected
+func Columns() []string {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.name
+	}
+	return names
+}
+
+func lookupColumn(name string) (column, bool) {
+	i := slices.IndexFunc(columns, func(c column) bool { return c.name == name })
+	if i < 0 {
+		return column{}, false
+	}
+	return columns[i], true
+}
+
+// ParseColumns parses comma separated column names, empty string selects every column
+func ParseColumns(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return Columns(), nil
+	}
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := lookupColumn(name); !ok {
+			return nil, fmt.Errorf("%w %q, available columns: %s", ErrUnknownColumn, name, strings.Join(Columns(), ", "))
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Fields returns pointers to fields of the history the columns are scanned to, columns must be valid
+func Fields(h *orders.History, names []string) []any {
+	fields := make([]any, len(names))
+	for i, name := range names {
+		c, _ := lookupColumn(name)
+		fields[i] = c.field(h)
+	}
+	return fields
+}
+
+// Query selects exported order_history rows, empty fields are not filtered
+type Query struct {
+	ClientName   string
+	ExchangeName string
+	Label        string
+	Pair         string
+	Side         string
+	Algorithm    string
+	// From is inclusive and To is exclusive time placed
+	From time.Time
+	To   time.Time
+	// Columns are exported in the order they are listed
+	Columns []string
+}
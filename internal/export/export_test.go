@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/parquet-go/parquet-go"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"testing"
+	"time"
+)
+
+var history = []*orders.History{
+	{
+		Client:     orders.Client{ClientName: "client", ExchangeName: "exchange", Label: "label", Pair: "A_B"},
+		Side:       orders.SideBuy,
+		BaseQty:    1.5,
+		Price:      0.1,
+		TimePlaced: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	},
+	{
+		Client:     orders.Client{ClientName: "client", ExchangeName: "exchange", Label: "label", Pair: "A_B"},
+		Side:       orders.SideSell,
+		BaseQty:    2,
+		Price:      0.2,
+		TimePlaced: time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC),
+	},
+}
+
+func TestParseColumns(t *testing.T) {
+	testTable := []struct {
+		name          string
+		columns       string
+		expected      []string
+		expectedError error
+	}{
+		{name: "ALL", columns: "", expected: Columns()},
+		{name: "SELECTED", columns: "pair, price,pair", expected: []string{"pair", "price"}},
+		{name: "UNKNOWN", columns: "pair,secret", expectedError: ErrUnknownColumn},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				columns, err := ParseColumns(test.columns)
+				assert.ErrorIs(t, err, test.expectedError)
+				assert.Equal(t, test.expected, columns)
+			},
+		)
+	}
+}
+
+func write(t *testing.T, format string, columns []string, compress bool, rows []*orders.History) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := NewWriter(&buf, format, columns, compress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, h := range rows {
+		if err := writer.Write(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriter_CSV(t *testing.T) {
+	expected := "time_placed,side,price\n2024-01-02T03:04:05Z,buy,0.1\n2024-01-02T03:04:06Z,sell,0.2\n"
+	columns := []string{"time_placed", "side", "price"}
+	assert.Equal(t, expected, string(write(t, FormatCSV, columns, false, history)))
+	assert.Equal(t, "time_placed,side,price\n", string(write(t, FormatCSV, columns, false, nil)))
+
+	reader, err := gzip.NewReader(bytes.NewReader(write(t, FormatCSV, columns, true, history)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompressed, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, string(decompressed))
+}
+
+func TestWriter_Parquet(t *testing.T) {
+	type row struct {
+		Side       string    `parquet:"side"`
+		Price      float64   `parquet:"price"`
+		TimePlaced time.Time `parquet:"time_placed,timestamp(millisecond)"`
+	}
+	for _, compress := range []bool{false, true} {
+		b := write(t, FormatParquet, []string{"time_placed", "side", "price"}, compress, history)
+		reader := parquet.NewGenericReader[row](bytes.NewReader(b))
+		rows := make([]row, len(history)+1)
+		n, err := reader.Read(rows)
+		assert.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, len(history), n)
+		for i, h := range history {
+			assert.Equal(t, h.Side, rows[i].Side)
+			assert.Equal(t, h.Price, rows[i].Price)
+			assert.True(t, h.TimePlaced.Equal(rows[i].TimePlaced))
+		}
+		assert.Len(t, reader.Schema().Columns(), 3, "only selected columns are written")
+		assert.NoError(t, reader.Close())
+	}
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	_, err := NewWriter(io.Discard, "xlsx", Columns(), false)
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
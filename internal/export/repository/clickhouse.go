@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"math"
+	"strings"
+	"time"
+)
+
+type clickHouseRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseRepository(db clickhouse.Conn) Repository {
+	return clickHouseRepository{
+		db: db,
+	}
+}
+
+func (r clickHouseRepository) IterateOrderHistory(
+	ctx context.Context, query export.Query, fn func(h *orders.History) error,
+) (err error) {
+	var (
+		conditions []string
+		args       []any
+	)
+	where := func(condition string, arg any) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	for _, filter := range []struct {
+		column string
+		value  string
+	}{
+		{"client_name", query.ClientName},
+		{"exchange_name", query.ExchangeName},
+		{"label", query.Label},
+		{"pair", query.Pair},
+		{"side", query.Side},
+		{"algorithm_name_placed", query.Algorithm},
+	} {
+		if filter.value != "" {
+			where(filter.column+" = ?", filter.value)
+		}
+	}
+	if !query.From.IsZero() {
+		where("time_placed >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		where("time_placed < ?", query.To)
+	}
+	// column names are validated by export.ParseColumns
+	q := "SELECT " + strings.Join(query.Columns, ", ") + " FROM order_history"
+	if len(conditions) > 0 {
+		q += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	q += " ORDER BY time_placed"
+	// exports run longer than other queries, so ClickHouse limit follows the export deadline
+	// instead of max_execution_time of the connection
+	if deadline, ok := ctx.Deadline(); ok {
+		ctx = clickhouse.Context(
+			ctx, clickhouse.WithSettings(
+				clickhouse.Settings{"max_execution_time": int(math.Ceil(time.Until(deadline).Seconds()))},
+			),
+		)
+	}
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	var h orders.History
+	fields := export.Fields(&h, query.Columns)
+	for rows.Next() {
+		if err := rows.Scan(fields...); err != nil {
+			return err
+		}
+		if err := fn(&h); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+)
+
+type Repository interface {
+	// IterateOrderHistory calls fn for every row of the query in time placed order without loading all of them,
+	// only selected columns are set. The history is reused between calls, iteration stops on fn error
+	IterateOrderHistory(ctx context.Context, query export.Query, fn func(h *orders.History) error) error
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	exportRepository "github.com/plinkplenk/test-vortex/internal/export/repository"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"time"
+)
+
+//go:generate mockgen -source=export.go -destination=mocks/mock.go
+type ExportService interface {
+	// Export writes order history rows of the query to writer and returns the number of written rows.
+	// The writer is not closed
+	Export(ctx context.Context, query export.Query, writer export.Writer) (uint64, error)
+}
+
+type exportService struct {
+	repository exportRepository.Repository
+	timeout    time.Duration
+}
+
+// New creates service, timeout limits the whole export rather than a single query
+func New(repository exportRepository.Repository, timeout time.Duration) ExportService {
+	return exportService{
+		repository: repository,
+		timeout:    timeout,
+	}
+}
+
+func (s exportService) Export(ctx context.Context, query export.Query, writer export.Writer) (uint64, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	var written uint64
+	err := s.repository.IterateOrderHistory(
+		c, query, func(h *orders.History) error {
+			if err := writer.Write(h); err != nil {
+				return err
+			}
+			written++
+			return nil
+		},
+	)
+	return written, err
+}
@@ -0,0 +1,182 @@
+package export
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"github.com/parquet-go/parquet-go"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"io"
+	"strconv"
+	"time"
+)
+
+// rowGroupSize bounds rows buffered by parquet writer before they are written out
+const rowGroupSize = 64 * 1024
+
+// Writer writes exported rows. Close writes buffered rows and must be called after the last one,
+// it doesn't close the underlying writer
+type Writer interface {
+	Write(h *orders.History) error
+	Close() error
+}
+
+// NewWriter creates writer of the format with the columns, columns must be valid.
+// CSV is gzipped as a whole, parquet compresses columns with gzip instead of snappy
+func NewWriter(w io.Writer, format string, columnNames []string, compress bool) (Writer, error) {
+	selected := make([]column, len(columnNames))
+	for i, name := range columnNames {
+		selected[i], _ = lookupColumn(name)
+	}
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, selected, compress), nil
+	case FormatParquet:
+		return newParquetWriter(w, selected, compress), nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// ContentType returns media type of exported file
+func ContentType(format string, compress bool) string {
+	switch {
+	case format == FormatParquet:
+		return "application/vnd.apache.parquet"
+	case compress:
+		return "application/gzip"
+	default:
+		return "text/csv"
+	}
+}
+
+// FileName returns name of exported file like "order_history.csv.gz"
+func FileName(format string, compress bool) string {
+	name := "order_history." + format
+	if compress && format == FormatCSV {
+		name += ".gz"
+	}
+	return name
+}
+
+type csvWriter struct {
+	writer  *csv.Writer
+	gzip    *gzip.Writer
+	columns []column
+	record  []string
+	// header is written with the first row, so nothing is written if the export fails before it
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer, columns []column, compress bool) *csvWriter {
+	writer := &csvWriter{columns: columns, record: make([]string, len(columns))}
+	if compress {
+		writer.gzip = gzip.NewWriter(w)
+		w = writer.gzip
+	}
+	writer.writer = csv.NewWriter(w)
+	return writer
+}
+
+func (w *csvWriter) writeHeader() error {
+	w.headerWritten = true
+	for i, c := range w.columns {
+		w.record[i] = c.name
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Write(h *orders.History) error {
+	if !w.headerWritten {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	for i, c := range w.columns {
+		switch value := c.field(h).(type) {
+		case *string:
+			w.record[i] = *value
+		case *float64:
+			w.record[i] = strconv.FormatFloat(*value, 'f', -1, 64)
+		case *time.Time:
+			w.record[i] = value.UTC().Format(time.RFC3339)
+		}
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	if !w.headerWritten {
+		if err := w.writeHeader(); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	if err := w.writer.Error(); err != nil {
+		return err
+	}
+	if w.gzip != nil {
+		return w.gzip.Close()
+	}
+	return nil
+}
+
+type parquetWriter struct {
+	writer  *parquet.Writer
+	columns []column
+	// indexes are leaf column indexes of the columns in the schema, they are ordered by name there
+	indexes []int
+}
+
+func newParquetWriter(w io.Writer, columns []column, compress bool) *parquetWriter {
+	group := parquet.Group{}
+	for _, c := range columns {
+		switch c.kind {
+		case kindString:
+			group[c.name] = parquet.String()
+		case kindFloat:
+			group[c.name] = parquet.Leaf(parquet.DoubleType)
+		case kindTime:
+			group[c.name] = parquet.Timestamp(parquet.Millisecond)
+		}
+	}
+	schema := parquet.NewSchema("order_history", group)
+	indexes := make([]int, len(columns))
+	for index, path := range schema.Columns() {
+		for i, c := range columns {
+			if c.name == path[0] {
+				indexes[i] = index
+			}
+		}
+	}
+	var codec parquet.WriterOption = parquet.Compression(&parquet.Snappy)
+	if compress {
+		codec = parquet.Compression(&parquet.Gzip)
+	}
+	return &parquetWriter{
+		writer:  parquet.NewWriter(w, schema, codec, parquet.MaxRowsPerRowGroup(rowGroupSize)),
+		columns: columns,
+		indexes: indexes,
+	}
+}
+
+func (w *parquetWriter) Write(h *orders.History) error {
+	row := make(parquet.Row, len(w.columns))
+	for i, c := range w.columns {
+		var value parquet.Value
+		switch field := c.field(h).(type) {
+		case *string:
+			value = parquet.ByteArrayValue([]byte(*field))
+		case *float64:
+			value = parquet.DoubleValue(*field)
+		case *time.Time:
+			value = parquet.Int64Value(field.UnixMilli())
+		}
+		row[w.indexes[i]] = value.Level(0, 0, w.indexes[i])
+	}
+	_, err := w.writer.WriteRows([]parquet.Row{row})
+	return err
+}
+
+func (w *parquetWriter) Close() error {
+	return w.writer.Close()
+}
@@ -0,0 +1,64 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	healthRepository "github.com/plinkplenk/test-vortex/internal/health/repository"
+)
+
+var ErrSchemaOutdated = errors.New("database schema is older than the service expects")
+
+// PingCheck checks that database responds
+func PingCheck(repository healthRepository.Repository) Check {
+	return func(ctx context.Context) (any, error) {
+		return nil, repository.Ping(ctx)
+	}
+}
+
+type MigrationDetails struct {
+	Current  int64 `json:"current"`
+	Expected int64 `json:"expected"`
+}
+
+// MigrationCheck checks that migrations up to expected version are applied, newer versions are allowed
+// so the previous release keeps working during rollout
+func MigrationCheck(repository healthRepository.Repository, expected int64) Check {
+	return func(ctx context.Context) (any, error) {
+		current, err := repository.GetMigrationVersion(ctx)
+		if err != nil {
+			return nil, err
+		}
+		details := MigrationDetails{Current: current, Expected: expected}
+		if current < expected {
+			return details, ErrSchemaOutdated
+		}
+		return details, nil
+	}
+}
+
+// StatsProvider is implemented by clickhouse.Conn
+type StatsProvider interface {
+	Stats() driver.Stats
+}
+
+type PoolDetails struct {
+	Open         int `json:"open"`
+	Idle         int `json:"idle"`
+	MaxOpenConns int `json:"maxOpenConns"`
+	// Saturated is true when all connections are busy and queries wait for a connection
+	Saturated bool `json:"saturated"`
+}
+
+// PoolCheck reports connection pool usage, saturation is reported but doesn't fail the check
+func PoolCheck(pool StatsProvider) Check {
+	return func(_ context.Context) (any, error) {
+		stats := pool.Stats()
+		return PoolDetails{
+			Open:         stats.Open,
+			Idle:         stats.Idle,
+			MaxOpenConns: stats.MaxOpenConns,
+			Saturated:    stats.Open >= stats.MaxOpenConns && stats.Idle == 0,
+		}, nil
+	}
+}
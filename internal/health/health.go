@@ -0,0 +1,97 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusFail Status = "fail"
+)
+
+var ErrDraining = errors.New("server is shutting down")
+
+// Check returns details about the dependency, and error if it is not usable
+type Check func(ctx context.Context) (details any, err error)
+
+type Result struct {
+	Status  Status `json:"status"`
+	Error   string `json:"error,omitempty"`
+	Details any    `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Health runs readiness checks of dependencies
+type Health struct {
+	mu       sync.RWMutex
+	checks   map[string]Check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// New creates Health, every check must finish within timeout
+func New(timeout time.Duration) *Health {
+	return &Health{checks: make(map[string]Check), timeout: timeout}
+}
+
+func (h *Health) Register(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// SetDraining makes the service not ready, so it gets no new traffic while in-flight requests finish
+func (h *Health) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Ready runs all checks concurrently, the report fails if any check fails or the service is draining
+func (h *Health) Ready(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := make(map[string]Check, len(h.checks))
+	for name, check := range h.checks {
+		checks[name] = check
+	}
+	h.mu.RUnlock()
+
+	c, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks)+1)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			details, err := check(c)
+			result := Result{Status: StatusOK, Details: details}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+		}()
+	}
+	wg.Wait()
+	if h.draining.Load() {
+		report.Checks["draining"] = Result{Status: StatusFail, Error: ErrDraining.Error()}
+	}
+	for _, result := range report.Checks {
+		if result.Status == StatusFail {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHealth_Ready(t *testing.T) {
+	ok := func(context.Context) (any, error) { return "details", nil }
+	failing := func(context.Context) (any, error) { return nil, errors.New("connection refused") }
+	slow := func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	testTable := []struct {
+		name     string
+		checks   map[string]Check
+		draining bool
+		expected Report
+	}{
+		{
+			name:   "READY",
+			checks: map[string]Check{"clickhouse": ok},
+			expected: Report{
+				Status: StatusOK,
+				Checks: map[string]Result{"clickhouse": {Status: StatusOK, Details: "details"}},
+			},
+		},
+		{
+			name:   "FAILING CHECK",
+			checks: map[string]Check{"clickhouse": failing, "migrations": ok},
+			expected: Report{
+				Status: StatusFail,
+				Checks: map[string]Result{
+					"clickhouse": {Status: StatusFail, Error: "connection refused"},
+					"migrations": {Status: StatusOK, Details: "details"},
+				},
+			},
+		},
+		{
+			name:   "TIMEOUT",
+			checks: map[string]Check{"clickhouse": slow},
+			expected: Report{
+				Status: StatusFail,
+				Checks: map[string]Result{"clickhouse": {Status: StatusFail, Error: "context deadline exceeded"}},
+			},
+		},
+		{
+			name:     "DRAINING",
+			checks:   map[string]Check{"clickhouse": ok},
+			draining: true,
+			expected: Report{
+				Status: StatusFail,
+				Checks: map[string]Result{
+					"clickhouse": {Status: StatusOK, Details: "details"},
+					"draining":   {Status: StatusFail, Error: ErrDraining.Error()},
+				},
+			},
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				h := New(10 * time.Millisecond)
+				for name, check := range test.checks {
+					h.Register(name, check)
+				}
+				if test.draining {
+					h.SetDraining()
+				}
+				assert.Equal(t, test.expected, h.Ready(context.Background()))
+			},
+		)
+	}
+}
+
+type healthRepositoryStub struct {
+	version int64
+}
+
+func (r healthRepositoryStub) Ping(_ context.Context) error {
+	return nil
+}
+
+func (r healthRepositoryStub) GetMigrationVersion(_ context.Context) (int64, error) {
+	return r.version, nil
+}
+
+func TestMigrationCheck(t *testing.T) {
+	details, err := MigrationCheck(healthRepositoryStub{version: 2}, 3)(context.Background())
+	assert.ErrorIs(t, err, ErrSchemaOutdated)
+	assert.Equal(t, MigrationDetails{Current: 2, Expected: 3}, details)
+
+	_, err = MigrationCheck(healthRepositoryStub{version: 4}, 3)(context.Background())
+	assert.NoError(t, err, "newer schema is allowed")
+}
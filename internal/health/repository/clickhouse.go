@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+type clickHouseRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseRepository(db clickhouse.Conn) Repository {
+	return clickHouseRepository{
+		db: db,
+	}
+}
+
+func (r clickHouseRepository) Ping(ctx context.Context) error {
+	return r.db.Ping(ctx)
+}
+
+func (r clickHouseRepository) GetMigrationVersion(ctx context.Context) (int64, error) {
+	// goose appends a row on every up and down, a version is applied if its latest row is
+	query := `
+		SELECT max(version_id)
+		FROM (
+			SELECT version_id
+			FROM goose_db_version
+			GROUP BY version_id
+			HAVING argMax(is_applied, tstamp) = 1
+		)`
+	var version int64
+	if err := r.db.QueryRow(ctx, query).Scan(&version); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+type Repository interface {
+	Ping(ctx context.Context) error
+	// GetMigrationVersion returns the newest applied goose migration version
+	GetMigrationVersion(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,190 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/api/schemas"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	ordersRepository "github.com/plinkplenk/test-vortex/internal/orders/repository"
+	"io"
+	"log/slog"
+)
+
+// DefaultBatchSize is the number of rows inserted at once if batch size is not set
+const DefaultBatchSize = 50000
+
+var ErrTimeNotProvided = errors.New("time not provided")
+
+// historyFields are named like order_history columns
+func historyFields() []string {
+	return export.Columns()
+}
+
+// Params of import, Kind and Format are required
+type Params struct {
+	Kind      string
+	Format    string
+	Mapping   Mapping
+	BatchSize int
+	// Rejected is called with every row that is not imported and the reason
+	Rejected func(line int, err error)
+	Logger   *slog.Logger
+}
+
+// Report counts rows of the import
+type Report struct {
+	Rows     uint64
+	Imported uint64
+	Rejected uint64
+}
+
+type Importer struct {
+	repository ordersRepository.Repository
+	params     Params
+	fields     []string
+	report     Report
+}
+
+// New creates importer writing to the repository, batches are inserted as they are filled
+func New(repository ordersRepository.Repository, params Params) (*Importer, error) {
+	fields, err := Fields(params.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := params.Mapping.Validate(params.Kind); err != nil {
+		return nil, err
+	}
+	if params.BatchSize <= 0 {
+		params.BatchSize = DefaultBatchSize
+	}
+	if params.Rejected == nil {
+		params.Rejected = func(int, error) {}
+	}
+	if params.Logger == nil {
+		params.Logger = slog.Default()
+	}
+	return &Importer{repository: repository, params: params, fields: fields}, nil
+}
+
+// Import reads rows of r and inserts valid ones. Rows of a failed batch are not counted as imported,
+// rows of batches inserted before the failure are kept
+func (im *Importer) Import(ctx context.Context, r io.Reader) (Report, error) {
+	im.report = Report{}
+	rows, err := newReader(r, im.params.Format)
+	if err != nil {
+		return im.report, err
+	}
+	switch im.params.Kind {
+	case KindHistory:
+		batch := make([]*orders.History, 0, im.params.BatchSize)
+		err = readBatches(
+			im, rows, &batch, im.parseHistory, func(batch []*orders.History) error {
+				return im.repository.CreateOrders(ctx, batch)
+			},
+		)
+	case KindDepth:
+		batch := make([]orders.BookLevel, 0, im.params.BatchSize)
+		err = readBatches(
+			im, rows, &batch, im.parseLevel, func(batch []orders.BookLevel) error {
+				return im.repository.CreateOrderBookLevels(ctx, batch)
+			},
+		)
+	}
+	return im.report, err
+}
+
+// readBatches parses rows into batch and inserts it when it is full and after the last row
+func readBatches[T any](
+	im *Importer, rows reader, batch *[]T, parse func(row map[string]string) (T, error), insert func([]T) error,
+) error {
+	flush := func() error {
+		if len(*batch) == 0 {
+			return nil
+		}
+		if err := insert(*batch); err != nil {
+			return fmt.Errorf("inserting batch ending at line %d: %w", rows.Line(), err)
+		}
+		im.report.Imported += uint64(len(*batch))
+		*batch = (*batch)[:0]
+		im.params.Logger.Info(
+			"Import progress",
+			"rows", im.report.Rows,
+			"imported", im.report.Imported,
+			"rejected", im.report.Rejected,
+		)
+		return nil
+	}
+	for {
+		row, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			return flush()
+		}
+		if err == nil {
+			var value T
+			if value, err = parse(row); err == nil {
+				im.report.Rows++
+				*batch = append(*batch, value)
+				if len(*batch) >= im.params.BatchSize {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		} else if !errors.Is(err, ErrInvalidRow) {
+			return err
+		}
+		im.report.Rows++
+		im.report.Rejected++
+		im.params.Rejected(rows.Line(), err)
+	}
+}
+
+// parseHistory parses order of the row and validates it like orders of the api
+func (im *Importer) parseHistory(row map[string]string) (*orders.History, error) {
+	h := &orders.History{}
+	for i, pointer := range export.Fields(h, im.fields) {
+		field := im.fields[i]
+		if err := im.params.Mapping.set(field, pointer, im.params.Mapping.value(row, field)); err != nil {
+			return nil, err
+		}
+	}
+	if err := validators.ValidateClientHistory(
+		schemas.ClientHistoryCreate{Client: h.Client, OrderHistory: *h},
+	); err != nil {
+		return nil, err
+	}
+	if h.TimePlaced.IsZero() {
+		return nil, ErrTimeNotProvided
+	}
+	return h, nil
+}
+
+// parseLevel parses order book level of the row and validates it like order books of the api
+func (im *Importer) parseLevel(row map[string]string) (orders.BookLevel, error) {
+	var level orders.BookLevel
+	pointers := map[string]any{
+		"exchange_name": &level.ExchangeName,
+		"pair":          &level.Pair,
+		"price":         &level.Price,
+		"base_qty":      &level.BaseQty,
+		"time_created":  &level.TimeCreated,
+	}
+	for _, field := range im.fields {
+		if err := im.params.Mapping.set(field, pointers[field], im.params.Mapping.value(row, field)); err != nil {
+			return level, err
+		}
+	}
+	if err := validators.ValidateOrderBook(
+		schemas.OrderBookCreate{ExchangeName: level.ExchangeName, Pair: level.Pair, Depth: []orders.Depth{level.Depth}},
+	); err != nil {
+		return level, err
+	}
+	if level.TimeCreated.IsZero() {
+		return level, ErrTimeNotProvided
+	}
+	return level, nil
+}
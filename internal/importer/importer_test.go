@@ -0,0 +1,186 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	ordersRepository "github.com/plinkplenk/test-vortex/internal/orders/repository"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+var loggerStub = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// repositoryStub records inserted batches, other methods are not used by imports
+type repositoryStub struct {
+	ordersRepository.Repository
+	orders  [][]*orders.History
+	levels  [][]orders.BookLevel
+	failing bool
+}
+
+func (r *repositoryStub) CreateOrders(_ context.Context, orderHistories []*orders.History) error {
+	if r.failing {
+		return errors.New("db is down")
+	}
+	r.orders = append(r.orders, append([]*orders.History(nil), orderHistories...))
+	return nil
+}
+
+func (r *repositoryStub) CreateOrderBookLevels(_ context.Context, levels []orders.BookLevel) error {
+	if r.failing {
+		return errors.New("db is down")
+	}
+	r.levels = append(r.levels, append([]orders.BookLevel(nil), levels...))
+	return nil
+}
+
+type rejection struct {
+	line int
+	err  error
+}
+
+func importRows(t *testing.T, repository *repositoryStub, params Params, input string) (Report, []rejection, error) {
+	t.Helper()
+	var rejected []rejection
+	params.Rejected = func(line int, err error) {
+		rejected = append(rejected, rejection{line: line, err: err})
+	}
+	params.Logger = loggerStub
+	im, err := New(repository, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := im.Import(context.Background(), strings.NewReader(input))
+	return report, rejected, err
+}
+
+func TestImporter_History(t *testing.T) {
+	input := "\ufeffAccount,Symbol,Side,Qty,Price,Time\n" +
+		"client,A_B,buy,1.5,0.1,1704164645\n" +
+		"client,A_B,sell,2,0.2,1704164646.5\n" +
+		"client,A_B,sell,two,0.2,1704164647\n" +
+		",,,,,\n" +
+		"client,A_B,buy\n" +
+		"client,A_B,buy,1,0.3,\n" +
+		"other,B_C,buy,3,0.4,1704164648\n"
+	mapping := Mapping{
+		Columns: map[string]string{
+			"client_name": "Account",
+			"pair":        "Symbol",
+			"side":        "Side",
+			"base_qty":    "Qty",
+			"price":       "Price",
+			"time_placed": "Time",
+		},
+		Defaults:   map[string]string{"exchange_name": "exchange", "type": "limit"},
+		TimeFormat: TimeUnix,
+	}
+	repository := &repositoryStub{}
+	report, rejected, err := importRows(
+		t, repository, Params{Kind: KindHistory, Format: FormatCSV, Mapping: mapping, BatchSize: 2}, input,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, Report{Rows: 7, Imported: 3, Rejected: 4}, report)
+
+	assert.Len(t, repository.orders, 2, "rows are inserted in batches")
+	assert.Len(t, repository.orders[0], 2)
+	assert.Equal(
+		t,
+		&orders.History{
+			Client:     orders.Client{ClientName: "client", ExchangeName: "exchange", Pair: "A_B"},
+			Side:       orders.SideBuy,
+			Type:       "limit",
+			BaseQty:    1.5,
+			Price:      0.1,
+			TimePlaced: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		repository.orders[0][0],
+	)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 6, 5e8, time.UTC), repository.orders[0][1].TimePlaced)
+	assert.Equal(t, "other", repository.orders[1][0].ClientName)
+
+	assert.Equal(t, []int{4, 5, 6, 7}, []int{rejected[0].line, rejected[1].line, rejected[2].line, rejected[3].line})
+	assert.ErrorIs(t, rejected[0].err, ErrInvalidValue)
+	assert.ErrorIs(t, rejected[1].err, ErrTimeNotProvided)
+	assert.ErrorIs(t, rejected[2].err, ErrInvalidRow)
+	assert.ErrorIs(t, rejected[3].err, ErrTimeNotProvided)
+}
+
+func TestImporter_Depth(t *testing.T) {
+	input := `{"exchange_name": "exchange", "pair": "A_B", "price": 0.1, "base_qty": "1", "time_created": "2024-01-02T03:04:05Z"}
+
+{"exchange_name": "exchange", "pair": "A_B", "price": 0.2, "base_qty": 2, "time_created": "2024-01-02T03:04:05Z"}
+{"exchange_name": "exchange", "price": 0.2}
+{"exchange_name":
+`
+	repository := &repositoryStub{}
+	report, rejected, err := importRows(t, repository, Params{Kind: KindDepth, Format: FormatNDJSON}, input)
+	assert.NoError(t, err)
+	assert.Equal(t, Report{Rows: 4, Imported: 2, Rejected: 2}, report)
+	assert.Equal(
+		t,
+		[][]orders.BookLevel{
+			{
+				{
+					Depth:        orders.Depth{Price: 0.1, BaseQty: 1},
+					ExchangeName: "exchange",
+					Pair:         "A_B",
+					TimeCreated:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+				{
+					Depth:        orders.Depth{Price: 0.2, BaseQty: 2},
+					ExchangeName: "exchange",
+					Pair:         "A_B",
+					TimeCreated:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+			},
+		},
+		repository.levels,
+	)
+	assert.Equal(t, 4, rejected[0].line)
+	assert.ErrorIs(t, rejected[0].err, validators.ErrPairNotProvided)
+	assert.Equal(t, 5, rejected[1].line)
+	assert.ErrorIs(t, rejected[1].err, ErrInvalidRow)
+}
+
+func TestImporter_InsertError(t *testing.T) {
+	input := "client_name,exchange_name,pair,time_placed\nclient,exchange,A_B,2024-01-02T03:04:05Z\n"
+	report, _, err := importRows(
+		t, &repositoryStub{failing: true}, Params{Kind: KindHistory, Format: FormatCSV}, input,
+	)
+	assert.Error(t, err)
+	assert.Equal(t, Report{Rows: 1}, report)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	testTable := []struct {
+		name          string
+		params        Params
+		expectedError error
+	}{
+		{name: "UNKNOWN KIND", params: Params{Kind: "trades"}, expectedError: ErrUnknownKind},
+		{
+			name:          "UNKNOWN FIELD",
+			params:        Params{Kind: KindDepth, Mapping: Mapping{Columns: map[string]string{"side": "Side"}}},
+			expectedError: ErrUnknownField,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				_, err := New(&repositoryStub{}, test.params)
+				assert.ErrorIs(t, err, test.expectedError)
+			},
+		)
+	}
+	im, err := New(&repositoryStub{}, Params{Kind: KindDepth, Format: "xml"})
+	assert.NoError(t, err)
+	_, err = im.Import(context.Background(), strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	KindHistory = "history"
+	KindDepth   = "depth"
+)
+
+const (
+	// TimeUnix and TimeUnixMilli are time formats of seconds and milliseconds since epoch
+	TimeUnix      = "unix"
+	TimeUnixMilli = "unix_ms"
+)
+
+var (
+	ErrUnknownKind  = errors.New("unknown kind, use history or depth")
+	ErrUnknownField = errors.New("unknown field")
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// depthFields are fields of order book levels, they are named like order_history columns
+var depthFields = []string{"exchange_name", "pair", "price", "base_qty", "time_created"}
+
+// Fields returns names of fields of the kind, files have columns of the same name unless they are mapped
+func Fields(kind string) ([]string, error) {
+	switch kind {
+	case KindHistory:
+		return historyFields(), nil
+	case KindDepth:
+		return slices.Clone(depthFields), nil
+	default:
+		return nil, ErrUnknownKind
+	}
+}
+
+// Mapping maps fields to columns of imported files
+type Mapping struct {
+	// Columns are source columns by field name, fields that are not listed are read from the column of their name
+	Columns map[string]string `yaml:"columns"`
+	// Defaults are values of fields that are missing or empty in a row
+	Defaults map[string]string `yaml:"defaults"`
+	// TimeFormat is Go layout of time values, "unix" or "unix_ms", RFC3339 if empty
+	TimeFormat string `yaml:"time_format"`
+}
+
+// LoadMapping reads YAML or JSON mapping file
+func LoadMapping(path string) (Mapping, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Mapping{}, err
+	}
+	var mapping Mapping
+	if err := yaml.Unmarshal(b, &mapping); err != nil {
+		return Mapping{}, fmt.Errorf("parsing mapping %s: %w", path, err)
+	}
+	return mapping, nil
+}
+
+// Validate checks that mapped fields exist in the kind
+func (m Mapping) Validate(kind string) error {
+	fields, err := Fields(kind)
+	if err != nil {
+		return err
+	}
+	for _, mapped := range []map[string]string{m.Columns, m.Defaults} {
+		for field := range mapped {
+			if !slices.Contains(fields, field) {
+				return fmt.Errorf("%w %q, fields of %s: %s", ErrUnknownField, field, kind, strings.Join(fields, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// column returns source column of the field
+func (m Mapping) column(field string) string {
+	if column, ok := m.Columns[field]; ok {
+		return column
+	}
+	return field
+}
+
+// value returns value of the field in the record or its default
+func (m Mapping) value(record map[string]string, field string) string {
+	if value := strings.TrimSpace(record[m.column(field)]); value != "" {
+		return value
+	}
+	return m.Defaults[field]
+}
+
+func (m Mapping) parseTime(value string) (time.Time, error) {
+	switch m.TimeFormat {
+	case "":
+		return time.Parse(time.RFC3339, value)
+	case TimeUnix, TimeUnixMilli:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if m.TimeFormat == TimeUnix {
+			number *= 1000
+		}
+		return time.UnixMilli(int64(number)).UTC(), nil
+	default:
+		return time.Parse(m.TimeFormat, value)
+	}
+}
+
+// set parses value of the field to the pointer, empty values are left zero
+func (m Mapping) set(field string, pointer any, value string) error {
+	if value == "" {
+		return nil
+	}
+	var err error
+	switch p := pointer.(type) {
+	case *string:
+		*p = value
+	case *float64:
+		*p, err = strconv.ParseFloat(value, 64)
+	case *time.Time:
+		*p, err = m.parseTime(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%w of %s %q", ErrInvalidValue, field, value)
+	}
+	return nil
+}
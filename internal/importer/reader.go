@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// maxLineSize bounds NDJSON lines, longer lines fail the import
+const maxLineSize = 16 * 1024 * 1024
+
+var (
+	ErrUnknownFormat = errors.New("unknown format, use csv or ndjson")
+	ErrInvalidRow    = errors.New("invalid row")
+)
+
+// reader reads rows of a file as values by column. Rows that can't be read return ErrInvalidRow
+// and reading goes on, other errors stop the import. io.EOF is returned after the last row
+type reader interface {
+	Read() (map[string]string, error)
+	// Line is the line of the last read row
+	Line() int
+}
+
+func newReader(r io.Reader, format string) (reader, error) {
+	switch format {
+	case FormatCSV:
+		return &csvReader{reader: csv.NewReader(r)}, nil
+	case FormatNDJSON:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+		return &ndjsonReader{scanner: scanner}, nil
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// csvReader reads rows by the columns of the header
+type csvReader struct {
+	reader *csv.Reader
+	header []string
+	line   int
+}
+
+func (r *csvReader) Read() (map[string]string, error) {
+	if r.header == nil {
+		header, err := r.reader.Read()
+		if err != nil {
+			return nil, err
+		}
+		// header is copied, records are reused. Exports of spreadsheets often start with byte order mark
+		r.header = append([]string(nil), header...)
+		r.header[0] = strings.TrimPrefix(r.header[0], "\ufeff")
+		r.reader.ReuseRecord = true
+	}
+	record, err := r.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		r.line = parseErr.StartLine
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRow, parseErr.Err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.line, _ = r.reader.FieldPos(0)
+	row := make(map[string]string, len(r.header))
+	for i, column := range r.header {
+		row[column] = record[i]
+	}
+	return row, nil
+}
+
+func (r *csvReader) Line() int {
+	return r.line
+}
+
+// ndjsonReader reads rows of JSON objects by line, values other than strings are read as their JSON
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) Read() (map[string]string, error) {
+	for r.scanner.Scan() {
+		r.line++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(line, &object); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidRow, err)
+		}
+		row := make(map[string]string, len(object))
+		for key, value := range object {
+			var s string
+			if err := json.Unmarshal(value, &s); err == nil {
+				row[key] = s
+			} else if string(value) != "null" {
+				row[key] = string(value)
+			}
+		}
+		return row, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (r *ndjsonReader) Line() int {
+	return r.line
+}
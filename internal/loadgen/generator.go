@@ -0,0 +1,155 @@
+package loadgen
+
+import (
+	"errors"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/api/schemas"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var ErrInvalidMarket = errors.New("clients, labels, pairs, depth and start mid must be positive, spread and volatility not negative")
+
+// MarketParams shape synthetic order books and orders
+type MarketParams struct {
+	Exchange string
+	Clients  int
+	Labels   int
+	Pairs    int
+	// Depth is the number of levels of a book
+	Depth int
+	// SpreadBps is the spread between the best bid and ask in basis points of mid
+	SpreadBps float64
+	// VolatilityBps is standard deviation of a mid step in basis points
+	VolatilityBps float64
+	// StartMid is mid of every pair before the first step
+	StartMid float64
+	Seed     int64
+}
+
+// Generator generates order books and orders of pairs with mid prices moving by random walk,
+// it is safe for concurrent use
+type Generator struct {
+	mu     sync.Mutex
+	params MarketParams
+	random *rand.Rand
+	mids   []float64
+	now    func() time.Time
+}
+
+func NewGenerator(params MarketParams) (*Generator, error) {
+	if params.Clients <= 0 || params.Labels <= 0 || params.Pairs <= 0 || params.Depth <= 0 || !(params.StartMid > 0) ||
+		!(params.SpreadBps >= 0) || !(params.VolatilityBps >= 0) {
+		return nil, ErrInvalidMarket
+	}
+	mids := make([]float64, params.Pairs)
+	for i := range mids {
+		mids[i] = params.StartMid
+	}
+	return &Generator{
+		params: params,
+		random: rand.New(rand.NewSource(params.Seed)),
+		mids:   mids,
+		now:    time.Now,
+	}, nil
+}
+
+// Pair returns name of the pair of the index like "P0_USD"
+func Pair(i int) string {
+	return fmt.Sprintf("P%d_USD", i)
+}
+
+// Client returns name of the client of the index
+func Client(i int) string {
+	return fmt.Sprintf("client-%d", i)
+}
+
+// Label returns name of the label of the index
+func Label(i int) string {
+	return fmt.Sprintf("label-%d", i)
+}
+
+// step moves mid of the pair and returns it, mid stays positive as the walk is geometric
+func (g *Generator) step(pair int) float64 {
+	g.mids[pair] *= math.Exp(g.random.NormFloat64() * g.params.VolatilityBps / 1e4)
+	return g.mids[pair]
+}
+
+// Book steps mid of a random pair and returns its book, levels go away from mid by half of the spread
+func (g *Generator) Book() schemas.OrderBookCreate {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.book(g.random.Intn(g.params.Pairs))
+}
+
+// BookOf returns book of the pair of the index at its current mid
+func (g *Generator) BookOf(pair int) schemas.OrderBookCreate {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.book(pair)
+}
+
+func (g *Generator) book(pair int) schemas.OrderBookCreate {
+	mid := g.step(pair)
+	halfSpread := mid * g.params.SpreadBps / 2e4
+	depth := make([]orders.Depth, g.params.Depth)
+	for i := range depth {
+		depth[i] = orders.Depth{
+			Price:   mid + halfSpread*float64(i+1),
+			BaseQty: 1 + g.random.ExpFloat64(),
+		}
+	}
+	return schemas.OrderBookCreate{ExchangeName: g.params.Exchange, Pair: Pair(pair), Depth: depth}
+}
+
+// Order returns order of a random client, label and pair placed at the touch of the current mid
+func (g *Generator) Order() schemas.ClientHistoryCreate {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	pair := g.random.Intn(g.params.Pairs)
+	mid := g.mids[pair]
+	halfSpread := mid * g.params.SpreadBps / 2e4
+	side, price := orders.SideBuy, mid+halfSpread
+	if g.random.Intn(2) == 0 {
+		side, price = orders.SideSell, mid-halfSpread
+	}
+	client := orders.Client{
+		ClientName:   Client(g.random.Intn(g.params.Clients)),
+		ExchangeName: g.params.Exchange,
+		Label:        Label(g.random.Intn(g.params.Labels)),
+		Pair:         Pair(pair),
+	}
+	baseQty := 0.1 + g.random.ExpFloat64()
+	return schemas.ClientHistoryCreate{
+		Client: client,
+		OrderHistory: orders.History{
+			Client:              client,
+			Side:                side,
+			Type:                "market",
+			BaseQty:             baseQty,
+			Price:               price,
+			AlgorithmNamePlaced: "loadgen",
+			LowestSellPrc:       mid + halfSpread,
+			HighestBuyPrc:       mid - halfSpread,
+			CommissionQuoteQty:  baseQty * price * 0.001,
+			TimePlaced:          g.now().UTC(),
+		},
+	}
+}
+
+// Intn returns random number in [0, n) from the generator source
+func (g *Generator) Intn(n int) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.random.Intn(n)
+}
+
+// Float64 returns random number in [0, 1) from the generator source
+func (g *Generator) Float64() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.random.Float64()
+}
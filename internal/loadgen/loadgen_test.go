@@ -0,0 +1,178 @@
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/plinkplenk/test-vortex/internal/api/schemas"
+	"github.com/plinkplenk/test-vortex/internal/api/validators"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var market = MarketParams{
+	Exchange:      "loadgen",
+	Clients:       3,
+	Labels:        2,
+	Pairs:         2,
+	Depth:         5,
+	SpreadBps:     10,
+	VolatilityBps: 5,
+	StartMid:      100,
+	Seed:          1,
+}
+
+func newGenerator(t *testing.T, params MarketParams) *Generator {
+	t.Helper()
+	g, err := NewGenerator(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGenerator(t *testing.T) {
+	g := newGenerator(t, market)
+	for i := 0; i < 100; i++ {
+		book := g.Book()
+		assert.NoError(t, validators.ValidateOrderBook(book))
+		assert.Len(t, book.Depth, market.Depth)
+		mid := g.mids[strings.Index("P0_USDP1_USD", book.Pair)/len(book.Pair)]
+		assert.InDelta(t, mid*(1+market.SpreadBps/2e4), book.Depth[0].Price, 1e-9, "best level is half spread from mid")
+		for j := 1; j < len(book.Depth); j++ {
+			assert.Greater(t, book.Depth[j].Price, book.Depth[j-1].Price)
+		}
+
+		order := g.Order()
+		assert.NoError(t, validators.ValidateClientHistory(order))
+		assert.Greater(t, order.OrderHistory.Price, 0.0)
+		assert.Less(t, order.OrderHistory.HighestBuyPrc, order.OrderHistory.LowestSellPrc)
+	}
+	assert.NotEqual(t, market.StartMid, g.mids[0], "mid walks")
+
+	same := newGenerator(t, market)
+	other := newGenerator(t, market)
+	assert.Equal(t, same.Book(), other.Book(), "generator is deterministic by seed")
+
+	_, err := NewGenerator(MarketParams{Clients: 1, Labels: 1, Pairs: 0, Depth: 1, StartMid: 1})
+	assert.ErrorIs(t, err, ErrInvalidMarket)
+}
+
+func TestSummarize(t *testing.T) {
+	var samples []sample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, sample{latency: time.Duration(i) * time.Millisecond, status: http.StatusOK})
+	}
+	samples = append(
+		samples,
+		sample{latency: time.Second, status: http.StatusNotFound},
+		sample{latency: time.Second, status: http.StatusInternalServerError},
+		sample{failed: true},
+	)
+	report := summarize("get_order_book", samples, true)
+	assert.Equal(t, 103, report.Requests)
+	assert.Equal(t, 2, report.Errors, "not found of reads is not an error")
+	assert.Equal(t, map[int]int{http.StatusOK: 100, http.StatusNotFound: 1, http.StatusInternalServerError: 1}, report.Statuses)
+	assert.Equal(t, 51*time.Millisecond, report.P50)
+	assert.Equal(t, 92*time.Millisecond, report.P90)
+	assert.Equal(t, time.Second, report.P99)
+	assert.Equal(t, time.Second, report.Max)
+	assert.InDelta(t, 2.0/103, report.ErrorRate(), 1e-9)
+
+	assert.Equal(t, 3, summarize("save_order", samples, false).Errors)
+	assert.Equal(t, OperationReport{Operation: "save_order", Statuses: map[int]int{}}, summarize("save_order", nil, false))
+}
+
+func TestRunner_Run(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests = map[string]int{}
+	)
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "key", r.Header.Get("X-API-Key"))
+				route := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/v1")
+				if r.Method == http.MethodPost {
+					var book schemas.OrderBookCreate
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&book))
+				}
+				mu.Lock()
+				requests[route]++
+				mu.Unlock()
+				if strings.HasPrefix(route, "GET /orders/history") {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.WriteHeader(http.StatusCreated)
+			},
+		),
+	)
+	defer server.Close()
+
+	runner, err := NewRunner(
+		newGenerator(t, market),
+		RunParams{
+			URL:       server.URL + "/v1/",
+			RPS:       500,
+			Duration:  200 * time.Millisecond,
+			ReadRatio: 0.5,
+			Workers:   4,
+			Header:    http.Header{"X-Api-Key": {"key"}},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, runner.Seed(context.Background()))
+	assert.Equal(t, market.Pairs, requests["POST /orders"], "book of every pair is seeded")
+
+	report := runner.Run(context.Background())
+	assert.Greater(t, report.Total.Requests, 10)
+	assert.Zero(t, report.Total.Errors)
+	assert.Len(t, report.Operations, len(Operations))
+	var sum int
+	for _, operation := range report.Operations {
+		assert.Greater(t, operation.Requests, 0, "%s is sent", operation.Operation)
+		sum += operation.Requests
+	}
+	assert.Equal(t, report.Total.Requests, sum)
+	assert.Greater(t, report.RPS(), 0.0)
+	assert.Equal(t, 500.0, report.Target)
+
+	_, err = NewRunner(newGenerator(t, market), RunParams{RPS: 1, ReadRatio: 2})
+	assert.ErrorIs(t, err, ErrInvalidReadRatio)
+	_, err = NewRunner(newGenerator(t, market), RunParams{})
+	assert.ErrorIs(t, err, ErrInvalidRPS)
+}
+
+func TestRunner_Run_Schedule(t *testing.T) {
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(20 * time.Millisecond)
+				w.WriteHeader(http.StatusCreated)
+			},
+		),
+	)
+	defer server.Close()
+
+	runner, err := NewRunner(
+		newGenerator(t, market),
+		RunParams{URL: server.URL, RPS: 500, Duration: 200 * time.Millisecond, ReadRatio: 0.5, Workers: 1},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := runner.Run(context.Background())
+	// every request due in the duration is sent or dropped, slow responses don't delay the schedule
+	scheduled := report.Total.Requests + report.Dropped
+	assert.GreaterOrEqual(t, scheduled, 95)
+	assert.LessOrEqual(t, scheduled, 101)
+	assert.Greater(t, report.Dropped, 0, "requests due while the worker is busy are dropped")
+	assert.Less(t, report.RPS(), report.Target)
+}
@@ -0,0 +1,232 @@
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRPS       = errors.New("rps must be positive")
+	ErrInvalidReadRatio = errors.New("read ratio must be between 0 and 1")
+)
+
+// Operation is a kind of request sent by the load test
+type Operation struct {
+	Name string
+	Read bool
+	// request builds request of the operation to the api at base url
+	request func(ctx context.Context, base string, g *Generator) (*http.Request, error)
+}
+
+// Operations are requests of the load test, reads and writes are split evenly between their operations
+var Operations = []Operation{
+	{
+		Name: "get_order_book",
+		Read: true,
+		request: func(ctx context.Context, base string, g *Generator) (*http.Request, error) {
+			path := fmt.Sprintf("/orders/%s/%s", url.PathEscape(g.params.Exchange), Pair(g.Intn(g.params.Pairs)))
+			return http.NewRequestWithContext(ctx, http.MethodGet, base+path, nil)
+		},
+	},
+	{
+		Name: "get_order_history",
+		Read: true,
+		request: func(ctx context.Context, base string, g *Generator) (*http.Request, error) {
+			path := fmt.Sprintf("/orders/history/%s/%s", Client(g.Intn(g.params.Clients)), url.PathEscape(g.params.Exchange))
+			query := url.Values{"label": {Label(g.Intn(g.params.Labels))}, "pair": {Pair(g.Intn(g.params.Pairs))}}
+			return http.NewRequestWithContext(ctx, http.MethodGet, base+path+"?"+query.Encode(), nil)
+		},
+	},
+	{
+		Name: "save_order_book",
+		request: func(ctx context.Context, base string, g *Generator) (*http.Request, error) {
+			return jsonRequest(ctx, base+"/orders", g.Book())
+		},
+	},
+	{
+		Name: "save_order",
+		request: func(ctx context.Context, base string, g *Generator) (*http.Request, error) {
+			return jsonRequest(ctx, base+"/orders/history", g.Order())
+		},
+	},
+}
+
+func jsonRequest(ctx context.Context, u string, body any) (*http.Request, error) {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	return r, nil
+}
+
+type RunParams struct {
+	// URL of the api with version prefix like "http://localhost:8080/v1"
+	URL string
+	// RPS is the target rate, requests are sent on schedule whether responses are slow or not
+	RPS      float64
+	Duration time.Duration
+	// ReadRatio is the share of read requests
+	ReadRatio float64
+	// Workers bound requests in flight, requests due when every worker is busy are dropped
+	Workers int
+	// Header is added to every request, it carries credentials
+	Header http.Header
+	Client *http.Client
+}
+
+func (p RunParams) validate() error {
+	if !(p.RPS > 0) {
+		return ErrInvalidRPS
+	}
+	if !(p.ReadRatio >= 0 && p.ReadRatio <= 1) {
+		return ErrInvalidReadRatio
+	}
+	return nil
+}
+
+type Runner struct {
+	generator *Generator
+	params    RunParams
+	recorder  *recorder
+	reads     []Operation
+	writes    []Operation
+}
+
+func NewRunner(generator *Generator, params RunParams) (*Runner, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	params.URL = strings.TrimSuffix(params.URL, "/")
+	if params.Workers <= 0 {
+		params.Workers = 1
+	}
+	if params.Client == nil {
+		params.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	runner := &Runner{generator: generator, params: params, recorder: newRecorder()}
+	for _, operation := range Operations {
+		if operation.Read {
+			runner.reads = append(runner.reads, operation)
+		} else {
+			runner.writes = append(runner.writes, operation)
+		}
+	}
+	return runner, nil
+}
+
+// Seed saves a book of every pair, so reads of books find them. Seeding requests are not reported
+func (r *Runner) Seed(ctx context.Context) error {
+	for pair := 0; pair < r.generator.params.Pairs; pair++ {
+		req, err := jsonRequest(ctx, r.params.URL+"/orders", r.generator.BookOf(pair))
+		if err != nil {
+			return err
+		}
+		status, err := r.send(req)
+		if err != nil {
+			return err
+		}
+		if status >= http.StatusBadRequest {
+			return fmt.Errorf("seeding order book of %s: status %d", Pair(pair), status)
+		}
+	}
+	return nil
+}
+
+// Run sends requests at the target rate until duration passes or ctx is done and waits for requests in flight.
+// Request n is due at n/RPS after the start, requests that became due while the runner waited are sent at once,
+// so the rate doesn't fall behind the schedule
+func (r *Runner) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, r.params.Duration)
+	defer cancel()
+	// queue is not buffered, so requests are sent only by idle workers
+	queue := make(chan Operation)
+	done := make(chan struct{})
+	for i := 0; i < r.params.Workers; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for operation := range queue {
+				r.do(operation)
+			}
+		}()
+	}
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	report := Report{Target: r.params.RPS}
+	start := time.Now()
+loop:
+	for n := 0; ; n++ {
+		due := start.Add(time.Duration(float64(n) * float64(time.Second) / r.params.RPS))
+		if wait := time.Until(due); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				break loop
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			break loop
+		}
+		select {
+		case queue <- r.pick():
+		default:
+			report.Dropped++
+		}
+	}
+	report.Elapsed = time.Since(start)
+	close(queue)
+	for i := 0; i < r.params.Workers; i++ {
+		<-done
+	}
+	report.Operations, report.Total = r.recorder.report(Operations)
+	return report
+}
+
+// pick picks operation by read ratio
+func (r *Runner) pick() Operation {
+	operations := r.writes
+	if r.generator.Float64() < r.params.ReadRatio {
+		operations = r.reads
+	}
+	return operations[r.generator.Intn(len(operations))]
+}
+
+// do sends request of the operation, requests in flight are not cancelled when the run ends
+func (r *Runner) do(operation Operation) {
+	req, err := operation.request(context.Background(), r.params.URL, r.generator)
+	if err != nil {
+		r.recorder.record(operation.Name, sample{failed: true})
+		return
+	}
+	start := time.Now()
+	status, err := r.send(req)
+	r.recorder.record(operation.Name, sample{latency: time.Since(start), status: status, failed: err != nil})
+}
+
+func (r *Runner) send(req *http.Request) (int, error) {
+	for key, values := range r.params.Header {
+		req.Header[key] = values
+	}
+	resp, err := r.params.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// body is read so the connection is reused
+	_, err = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, errors.Join(err, resp.Body.Close())
+}
@@ -0,0 +1,121 @@
+package loadgen
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// OperationReport is latency and outcome of requests of one operation
+type OperationReport struct {
+	Operation string
+	Requests  int
+	// Errors are failed requests and responses with error status, except not found of reads
+	Errors int
+	// Statuses counts responses by status code, failed requests have no status
+	Statuses map[int]int
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+// ErrorRate is the share of requests that failed
+func (r OperationReport) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+type Report struct {
+	// Target is the rate requests were scheduled at
+	Target float64
+	// Elapsed is the time requests were sent for
+	Elapsed time.Duration
+	// Dropped are requests that were not sent because every worker was busy
+	Dropped    int
+	Operations []OperationReport
+	Total      OperationReport
+}
+
+// RPS is the achieved rate of sent requests
+func (r Report) RPS() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Total.Requests) / r.Elapsed.Seconds()
+}
+
+type sample struct {
+	latency time.Duration
+	status  int
+	failed  bool
+}
+
+// recorder collects samples of operations
+type recorder struct {
+	mu      sync.Mutex
+	samples map[string][]sample
+}
+
+func newRecorder() *recorder {
+	return &recorder{samples: map[string][]sample{}}
+}
+
+func (r *recorder) record(operation string, s sample) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[operation] = append(r.samples[operation], s)
+}
+
+// report summarizes samples of operations in the order of operations
+func (r *recorder) report(operations []Operation) ([]OperationReport, OperationReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var all []sample
+	reports := make([]OperationReport, 0, len(operations))
+	for _, operation := range operations {
+		samples := r.samples[operation.Name]
+		all = append(all, samples...)
+		reports = append(reports, summarize(operation.Name, samples, operation.Read))
+	}
+	total := summarize("total", all, false)
+	// not found of reads is not an error of the total either
+	total.Errors = 0
+	for _, report := range reports {
+		total.Errors += report.Errors
+	}
+	return reports, total
+}
+
+func summarize(operation string, samples []sample, read bool) OperationReport {
+	report := OperationReport{Operation: operation, Requests: len(samples), Statuses: map[int]int{}}
+	latencies := make([]time.Duration, len(samples))
+	for i, s := range samples {
+		latencies[i] = s.latency
+		if !s.failed {
+			report.Statuses[s.status]++
+		}
+		if s.failed || (s.status >= http.StatusBadRequest && !(read && s.status == http.StatusNotFound)) {
+			report.Errors++
+		}
+	}
+	if len(latencies) == 0 {
+		return report
+	}
+	slices.Sort(latencies)
+	report.P50 = percentile(latencies, 0.5)
+	report.P90 = percentile(latencies, 0.9)
+	report.P99 = percentile(latencies, 0.99)
+	report.Max = latencies[len(latencies)-1]
+	return report
+}
+
+// percentile returns nearest rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
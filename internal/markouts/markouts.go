@@ -0,0 +1,26 @@
+package markouts
+
+import "time"
+
+// Markout is aggregated markout of orders of one algorithm, label and pair at one horizon
+type Markout struct {
+	Algorithm      string  `json:"algorithm"`
+	Label          string  `json:"label"`
+	Pair           string  `json:"pair"`
+	HorizonSeconds uint32  `json:"horizonSeconds"`
+	Orders         uint64  `json:"orders"`
+	BaseVolume     float64 `json:"baseVolume"`
+	// AvgMarkoutBps is quote volume weighted markout, negative value means adverse selection
+	AvgMarkoutBps float64 `json:"avgMarkoutBps"`
+	// MarkoutQuote is the sum of fills pnl versus mid at the horizon
+	MarkoutQuote float64 `json:"markoutQuote"`
+}
+
+type Query struct {
+	From time.Time
+	To   time.Time
+	// Algorithm, Label and Pair are optional filters
+	Algorithm string
+	Label     string
+	Pair      string
+}
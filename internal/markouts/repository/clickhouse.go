@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/plinkplenk/test-vortex/internal/markouts"
+	"time"
+)
+
+type clickHouseRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseRepository(db clickhouse.Conn) Repository {
+	return clickHouseRepository{
+		db: db,
+	}
+}
+
+// ComputeMarkouts joins every order and horizon with the latest order book snapshot
+// created at or before time placed + horizon. Horizons without such snapshot created at or after
+// time placed are skipped, so orders are not marked against books older than them
+func (r clickHouseRepository) ComputeMarkouts(
+	ctx context.Context, from, to time.Time, horizons []time.Duration,
+) error {
+	seconds := make([]uint32, 0, len(horizons))
+	for _, horizon := range horizons {
+		seconds = append(seconds, uint32(horizon.Seconds()))
+	}
+	query := `
+		INSERT INTO order_markouts (
+			client_name,
+			exchange_name,
+			label,
+			pair,
+			algorithm_name_placed,
+			side,
+			base_qty,
+			price,
+			time_placed,
+			horizon_seconds,
+			mid,
+			markout_bps,
+			markout_quote
+		)
+		SELECT o.client_name,
+		       o.exchange_name,
+		       o.label,
+		       o.pair,
+		       o.algorithm_name_placed,
+		       o.side,
+		       o.base_qty,
+		       o.price,
+		       o.time_placed,
+		       o.horizon_seconds,
+		       b.mid,
+		       o.direction * (b.mid - o.price) / o.price * 10000,
+		       o.direction * (b.mid - o.price) * o.base_qty
+		FROM (
+			SELECT *,
+			       if(lower(side) = 'buy', 1, -1) AS direction,
+			       arrayJoin(?) AS horizon_seconds,
+			       toDateTime64(time_placed, 3) + toIntervalSecond(horizon_seconds) AS time_marked
+			FROM order_history
+			WHERE time_placed >= ? AND time_placed < ? AND lower(side) IN ('buy', 'sell') AND price > 0
+		) AS o
+		ASOF INNER JOIN (
+			SELECT exchange,
+			       pair,
+			       time_created,
+			       multiIf(
+			           best_ask > 0 AND best_bid > 0, (best_ask + best_bid) / 2,
+			           best_ask > 0, best_ask,
+			           best_bid
+			       ) AS mid
+			FROM (
+				SELECT exchange,
+				       pair,
+				       time_created,
+				       minIf(tupleElement(asks, 1), tupleElement(asks, 1) > 0) AS best_ask,
+				       maxIf(tupleElement(bids, 1), tupleElement(bids, 1) > 0) AS best_bid
+				FROM order_book
+				GROUP BY exchange, pair, time_created
+			)
+			WHERE mid > 0
+		) AS b
+		ON o.exchange_name = b.exchange AND o.pair = b.pair AND o.time_marked >= b.time_created
+		WHERE b.time_created >= o.time_placed`
+	return r.db.Exec(ctx, query, seconds, from, to)
+}
+
+func (r clickHouseRepository) GetMarkouts(ctx context.Context, query markouts.Query) ([]markouts.Markout, error) {
+	q := `
+		SELECT algorithm_name_placed,
+		       label,
+		       pair,
+		       horizon_seconds,
+		       count(),
+		       sum(base_qty),
+		       ifNotFinite(sum(markout_bps * base_qty * price) / sum(base_qty * price), 0),
+		       sum(markout_quote)
+		FROM order_markouts FINAL
+		WHERE time_placed >= ? AND time_placed < ?
+		  AND (? = '' OR algorithm_name_placed = ?)
+		  AND (? = '' OR label = ?)
+		  AND (? = '' OR pair = ?)
+		GROUP BY algorithm_name_placed, label, pair, horizon_seconds
+		ORDER BY algorithm_name_placed, label, pair, horizon_seconds`
+	rows, err := r.db.Query(
+		ctx,
+		q,
+		query.From,
+		query.To,
+		query.Algorithm, query.Algorithm,
+		query.Label, query.Label,
+		query.Pair, query.Pair,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var result []markouts.Markout
+	for rows.Next() {
+		var m markouts.Markout
+		if err := rows.Scan(
+			&m.Algorithm,
+			&m.Label,
+			&m.Pair,
+			&m.HorizonSeconds,
+			&m.Orders,
+			&m.BaseVolume,
+			&m.AvgMarkoutBps,
+			&m.MarkoutQuote,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/markouts"
+	"time"
+)
+
+type Repository interface {
+	// ComputeMarkouts stores markouts of orders placed in [from, to) for every horizon.
+	// Recomputing the same window replaces previously stored markouts
+	ComputeMarkouts(ctx context.Context, from, to time.Time, horizons []time.Duration) error
+	GetMarkouts(ctx context.Context, query markouts.Query) ([]markouts.Markout, error)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Job periodically computes markouts of orders whose longest horizon has already passed
+type Job struct {
+	service  MarkoutsService
+	interval time.Duration
+	// delay is the longest markout horizon
+	delay time.Duration
+	// enabled is checked on every tick, markouts are not computed while it returns false
+	enabled func() bool
+	logger  *slog.Logger
+}
+
+// NewJob creates job computing markouts every interval, nil enabled means always enabled
+func NewJob(
+	service MarkoutsService, interval time.Duration, horizons []time.Duration, enabled func() bool, logger *slog.Logger,
+) Job {
+	var delay time.Duration
+	for _, horizon := range horizons {
+		delay = max(delay, horizon)
+	}
+	if enabled == nil {
+		enabled = func() bool { return true }
+	}
+	return Job{service: service, interval: interval, delay: delay, enabled: enabled, logger: logger}
+}
+
+// Run blocks until ctx is done. Window that failed to compute or was skipped while disabled
+// is computed on the next enabled tick
+func (j Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	from := time.Now().UTC().Add(-j.delay - j.interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !j.enabled() {
+				continue
+			}
+			to := time.Now().UTC().Add(-j.delay)
+			if err := j.service.Compute(ctx, from, to); err != nil {
+				j.logger.Error("Error while computing markouts", "from", from, "to", to, "error", err)
+				continue
+			}
+			j.logger.Debug("Markouts computed", "from", from, "to", to)
+			from = to
+		}
+	}
+}
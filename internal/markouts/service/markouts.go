@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/markouts"
+	markoutsRepository "github.com/plinkplenk/test-vortex/internal/markouts/repository"
+	"time"
+)
+
+//go:generate mockgen -source=markouts.go -destination=mocks/mock.go
+type MarkoutsService interface {
+	// Compute computes and stores markouts of orders placed in [from, to) for every configured horizon
+	Compute(ctx context.Context, from, to time.Time) error
+	GetMarkouts(ctx context.Context, query markouts.Query) ([]markouts.Markout, error)
+}
+
+type markoutService struct {
+	repository markoutsRepository.Repository
+	timeout    time.Duration
+	horizons   []time.Duration
+}
+
+func New(
+	repository markoutsRepository.Repository, timeout time.Duration, horizons []time.Duration,
+) MarkoutsService {
+	return markoutService{
+		repository: repository,
+		timeout:    timeout,
+		horizons:   horizons,
+	}
+}
+
+func (s markoutService) Compute(ctx context.Context, from, to time.Time) error {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.repository.ComputeMarkouts(c, from, to, s.horizons)
+}
+
+func (s markoutService) GetMarkouts(ctx context.Context, query markouts.Query) ([]markouts.Markout, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.repository.GetMarkouts(c, query)
+}
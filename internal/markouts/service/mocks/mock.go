@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: markouts.go
+//
+// Generated by this command:
+//
+//	mockgen -source=markouts.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	markouts "github.com/plinkplenk/test-vortex/internal/markouts"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMarkoutsService is a mock of MarkoutsService interface.
+type MockMarkoutsService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMarkoutsServiceMockRecorder
+}
+
+// MockMarkoutsServiceMockRecorder is the mock recorder for MockMarkoutsService.
+type MockMarkoutsServiceMockRecorder struct {
+	mock *MockMarkoutsService
+}
+
+// NewMockMarkoutsService creates a new mock instance.
+func NewMockMarkoutsService(ctrl *gomock.Controller) *MockMarkoutsService {
+	mock := &MockMarkoutsService{ctrl: ctrl}
+	mock.recorder = &MockMarkoutsServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMarkoutsService) EXPECT() *MockMarkoutsServiceMockRecorder {
+	return m.recorder
+}
+
+// Compute mocks base method.
+func (m *MockMarkoutsService) Compute(ctx context.Context, from, to time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compute", ctx, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compute indicates an expected call of Compute.
+func (mr *MockMarkoutsServiceMockRecorder) Compute(ctx, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compute", reflect.TypeOf((*MockMarkoutsService)(nil).Compute), ctx, from, to)
+}
+
+// GetMarkouts mocks base method.
+func (m *MockMarkoutsService) GetMarkouts(ctx context.Context, query markouts.Query) ([]markouts.Markout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMarkouts", ctx, query)
+	ret0, _ := ret[0].([]markouts.Markout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMarkouts indicates an expected call of GetMarkouts.
+func (mr *MockMarkoutsServiceMockRecorder) GetMarkouts(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarkouts", reflect.TypeOf((*MockMarkoutsService)(nil).GetMarkouts), ctx, query)
+}
@@ -0,0 +1,142 @@
+package metrics
+
+import (
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "order_service"
+
+// Metrics holds collectors of the service, every instance has its own registry
+type Metrics struct {
+	registry          *prometheus.Registry
+	requests          *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	operationDuration *prometheus.HistogramVec
+	operationErrors   *prometheus.CounterVec
+	ingestedOrders    prometheus.Counter
+	ingestedLevels    prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "http_requests_total",
+				Help:      "Number of HTTP requests by route pattern, method and status code.",
+			},
+			[]string{"route", "method", "status"},
+		),
+		requestDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "http_request_duration_seconds",
+				Help:      "HTTP request latency by route pattern, method and status code.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"route", "method", "status"},
+		),
+		operationDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "service_operation_duration_seconds",
+				Help:      "Service operation latency.",
+				Buckets:   prometheus.DefBuckets,
+			},
+			[]string{"service", "operation"},
+		),
+		operationErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "service_operation_errors_total",
+				Help:      "Number of failed service operations.",
+			},
+			[]string{"service", "operation"},
+		),
+		ingestedOrders: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "ingested_orders_total",
+				Help:      "Number of saved order history rows.",
+			},
+		),
+		ingestedLevels: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "ingested_order_book_levels_total",
+				Help:      "Number of saved order book levels.",
+			},
+		),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.operationDuration,
+		m.operationErrors,
+		m.ingestedOrders,
+		m.ingestedLevels,
+	)
+	return m
+}
+
+// Handler serves metrics in Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveRequest(route, method string, status int, duration time.Duration) {
+	labels := prometheus.Labels{"route": route, "method": method, "status": strconv.Itoa(status)}
+	m.requests.With(labels).Inc()
+	m.requestDuration.With(labels).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveOperation(service, operation string, duration time.Duration, err error) {
+	m.operationDuration.WithLabelValues(service, operation).Observe(duration.Seconds())
+	if err != nil {
+		m.operationErrors.WithLabelValues(service, operation).Inc()
+	}
+}
+
+func (m *Metrics) AddIngestedOrders(n int) {
+	m.ingestedOrders.Add(float64(n))
+}
+
+func (m *Metrics) AddIngestedBookLevels(n int) {
+	m.ingestedLevels.Add(float64(n))
+}
+
+// StatsProvider is implemented by clickhouse.Conn
+type StatsProvider interface {
+	Stats() driver.Stats
+}
+
+// RegisterClickHouse exposes connection pool stats, they are read on every scrape
+func (m *Metrics) RegisterClickHouse(conn StatsProvider) {
+	gauge := func(name, help string, value func(driver.Stats) int) prometheus.GaugeFunc {
+		return prometheus.NewGaugeFunc(
+			prometheus.GaugeOpts{Namespace: namespace, Subsystem: "clickhouse", Name: name, Help: help},
+			func() float64 { return float64(value(conn.Stats())) },
+		)
+	}
+	m.registry.MustRegister(
+		gauge("open_connections", "Number of open connections.", func(s driver.Stats) int { return s.Open }),
+		gauge("idle_connections", "Number of idle connections.", func(s driver.Stats) int { return s.Idle }),
+		gauge(
+			"max_open_connections", "Maximum number of open connections.",
+			func(s driver.Stats) int { return s.MaxOpenConns },
+		),
+		gauge(
+			"max_idle_connections", "Maximum number of idle connections.",
+			func(s driver.Stats) int { return s.MaxIdleConns },
+		),
+	)
+}
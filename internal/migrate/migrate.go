@@ -0,0 +1,157 @@
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidMigration = errors.New("invalid migration")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+	ErrNothingToRevert  = errors.New("no applied migrations to revert")
+)
+
+// Migration is goose SQL migration, Up and Down are statements executed one by one
+type Migration struct {
+	Version int64
+	Name    string
+	Up      []string
+	Down    []string
+}
+
+// Status is state of a migration in the database
+type Status struct {
+	Version int64
+	Name    string
+	Applied bool
+	// AppliedAt is zero if migration is not applied
+	AppliedAt time.Time
+}
+
+const annotationPrefix = "-- +goose "
+
+type section int
+
+const (
+	sectionNone section = iota
+	sectionUp
+	sectionDown
+)
+
+// Parse parses goose SQL migration of the file name <version>_<name>.sql.
+// Statements end with ";" at the end of a line unless they are wrapped in StatementBegin and StatementEnd
+func Parse(name string, content []byte) (Migration, error) {
+	base := strings.TrimSuffix(path.Base(name), ".sql")
+	versionPart, namePart, _ := strings.Cut(base, "_")
+	version, err := strconv.ParseInt(versionPart, 10, 64)
+	if err != nil || version <= 0 {
+		return Migration{}, fmt.Errorf("%w: %s: file name must start with version", ErrInvalidMigration, name)
+	}
+	migration := Migration{Version: version, Name: namePart}
+
+	current := sectionNone
+	inBlock := false
+	var statement strings.Builder
+	flush := func() {
+		s := strings.TrimSuffix(strings.TrimSpace(statement.String()), ";")
+		statement.Reset()
+		if s == "" {
+			return
+		}
+		switch current {
+		case sectionUp:
+			migration.Up = append(migration.Up, s)
+		case sectionDown:
+			migration.Down = append(migration.Down, s)
+		}
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if annotation, ok := strings.CutPrefix(trimmed, annotationPrefix); ok {
+			switch strings.TrimSpace(annotation) {
+			case "Up":
+				flush()
+				current = sectionUp
+			case "Down":
+				flush()
+				current = sectionDown
+			case "StatementBegin":
+				flush()
+				inBlock = true
+			case "StatementEnd":
+				if !inBlock {
+					return Migration{}, fmt.Errorf("%w: %s: StatementEnd without StatementBegin", ErrInvalidMigration, name)
+				}
+				flush()
+				inBlock = false
+			}
+			continue
+		}
+		if current == sectionNone {
+			continue
+		}
+		// comments and blank lines between statements are not sent to the database
+		if !inBlock && statement.Len() == 0 && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+		statement.WriteString(line)
+		statement.WriteByte('\n')
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Migration{}, err
+	}
+	if inBlock {
+		return Migration{}, fmt.Errorf("%w: %s: StatementBegin without StatementEnd", ErrInvalidMigration, name)
+	}
+	flush()
+	if len(migration.Up) == 0 {
+		return Migration{}, fmt.Errorf("%w: %s: no Up statements", ErrInvalidMigration, name)
+	}
+	return migration, nil
+}
+
+// Load parses all .sql files of fsys sorted by version
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		migration, err := Parse(name, content)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+	slices.SortFunc(
+		migrations, func(a, b Migration) int {
+			return cmp.Compare(a.Version, b.Version)
+		},
+	)
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("%w: duplicate version %d", ErrInvalidMigration, migrations[i].Version)
+		}
+	}
+	return migrations, nil
+}
@@ -0,0 +1,104 @@
+package migrate
+
+import (
+	"github.com/plinkplenk/test-vortex/migrations"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testTable := []struct {
+		name        string
+		file        string
+		content     string
+		expected    Migration
+		expectedErr error
+	}{
+		{
+			name: "STATEMENT BLOCKS",
+			file: "20240101000000_create_table.sql",
+			content: `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE t
+(
+    id UUID
+) ENGINE = MergeTree ORDER BY id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE t;
+-- +goose StatementEnd
+`,
+			expected: Migration{
+				Version: 20240101000000,
+				Name:    "create_table",
+				Up:      []string{"CREATE TABLE t\n(\n    id UUID\n) ENGINE = MergeTree ORDER BY id"},
+				Down:    []string{"DROP TABLE t"},
+			},
+		},
+		{
+			name: "SEMICOLON SEPARATED",
+			file: "2_alter.sql",
+			content: `-- +goose Up
+-- adds columns
+ALTER TABLE t ADD COLUMN a String;
+ALTER TABLE t
+    ADD COLUMN b String;
+-- +goose Down
+ALTER TABLE t DROP COLUMN b;
+ALTER TABLE t DROP COLUMN a;
+`,
+			expected: Migration{
+				Version: 2,
+				Name:    "alter",
+				Up:      []string{"ALTER TABLE t ADD COLUMN a String", "ALTER TABLE t\n    ADD COLUMN b String"},
+				Down:    []string{"ALTER TABLE t DROP COLUMN b", "ALTER TABLE t DROP COLUMN a"},
+			},
+		},
+		{
+			name:        "NO VERSION",
+			file:        "create_table.sql",
+			content:     "-- +goose Up\nSELECT 1;\n",
+			expectedErr: ErrInvalidMigration,
+		},
+		{
+			name:        "UNCLOSED BLOCK",
+			file:        "3_unclosed.sql",
+			content:     "-- +goose Up\n-- +goose StatementBegin\nSELECT 1;\n",
+			expectedErr: ErrInvalidMigration,
+		},
+		{
+			name:        "NO UP",
+			file:        "4_empty.sql",
+			content:     "-- +goose Down\nSELECT 1;\n",
+			expectedErr: ErrInvalidMigration,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				migration, err := Parse(test.file, []byte(test.content))
+				if test.expectedErr != nil {
+					assert.ErrorIs(t, err, test.expectedErr)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, migration)
+			},
+		)
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	loaded, err := Load(migrations.FS)
+	assert.NoError(t, err)
+	latest, err := migrations.LatestVersion()
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, loaded) {
+		assert.Equal(t, latest, loaded[len(loaded)-1].Version)
+	}
+	for _, migration := range loaded {
+		assert.NotEmpty(t, migration.Down, "migration %d can't be reverted", migration.Version)
+	}
+}
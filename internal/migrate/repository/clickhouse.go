@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"time"
+)
+
+// versionTable has the same name and columns as the table goose creates for ClickHouse,
+// so databases migrated by goose and by the service are interchangeable
+const versionTable = "goose_db_version"
+
+type clickHouseRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseRepository(db clickhouse.Conn) Repository {
+	return clickHouseRepository{
+		db: db,
+	}
+}
+
+func (r clickHouseRepository) EnsureVersionTable(ctx context.Context) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS ` + versionTable + `
+		(
+			version_id Int64,
+			is_applied UInt8,
+			date       Date     DEFAULT now(),
+			tstamp     DateTime DEFAULT now()
+		)
+			ENGINE = MergeTree()
+			ORDER BY (date)`
+	if err := r.db.Exec(ctx, query); err != nil {
+		return err
+	}
+	var rows uint64
+	if err := r.db.QueryRow(ctx, `SELECT count() FROM `+versionTable).Scan(&rows); err != nil {
+		return err
+	}
+	if rows > 0 {
+		return nil
+	}
+	// goose marks version 0 as applied when it creates the table
+	return r.SetApplied(ctx, 0)
+}
+
+func (r clickHouseRepository) GetAppliedVersions(ctx context.Context) (map[int64]time.Time, error) {
+	// older goose versions recorded reverts as rows with is_applied = 0 instead of deleting
+	query := `
+		SELECT version_id, max(tstamp)
+		FROM ` + versionTable + `
+		GROUP BY version_id
+		HAVING argMax(is_applied, tstamp) = 1`
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	versions := make(map[int64]time.Time)
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		versions[version] = appliedAt
+	}
+	return versions, rows.Err()
+}
+
+func (r clickHouseRepository) SetApplied(ctx context.Context, version int64) error {
+	return r.db.Exec(ctx, `INSERT INTO `+versionTable+` (version_id, is_applied) VALUES (?, 1)`, version)
+}
+
+func (r clickHouseRepository) UnsetApplied(ctx context.Context, version int64) error {
+	return r.db.Exec(
+		ctx,
+		`ALTER TABLE `+versionTable+` DELETE WHERE version_id = ? SETTINGS mutations_sync = 2`,
+		version,
+	)
+}
+
+func (r clickHouseRepository) Exec(ctx context.Context, statement string) error {
+	return r.db.Exec(ctx, statement)
+}
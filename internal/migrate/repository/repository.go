@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// EnsureVersionTable creates goose version table if it doesn't exist
+	EnsureVersionTable(ctx context.Context) error
+	// GetAppliedVersions returns applied versions with the time they were applied
+	GetAppliedVersions(ctx context.Context) (map[int64]time.Time, error)
+	// SetApplied records version as applied
+	SetApplied(ctx context.Context, version int64) error
+	// UnsetApplied removes version record after the migration is reverted
+	UnsetApplied(ctx context.Context, version int64) error
+	// Exec executes migration statement
+	Exec(ctx context.Context, statement string) error
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/plinkplenk/test-vortex/internal/migrate"
+	migrateRepository "github.com/plinkplenk/test-vortex/internal/migrate/repository"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+type Migrator interface {
+	// Up applies all migrations that are not applied
+	Up(ctx context.Context) error
+	// Down reverts the newest applied migration
+	Down(ctx context.Context) error
+	// To applies or reverts migrations until version is the newest applied one, zero reverts everything
+	To(ctx context.Context, version int64) error
+	// Status returns state of every known migration
+	Status(ctx context.Context) ([]migrate.Status, error)
+}
+
+type migrator struct {
+	repository migrateRepository.Repository
+	migrations []migrate.Migration
+	logger     *slog.Logger
+}
+
+// New creates migrator of migrations sorted by version, see migrate.Load
+func New(
+	repository migrateRepository.Repository, migrations []migrate.Migration, logger *slog.Logger,
+) Migrator {
+	return migrator{
+		repository: repository,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+func (m migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].Version)
+}
+
+func (m migrator) Down(ctx context.Context) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		if _, ok := applied[m.migrations[i].Version]; ok {
+			return m.down(ctx, m.migrations[i])
+		}
+	}
+	return migrate.ErrNothingToRevert
+}
+
+func (m migrator) To(ctx context.Context, version int64) error {
+	known := version == 0 || slices.ContainsFunc(
+		m.migrations, func(migration migrate.Migration) bool {
+			return migration.Version == version
+		},
+	)
+	if !known {
+		return fmt.Errorf("%w: %d", migrate.ErrUnknownVersion, version)
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	// newer migrations are reverted first, so Down statements see the schema they were written for
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			if err := m.down(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.up(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m migrator) Status(ctx context.Context) ([]migrate.Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]migrate.Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		appliedAt, ok := applied[migration.Version]
+		statuses = append(
+			statuses, migrate.Status{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Applied:   ok,
+				AppliedAt: appliedAt,
+			},
+		)
+	}
+	return statuses, nil
+}
+
+// applied returns applied versions with the time they were applied, version table is created if needed
+func (m migrator) applied(ctx context.Context) (map[int64]time.Time, error) {
+	if err := m.repository.EnsureVersionTable(ctx); err != nil {
+		return nil, err
+	}
+	return m.repository.GetAppliedVersions(ctx)
+}
+
+func (m migrator) up(ctx context.Context, migration migrate.Migration) error {
+	for _, statement := range migration.Up {
+		if err := m.repository.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	if err := m.repository.SetApplied(ctx, migration.Version); err != nil {
+		return err
+	}
+	m.logger.Info("Migration applied", "version", migration.Version, "name", migration.Name)
+	return nil
+}
+
+func (m migrator) down(ctx context.Context, migration migrate.Migration) error {
+	for _, statement := range migration.Down {
+		if err := m.repository.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	if err := m.repository.UnsetApplied(ctx, migration.Version); err != nil {
+		return err
+	}
+	m.logger.Info("Migration reverted", "version", migration.Version, "name", migration.Name)
+	return nil
+}
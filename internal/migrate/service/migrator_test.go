@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/migrate"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// repositoryStub keeps version table in memory and records executed statements
+type repositoryStub struct {
+	applied  map[int64]time.Time
+	executed []string
+}
+
+func (r *repositoryStub) EnsureVersionTable(_ context.Context) error {
+	if r.applied == nil {
+		r.applied = map[int64]time.Time{0: {}}
+	}
+	return nil
+}
+
+func (r *repositoryStub) GetAppliedVersions(_ context.Context) (map[int64]time.Time, error) {
+	return r.applied, nil
+}
+
+func (r *repositoryStub) SetApplied(_ context.Context, version int64) error {
+	r.applied[version] = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return nil
+}
+
+func (r *repositoryStub) UnsetApplied(_ context.Context, version int64) error {
+	delete(r.applied, version)
+	return nil
+}
+
+func (r *repositoryStub) Exec(_ context.Context, statement string) error {
+	r.executed = append(r.executed, statement)
+	return nil
+}
+
+func TestMigrator(t *testing.T) {
+	migrations := []migrate.Migration{
+		{Version: 1, Name: "one", Up: []string{"up 1"}, Down: []string{"down 1"}},
+		{Version: 2, Name: "two", Up: []string{"up 2a", "up 2b"}, Down: []string{"down 2"}},
+		{Version: 3, Name: "three", Up: []string{"up 3"}, Down: []string{"down 3"}},
+	}
+	repository := &repositoryStub{}
+	m := New(repository, migrations, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	assert.NoError(t, m.To(ctx, 2))
+	assert.Equal(t, []string{"up 1", "up 2a", "up 2b"}, repository.executed)
+
+	statuses, err := m.Status(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{true, true, false}, []bool{statuses[0].Applied, statuses[1].Applied, statuses[2].Applied})
+
+	repository.executed = nil
+	assert.NoError(t, m.Up(ctx))
+	assert.NoError(t, m.Up(ctx), "up is idempotent")
+	assert.Equal(t, []string{"up 3"}, repository.executed)
+
+	repository.executed = nil
+	assert.NoError(t, m.Down(ctx))
+	assert.Equal(t, []string{"down 3"}, repository.executed)
+
+	repository.executed = nil
+	assert.NoError(t, m.To(ctx, 0))
+	assert.Equal(t, []string{"down 2", "down 1"}, repository.executed)
+	assert.ErrorIs(t, m.Down(ctx), migrate.ErrNothingToRevert)
+	assert.ErrorIs(t, m.To(ctx, 5), migrate.ErrUnknownVersion)
+}
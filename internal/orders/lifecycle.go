@@ -0,0 +1,140 @@
+package orders
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusNew             Status = "new"
+	StatusPartiallyFilled Status = "partially_filled"
+	StatusFilled          Status = "filled"
+	StatusCancelled       Status = "cancelled"
+	StatusRejected        Status = "rejected"
+	StatusExpired         Status = "expired"
+)
+
+// fillEpsilon is relative tolerance used to treat float residuals of filled quantity as zero
+const fillEpsilon = 1e-12
+
+var (
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrOrderExists       = errors.New("order with such id already exists")
+	ErrInvalidTransition = errors.New("invalid order status transition")
+	ErrOverfill          = errors.New("fill quantity exceeds order remaining quantity")
+)
+
+// transitions lists statuses that can be appended as events, fill statuses are derived from fills
+var transitions = map[Status][]Status{
+	StatusNew:             {StatusCancelled, StatusRejected, StatusExpired},
+	StatusPartiallyFilled: {StatusCancelled, StatusExpired},
+}
+
+type Order struct {
+	ID uuid.UUID `json:"orderId"`
+	Client
+	Side        string    `json:"side"`
+	Type        string    `json:"type"`
+	BaseQty     float64   `json:"baseQty"`
+	Price       float64   `json:"price"`
+	TimeCreated time.Time `json:"timeCreated"`
+}
+
+type Event struct {
+	OrderID uuid.UUID `json:"orderId"`
+	Status  Status    `json:"status"`
+	Reason  string    `json:"reason"`
+	Time    time.Time `json:"time"`
+}
+
+type Fill struct {
+	ID                 uuid.UUID `json:"fillId"`
+	OrderID            uuid.UUID `json:"orderId"`
+	BaseQty            float64   `json:"baseQty"`
+	Price              float64   `json:"price"`
+	CommissionQuoteQty float64   `json:"commissionQuoteQty"`
+	Time               time.Time `json:"time"`
+}
+
+type OrderState struct {
+	Order
+	Status             Status    `json:"status"`
+	FilledQty          float64   `json:"filledQty"`
+	AvgFillPrice       float64   `json:"avgFillPrice"`
+	CommissionQuoteQty float64   `json:"commissionQuoteQty"`
+	TimeUpdated        time.Time `json:"timeUpdated"`
+	Fills              []Fill    `json:"fills"`
+	Events             []Event   `json:"events"`
+}
+
+// ComputeState replays events and fills which must be sorted by time
+func ComputeState(order Order, events []Event, fills []Fill) OrderState {
+	state := OrderState{
+		Order:       order,
+		Status:      StatusNew,
+		TimeUpdated: order.TimeCreated,
+		Fills:       fills,
+		Events:      events,
+	}
+	var notional float64
+	for _, fill := range fills {
+		state.FilledQty += fill.BaseQty
+		state.CommissionQuoteQty += fill.CommissionQuoteQty
+		notional += fill.BaseQty * fill.Price
+		state.TimeUpdated = fill.Time
+	}
+	if state.FilledQty > 0 {
+		state.AvgFillPrice = notional / state.FilledQty
+		state.Status = StatusPartiallyFilled
+		if order.BaseQty-state.FilledQty <= fillTolerance(order.BaseQty) {
+			state.Status = StatusFilled
+		}
+	}
+	for _, event := range events {
+		state.Status = event.Status
+		state.TimeUpdated = maxTime(state.TimeUpdated, event.Time)
+	}
+	if state.Fills == nil {
+		state.Fills = []Fill{}
+	}
+	if state.Events == nil {
+		state.Events = []Event{}
+	}
+	return state
+}
+
+func fillTolerance(qty float64) float64 {
+	return fillEpsilon * math.Max(1, qty)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// CanTransition reports whether event with status can be appended to the order in this state
+func (s OrderState) CanTransition(status Status) bool {
+	for _, allowed := range transitions[s.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CanFill returns error if fill can't be appended to the order in this state
+func (s OrderState) CanFill(qty float64) error {
+	if s.Status != StatusNew && s.Status != StatusPartiallyFilled {
+		return ErrInvalidTransition
+	}
+	if s.FilledQty+qty-s.BaseQty > fillTolerance(s.BaseQty) {
+		return ErrOverfill
+	}
+	return nil
+}
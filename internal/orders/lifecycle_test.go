@@ -0,0 +1,72 @@
+package orders
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestComputeState(t *testing.T) {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	order := Order{ID: uuid.New(), Side: SideBuy, BaseQty: 2, Price: 10, TimeCreated: created}
+	fill := func(qty, price float64, second int) Fill {
+		return Fill{OrderID: order.ID, BaseQty: qty, Price: price, CommissionQuoteQty: 0.1, Time: created.Add(time.Duration(second) * time.Second)}
+	}
+	testTable := []struct {
+		name                 string
+		events               []Event
+		fills                []Fill
+		expectedStatus       Status
+		expectedFilledQty    float64
+		expectedAvgFillPrice float64
+		canCancel            bool
+		canFillOne           error
+	}{
+		{
+			name:           "NEW",
+			expectedStatus: StatusNew,
+			canCancel:      true,
+		},
+		{
+			name:                 "PARTIALLY FILLED",
+			fills:                []Fill{fill(0.5, 10, 1), fill(0.5, 12, 2)},
+			expectedStatus:       StatusPartiallyFilled,
+			expectedFilledQty:    1,
+			expectedAvgFillPrice: 11,
+			canCancel:            true,
+		},
+		{
+			name:                 "FILLED",
+			fills:                []Fill{fill(1, 10, 1), fill(1, 12, 2)},
+			expectedStatus:       StatusFilled,
+			expectedFilledQty:    2,
+			expectedAvgFillPrice: 11,
+			canFillOne:           ErrInvalidTransition,
+		},
+		{
+			name:                 "CANCELLED AFTER PARTIAL FILL",
+			fills:                []Fill{fill(1.5, 10, 1)},
+			events:               []Event{{OrderID: order.ID, Status: StatusCancelled, Time: created.Add(time.Minute)}},
+			expectedStatus:       StatusCancelled,
+			expectedFilledQty:    1.5,
+			expectedAvgFillPrice: 10,
+			canFillOne:           ErrInvalidTransition,
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				state := ComputeState(order, test.events, test.fills)
+				assert.Equal(t, test.expectedStatus, state.Status)
+				assert.InDelta(t, test.expectedFilledQty, state.FilledQty, 1e-9)
+				assert.InDelta(t, test.expectedAvgFillPrice, state.AvgFillPrice, 1e-9)
+				assert.Equal(t, test.canCancel, state.CanTransition(StatusCancelled))
+				if test.expectedStatus == StatusNew || test.expectedStatus == StatusPartiallyFilled {
+					assert.ErrorIs(t, state.CanFill(2.5), ErrOverfill)
+				}
+				assert.Equal(t, test.canFillOne, state.CanFill(0.5))
+			},
+		)
+	}
+}
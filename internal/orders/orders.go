@@ -5,10 +5,24 @@ import (
 	"time"
 )
 
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
 type Depth struct {
 	Price   float64 `json:"price"`
 	BaseQty float64 `json:"baseQty"`
 }
+
+// BookLevel is a level of order book snapshot of the exchange and pair, levels of a snapshot share time created
+type BookLevel struct {
+	Depth
+	ExchangeName string
+	Pair         string
+	TimeCreated  time.Time
+}
+
 type Book struct {
 	ID       uuid.UUID `json:"id"`
 	Exchange string    `json:"exchange"`
@@ -5,9 +5,14 @@ import (
 	"errors"
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/plinkplenk/test-vortex/internal/tracing"
+	"time"
 )
 
-var ErrOrderNotProvided = errors.New("order not provided")
+var (
+	ErrOrderNotProvided  = errors.New("order not provided")
+	ErrOrderBookNotFound = errors.New("order book not found")
+)
 
 type clickHouseRepository struct {
 	db clickhouse.Conn
@@ -20,9 +25,11 @@ func NewClickHouseRepository(db clickhouse.Conn) Repository {
 }
 
 func (r clickHouseRepository) GetOrderBook(ctx context.Context, exchangeName, pair string) (
-	[]orders.Depth, error,
+	_ []orders.Depth, err error,
 ) {
 	query := `SELECT asks FROM order_book WHERE exchange = ? AND pair = ?`
+	ctx, span := startSpan(ctx, "clickHouseRepository.GetOrderBook", query)
+	defer func() { tracing.End(span, err) }()
 	rows, err := r.db.Query(ctx, query, exchangeName, pair)
 	if err != nil {
 		return nil, err
@@ -46,16 +53,22 @@ func (r clickHouseRepository) GetOrderBook(ctx context.Context, exchangeName, pa
 
 func (r clickHouseRepository) CreateOrderBook(
 	ctx context.Context, exchangeName, pair string, orderBook []orders.Depth,
-) error {
-	batch, err := r.db.PrepareBatch(ctx, "INSERT INTO order_book (exchange, pair, asks)")
+) (err error) {
+	query := "INSERT INTO order_book (exchange, pair, asks, time_created)"
+	ctx, span := startSpan(ctx, "clickHouseRepository.CreateOrderBook", query)
+	defer func() { tracing.End(span, err) }()
+	batch, err := r.db.PrepareBatch(ctx, query)
 	if err != nil {
 		return err
 	}
+	// all levels of one snapshot share the same time so the latest snapshot can be selected
+	timeCreated := time.Now().UTC()
 	for _, order := range orderBook {
 		if err = batch.Append(
 			exchangeName,
 			pair,
 			[]float64{order.Price, order.BaseQty},
+			timeCreated,
 		); err != nil {
 			return err
 		}
@@ -63,14 +76,49 @@ func (r clickHouseRepository) CreateOrderBook(
 	return batch.Send()
 }
 
+func (r clickHouseRepository) GetOrderBookMid(ctx context.Context, exchangeName, pair string) (_ float64, err error) {
+	query := `
+		SELECT minIf(tupleElement(asks, 1), tupleElement(asks, 1) > 0),
+		       maxIf(tupleElement(bids, 1), tupleElement(bids, 1) > 0)
+		FROM order_book
+		WHERE exchange = ? AND pair = ? AND time_created = (
+			SELECT max(time_created) FROM order_book WHERE exchange = ? AND pair = ?
+		)`
+	ctx, span := startSpan(ctx, "clickHouseRepository.GetOrderBookMid", query)
+	defer func() { tracing.End(span, err) }()
+	var bestAsk, bestBid float64
+	if err := r.db.QueryRow(ctx, query, exchangeName, pair, exchangeName, pair).Scan(
+		&bestAsk,
+		&bestBid,
+	); err != nil {
+		return 0, err
+	}
+	return mid(bestAsk, bestBid)
+}
+
+func mid(bestAsk, bestBid float64) (float64, error) {
+	switch {
+	case bestAsk > 0 && bestBid > 0:
+		return (bestAsk + bestBid) / 2, nil
+	case bestAsk > 0:
+		return bestAsk, nil
+	case bestBid > 0:
+		return bestBid, nil
+	default:
+		return 0, ErrOrderBookNotFound
+	}
+}
+
 // GetOrderHistory returns history of order by client name, exchange name, label and pair
 func (r clickHouseRepository) GetOrderHistory(ctx context.Context, client orders.Client) (
-	[]*orders.History, error,
+	_ []*orders.History, err error,
 ) {
 	query := `
 		SELECT side, type, base_qty, price, algorithm_name_placed, lowest_sell_prc, highest_buy_prc, commission_quote_qty, time_placed
 		FROM order_history 
 		WHERE client_name = ? AND exchange_name = ? AND label = ? AND pair = ?`
+	ctx, span := startSpan(ctx, "clickHouseRepository.GetOrderHistory", query)
+	defer func() { tracing.End(span, err) }()
 	rows, err := r.db.Query(ctx, query, client.ClientName, client.ExchangeName, client.Label, client.Pair)
 	if err != nil {
 		return nil, err
@@ -98,7 +146,7 @@ func (r clickHouseRepository) GetOrderHistory(ctx context.Context, client orders
 
 func (r clickHouseRepository) CreateOrder(
 	ctx context.Context, client orders.Client, order *orders.History,
-) error {
+) (err error) {
 	if order == nil {
 		return ErrOrderNotProvided
 	}
@@ -116,6 +164,8 @@ func (r clickHouseRepository) CreateOrder(
 				highest_buy_prc,
 				commission_quote_qty
 			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	ctx, span := startSpan(ctx, "clickHouseRepository.CreateOrder", query)
+	defer func() { tracing.End(span, err) }()
 	if err := r.db.Exec(
 		ctx,
 		query,
@@ -136,3 +186,71 @@ func (r clickHouseRepository) CreateOrder(
 	}
 	return nil
 }
+
+func (r clickHouseRepository) CreateOrders(ctx context.Context, orderHistories []*orders.History) (err error) {
+	query := `INSERT INTO order_history (
+				client_name,
+				exchange_name,
+				label,
+				pair,
+				side,
+				type,
+				base_qty,
+				price,
+				algorithm_name_placed,
+				lowest_sell_prc,
+				highest_buy_prc,
+				commission_quote_qty,
+				time_placed
+			)`
+	ctx, span := startSpan(ctx, "clickHouseRepository.CreateOrders", query)
+	defer func() { tracing.End(span, err) }()
+	batch, err := r.db.PrepareBatch(ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, order := range orderHistories {
+		if order == nil {
+			return ErrOrderNotProvided
+		}
+		if err = batch.Append(
+			order.ClientName,
+			order.ExchangeName,
+			order.Label,
+			order.Pair,
+			order.Side,
+			order.Type,
+			order.BaseQty,
+			order.Price,
+			order.AlgorithmNamePlaced,
+			order.LowestSellPrc,
+			order.HighestBuyPrc,
+			order.CommissionQuoteQty,
+			order.TimePlaced,
+		); err != nil {
+			return err
+		}
+	}
+	return batch.Send()
+}
+
+func (r clickHouseRepository) CreateOrderBookLevels(ctx context.Context, levels []orders.BookLevel) (err error) {
+	query := "INSERT INTO order_book (exchange, pair, asks, time_created)"
+	ctx, span := startSpan(ctx, "clickHouseRepository.CreateOrderBookLevels", query)
+	defer func() { tracing.End(span, err) }()
+	batch, err := r.db.PrepareBatch(ctx, query)
+	if err != nil {
+		return err
+	}
+	for _, level := range levels {
+		if err = batch.Append(
+			level.ExchangeName,
+			level.Pair,
+			[]float64{level.Price, level.BaseQty},
+			level.TimeCreated,
+		); err != nil {
+			return err
+		}
+	}
+	return batch.Send()
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/google/uuid"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+)
+
+type clickHouseLifecycleRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseLifecycleRepository(db clickhouse.Conn) LifecycleRepository {
+	return clickHouseLifecycleRepository{
+		db: db,
+	}
+}
+
+func (r clickHouseLifecycleRepository) CreateLifecycleOrder(ctx context.Context, order orders.Order) error {
+	query := `INSERT INTO order_lifecycle (
+				order_id,
+				client_name,
+				exchange_name,
+				label,
+				pair,
+				side,
+				type,
+				base_qty,
+				price,
+				time_created
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	return r.db.Exec(
+		ctx,
+		query,
+		order.ID,
+		order.ClientName,
+		order.ExchangeName,
+		order.Label,
+		order.Pair,
+		order.Side,
+		order.Type,
+		order.BaseQty,
+		order.Price,
+		order.TimeCreated,
+	)
+}
+
+func (r clickHouseLifecycleRepository) GetLifecycleOrder(ctx context.Context, orderID uuid.UUID) (
+	orders.Order, error,
+) {
+	query := `
+		SELECT client_name, exchange_name, label, pair, side, type, base_qty, price, time_created
+		FROM order_lifecycle FINAL
+		WHERE order_id = ?`
+	order := orders.Order{ID: orderID}
+	if err := r.db.QueryRow(ctx, query, orderID).Scan(
+		&order.ClientName,
+		&order.ExchangeName,
+		&order.Label,
+		&order.Pair,
+		&order.Side,
+		&order.Type,
+		&order.BaseQty,
+		&order.Price,
+		&order.TimeCreated,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return orders.Order{}, orders.ErrOrderNotFound
+		}
+		return orders.Order{}, err
+	}
+	return order, nil
+}
+
+func (r clickHouseLifecycleRepository) CreateEvent(ctx context.Context, event orders.Event) error {
+	query := `INSERT INTO order_events (order_id, status, reason, time) VALUES (?, ?, ?, ?)`
+	return r.db.Exec(ctx, query, event.OrderID, string(event.Status), event.Reason, event.Time)
+}
+
+func (r clickHouseLifecycleRepository) GetEvents(ctx context.Context, orderID uuid.UUID) ([]orders.Event, error) {
+	query := `SELECT status, reason, time FROM order_events WHERE order_id = ? ORDER BY time`
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []orders.Event
+	for rows.Next() {
+		event := orders.Event{OrderID: orderID}
+		var status string
+		if err := rows.Scan(&status, &event.Reason, &event.Time); err != nil {
+			return nil, err
+		}
+		event.Status = orders.Status(status)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (r clickHouseLifecycleRepository) CreateFill(ctx context.Context, fill orders.Fill) error {
+	query := `INSERT INTO order_fills (
+				fill_id,
+				order_id,
+				base_qty,
+				price,
+				commission_quote_qty,
+				time
+			) VALUES (?, ?, ?, ?, ?, ?)`
+	return r.db.Exec(
+		ctx,
+		query,
+		fill.ID,
+		fill.OrderID,
+		fill.BaseQty,
+		fill.Price,
+		fill.CommissionQuoteQty,
+		fill.Time,
+	)
+}
+
+func (r clickHouseLifecycleRepository) GetFills(ctx context.Context, orderID uuid.UUID) ([]orders.Fill, error) {
+	query := `
+		SELECT fill_id, base_qty, price, commission_quote_qty, time
+		FROM order_fills FINAL
+		WHERE order_id = ?
+		ORDER BY time`
+	rows, err := r.db.Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var fills []orders.Fill
+	for rows.Next() {
+		fill := orders.Fill{OrderID: orderID}
+		if err := rows.Scan(
+			&fill.ID,
+			&fill.BaseQty,
+			&fill.Price,
+			&fill.CommissionQuoteQty,
+			&fill.Time,
+		); err != nil {
+			return nil, err
+		}
+		fills = append(fills, fill)
+	}
+	return fills, rows.Err()
+}
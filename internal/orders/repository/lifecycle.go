@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+)
+
+type LifecycleRepository interface {
+	CreateLifecycleOrder(ctx context.Context, order orders.Order) error
+	// GetLifecycleOrder returns orders.ErrOrderNotFound if there is no order with such id
+	GetLifecycleOrder(ctx context.Context, orderID uuid.UUID) (orders.Order, error)
+	CreateEvent(ctx context.Context, event orders.Event) error
+	// GetEvents returns events of the order sorted by time
+	GetEvents(ctx context.Context, orderID uuid.UUID) ([]orders.Event, error)
+	CreateFill(ctx context.Context, fill orders.Fill) error
+	// GetFills returns fills of the order sorted by time
+	GetFills(ctx context.Context, orderID uuid.UUID) ([]orders.Fill, error)
+}
@@ -8,6 +8,13 @@ import (
 type Repository interface {
 	GetOrderBook(ctx context.Context, exchangeName, pair string) ([]orders.Depth, error)
 	CreateOrderBook(ctx context.Context, exchangeName, pair string, orderBook []orders.Depth) error
+	// GetOrderBookMid returns mid price of the latest stored order book snapshot.
+	// If the snapshot has only one side its best price is returned
+	GetOrderBookMid(ctx context.Context, exchangeName, pair string) (float64, error)
 	GetOrderHistory(ctx context.Context, client orders.Client) ([]*orders.History, error)
 	CreateOrder(ctx context.Context, client orders.Client, order *orders.History) error
+	// CreateOrders inserts orders in one batch, unlike CreateOrder it keeps their time placed
+	CreateOrders(ctx context.Context, orderHistories []*orders.History) error
+	// CreateOrderBookLevels inserts levels of order book snapshots in one batch keeping their time created
+	CreateOrderBookLevels(ctx context.Context, levels []orders.BookLevel) error
 }
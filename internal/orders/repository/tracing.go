@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/plinkplenk/test-vortex/internal/requestid"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/plinkplenk/test-vortex/internal/orders/repository")
+
+// startSpan starts client span of the query. Span context is passed to ClickHouse,
+// so spans of the server join the trace if opentelemetry is enabled there.
+// Request id is sent as log_comment to find the query in system.query_log
+func startSpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(
+		ctx,
+		name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemClickhouse, semconv.DBStatement(query)),
+	)
+	options := []clickhouse.QueryOption{clickhouse.WithSpan(span.SpanContext())}
+	if id := requestid.FromContext(ctx); id != "" {
+		options = append(options, clickhouse.WithSettings(clickhouse.Settings{"log_comment": id}))
+	}
+	return clickhouse.Context(ctx, options...), span
+}
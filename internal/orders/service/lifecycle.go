@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	ordersRepository "github.com/plinkplenk/test-vortex/internal/orders/repository"
+	"slices"
+	"time"
+)
+
+//go:generate mockgen -source=lifecycle.go -destination=mocks/lifecycle.go
+type LifecycleService interface {
+	// CreateOrder stores order in new status, order id is generated if not provided.
+	// Returns orders.ErrOrderExists if there is an order with the provided id
+	CreateOrder(ctx context.Context, order orders.Order) (uuid.UUID, error)
+	// GetOrder returns order without its events and fills, orders.ErrOrderNotFound if there is no such order
+	GetOrder(ctx context.Context, orderID uuid.UUID) (orders.Order, error)
+	GetOrderState(ctx context.Context, orderID uuid.UUID) (orders.OrderState, error)
+	// AppendEvent appends cancelled, rejected or expired event.
+	// Returns orders.ErrInvalidTransition if order in its current status can't move to the event status
+	AppendEvent(ctx context.Context, event orders.Event) (orders.OrderState, error)
+	// AppendFill appends fill, order becomes partially filled or filled.
+	// Returns orders.ErrOverfill if fill exceeds remaining quantity.
+	// A fill with id of an appended fill is a retry, it is not appended again and the current state is returned
+	AppendFill(ctx context.Context, fill orders.Fill) (orders.OrderState, error)
+}
+
+// lifecycleService validates transitions against the state read right before the write.
+// ClickHouse has no transactions, so concurrent appends to the same order are not serialized
+type lifecycleService struct {
+	repository ordersRepository.LifecycleRepository
+	timeout    time.Duration
+	now        func() time.Time
+}
+
+func NewLifecycleService(repository ordersRepository.LifecycleRepository, timeout time.Duration) LifecycleService {
+	return lifecycleService{
+		repository: repository,
+		timeout:    timeout,
+		now:        time.Now,
+	}
+}
+
+func (s lifecycleService) CreateOrder(ctx context.Context, order orders.Order) (uuid.UUID, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if order.ID == uuid.Nil {
+		order.ID = uuid.New()
+	} else {
+		// orders are replaced by id in storage, so an existing order must not be created again
+		_, err := s.repository.GetLifecycleOrder(c, order.ID)
+		if err == nil {
+			return uuid.Nil, orders.ErrOrderExists
+		}
+		if !errors.Is(err, orders.ErrOrderNotFound) {
+			return uuid.Nil, err
+		}
+	}
+	if order.TimeCreated.IsZero() {
+		order.TimeCreated = s.now().UTC()
+	}
+	if err := s.repository.CreateLifecycleOrder(c, order); err != nil {
+		return uuid.Nil, err
+	}
+	return order.ID, nil
+}
+
+func (s lifecycleService) GetOrder(ctx context.Context, orderID uuid.UUID) (orders.Order, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.repository.GetLifecycleOrder(c, orderID)
+}
+
+func (s lifecycleService) GetOrderState(ctx context.Context, orderID uuid.UUID) (orders.OrderState, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.getOrderState(c, orderID)
+}
+
+func (s lifecycleService) getOrderState(ctx context.Context, orderID uuid.UUID) (orders.OrderState, error) {
+	order, err := s.repository.GetLifecycleOrder(ctx, orderID)
+	if err != nil {
+		return orders.OrderState{}, err
+	}
+	events, err := s.repository.GetEvents(ctx, orderID)
+	if err != nil {
+		return orders.OrderState{}, err
+	}
+	fills, err := s.repository.GetFills(ctx, orderID)
+	if err != nil {
+		return orders.OrderState{}, err
+	}
+	return orders.ComputeState(order, events, fills), nil
+}
+
+func (s lifecycleService) AppendEvent(ctx context.Context, event orders.Event) (orders.OrderState, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	state, err := s.getOrderState(c, event.OrderID)
+	if err != nil {
+		return orders.OrderState{}, err
+	}
+	if !state.CanTransition(event.Status) {
+		return orders.OrderState{}, orders.ErrInvalidTransition
+	}
+	if event.Time.IsZero() {
+		event.Time = s.now().UTC()
+	}
+	if err := s.repository.CreateEvent(c, event); err != nil {
+		return orders.OrderState{}, err
+	}
+	return orders.ComputeState(state.Order, append(state.Events, event), state.Fills), nil
+}
+
+func (s lifecycleService) AppendFill(ctx context.Context, fill orders.Fill) (orders.OrderState, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	state, err := s.getOrderState(c, fill.OrderID)
+	if err != nil {
+		return orders.OrderState{}, err
+	}
+	if fill.ID != uuid.Nil && slices.ContainsFunc(state.Fills, func(f orders.Fill) bool { return f.ID == fill.ID }) {
+		return state, nil
+	}
+	if err := state.CanFill(fill.BaseQty); err != nil {
+		return orders.OrderState{}, err
+	}
+	if fill.ID == uuid.Nil {
+		fill.ID = uuid.New()
+	}
+	if fill.Time.IsZero() {
+		fill.Time = s.now().UTC()
+	}
+	if err := s.repository.CreateFill(c, fill); err != nil {
+		return orders.OrderState{}, err
+	}
+	return orders.ComputeState(state.Order, state.Events, append(state.Fills, fill)), nil
+}
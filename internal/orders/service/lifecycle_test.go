@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// lifecycleRepositoryStub keeps orders, events and fills in memory
+type lifecycleRepositoryStub struct {
+	orders map[uuid.UUID]orders.Order
+	events []orders.Event
+	fills  []orders.Fill
+}
+
+func newLifecycleRepositoryStub() *lifecycleRepositoryStub {
+	return &lifecycleRepositoryStub{orders: map[uuid.UUID]orders.Order{}}
+}
+
+func (r *lifecycleRepositoryStub) CreateLifecycleOrder(_ context.Context, order orders.Order) error {
+	r.orders[order.ID] = order
+	return nil
+}
+
+func (r *lifecycleRepositoryStub) GetLifecycleOrder(_ context.Context, orderID uuid.UUID) (orders.Order, error) {
+	order, ok := r.orders[orderID]
+	if !ok {
+		return orders.Order{}, orders.ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (r *lifecycleRepositoryStub) CreateEvent(_ context.Context, event orders.Event) error {
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *lifecycleRepositoryStub) GetEvents(_ context.Context, orderID uuid.UUID) ([]orders.Event, error) {
+	var events []orders.Event
+	for _, event := range r.events {
+		if event.OrderID == orderID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (r *lifecycleRepositoryStub) CreateFill(_ context.Context, fill orders.Fill) error {
+	r.fills = append(r.fills, fill)
+	return nil
+}
+
+func (r *lifecycleRepositoryStub) GetFills(_ context.Context, orderID uuid.UUID) ([]orders.Fill, error) {
+	var fills []orders.Fill
+	for _, fill := range r.fills {
+		if fill.OrderID == orderID {
+			fills = append(fills, fill)
+		}
+	}
+	return fills, nil
+}
+
+func TestLifecycleService_CreateOrder(t *testing.T) {
+	repository := newLifecycleRepositoryStub()
+	service := NewLifecycleService(repository, time.Second)
+	orderID := uuid.New()
+	order := orders.Order{ID: orderID, Client: orders.Client{ClientName: "client"}, Side: "buy", BaseQty: 2}
+
+	id, err := service.CreateOrder(context.Background(), order)
+	assert.NoError(t, err)
+	assert.Equal(t, orderID, id)
+
+	order.Client.ClientName = "other"
+	_, err = service.CreateOrder(context.Background(), order)
+	assert.ErrorIs(t, err, orders.ErrOrderExists)
+	assert.Equal(t, "client", repository.orders[orderID].ClientName, "existing order is not replaced")
+
+	id, err = service.CreateOrder(context.Background(), orders.Order{Side: "sell", BaseQty: 1})
+	assert.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, id)
+}
+
+func TestLifecycleService_AppendFill(t *testing.T) {
+	repository := newLifecycleRepositoryStub()
+	service := NewLifecycleService(repository, time.Second)
+	orderID, err := service.CreateOrder(context.Background(), orders.Order{Side: "buy", BaseQty: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill := orders.Fill{ID: uuid.New(), OrderID: orderID, BaseQty: 1.5, Price: 10}
+
+	state, err := service.AppendFill(context.Background(), fill)
+	assert.NoError(t, err)
+	assert.Equal(t, orders.StatusPartiallyFilled, state.Status)
+
+	state, err = service.AppendFill(context.Background(), fill)
+	assert.NoError(t, err, "retried fill is not an overfill")
+	assert.Equal(t, orders.StatusPartiallyFilled, state.Status)
+	assert.Equal(t, 1.5, state.FilledQty)
+	assert.Len(t, repository.fills, 1, "retried fill is not appended again")
+
+	_, err = service.AppendFill(context.Background(), orders.Fill{OrderID: orderID, BaseQty: 1, Price: 10})
+	assert.ErrorIs(t, err, orders.ErrOverfill)
+}
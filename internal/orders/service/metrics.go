@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/metrics"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"time"
+)
+
+const metricsServiceName = "orders"
+
+type instrumentedOrderService struct {
+	service OrdersService
+	metrics *metrics.Metrics
+}
+
+// NewInstrumented wraps service to record operation latencies, errors and ingested rows
+func NewInstrumented(service OrdersService, metrics *metrics.Metrics) OrdersService {
+	return instrumentedOrderService{service: service, metrics: metrics}
+}
+
+func (s instrumentedOrderService) observe(operation string, start time.Time, err error) {
+	s.metrics.ObserveOperation(metricsServiceName, operation, time.Since(start), err)
+}
+
+func (s instrumentedOrderService) GetOrderBook(ctx context.Context, exchangeName, pair string) ([]orders.Depth, error) {
+	start := time.Now()
+	orderBook, err := s.service.GetOrderBook(ctx, exchangeName, pair)
+	s.observe("GetOrderBook", start, err)
+	return orderBook, err
+}
+
+func (s instrumentedOrderService) SaveOrderBook(
+	ctx context.Context, exchangeName, pair string, orderBook []orders.Depth,
+) error {
+	start := time.Now()
+	err := s.service.SaveOrderBook(ctx, exchangeName, pair, orderBook)
+	s.observe("SaveOrderBook", start, err)
+	if err == nil {
+		s.metrics.AddIngestedBookLevels(len(orderBook))
+	}
+	return err
+}
+
+func (s instrumentedOrderService) GetOrderHistory(ctx context.Context, client orders.Client) ([]*orders.History, error) {
+	start := time.Now()
+	history, err := s.service.GetOrderHistory(ctx, client)
+	s.observe("GetOrderHistory", start, err)
+	return history, err
+}
+
+func (s instrumentedOrderService) SaveOrder(ctx context.Context, client orders.Client, order *orders.History) error {
+	start := time.Now()
+	err := s.service.SaveOrder(ctx, client, order)
+	s.observe("SaveOrder", start, err)
+	if err == nil {
+		s.metrics.AddIngestedOrders(1)
+	}
+	return err
+}
@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: lifecycle.go
+//
+// Generated by this command:
+//
+//	mockgen -source=lifecycle.go -destination=mocks/lifecycle.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	orders "github.com/plinkplenk/test-vortex/internal/orders"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLifecycleService is a mock of LifecycleService interface.
+type MockLifecycleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLifecycleServiceMockRecorder
+}
+
+// MockLifecycleServiceMockRecorder is the mock recorder for MockLifecycleService.
+type MockLifecycleServiceMockRecorder struct {
+	mock *MockLifecycleService
+}
+
+// NewMockLifecycleService creates a new mock instance.
+func NewMockLifecycleService(ctrl *gomock.Controller) *MockLifecycleService {
+	mock := &MockLifecycleService{ctrl: ctrl}
+	mock.recorder = &MockLifecycleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLifecycleService) EXPECT() *MockLifecycleServiceMockRecorder {
+	return m.recorder
+}
+
+// AppendEvent mocks base method.
+func (m *MockLifecycleService) AppendEvent(ctx context.Context, event orders.Event) (orders.OrderState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendEvent", ctx, event)
+	ret0, _ := ret[0].(orders.OrderState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendEvent indicates an expected call of AppendEvent.
+func (mr *MockLifecycleServiceMockRecorder) AppendEvent(ctx, event any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendEvent", reflect.TypeOf((*MockLifecycleService)(nil).AppendEvent), ctx, event)
+}
+
+// AppendFill mocks base method.
+func (m *MockLifecycleService) AppendFill(ctx context.Context, fill orders.Fill) (orders.OrderState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendFill", ctx, fill)
+	ret0, _ := ret[0].(orders.OrderState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendFill indicates an expected call of AppendFill.
+func (mr *MockLifecycleServiceMockRecorder) AppendFill(ctx, fill any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendFill", reflect.TypeOf((*MockLifecycleService)(nil).AppendFill), ctx, fill)
+}
+
+// CreateOrder mocks base method.
+func (m *MockLifecycleService) CreateOrder(ctx context.Context, order orders.Order) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", ctx, order)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockLifecycleServiceMockRecorder) CreateOrder(ctx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockLifecycleService)(nil).CreateOrder), ctx, order)
+}
+
+// GetOrder mocks base method.
+func (m *MockLifecycleService) GetOrder(ctx context.Context, orderID uuid.UUID) (orders.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderID)
+	ret0, _ := ret[0].(orders.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockLifecycleServiceMockRecorder) GetOrder(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockLifecycleService)(nil).GetOrder), ctx, orderID)
+}
+
+// GetOrderState mocks base method.
+func (m *MockLifecycleService) GetOrderState(ctx context.Context, orderID uuid.UUID) (orders.OrderState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderState", ctx, orderID)
+	ret0, _ := ret[0].(orders.OrderState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderState indicates an expected call of GetOrderState.
+func (mr *MockLifecycleServiceMockRecorder) GetOrderState(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderState", reflect.TypeOf((*MockLifecycleService)(nil).GetOrderState), ctx, orderID)
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"github.com/plinkplenk/test-vortex/internal/orders"
 	ordersRepository "github.com/plinkplenk/test-vortex/internal/orders/repository"
+	"github.com/plinkplenk/test-vortex/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"time"
 )
 
+var tracer = otel.Tracer("github.com/plinkplenk/test-vortex/internal/orders/service")
+
 //go:generate mockgen -source=orders.go -destination=mocks/mock.go
 type OrdersService interface {
 	GetOrderBook(ctx context.Context, exchangeName, pair string) ([]orders.Depth, error)
@@ -28,9 +33,12 @@ func New(repository ordersRepository.Repository, timeout time.Duration) OrdersSe
 }
 
 func (s orderService) GetOrderBook(ctx context.Context, exchangeName, pair string) ([]orders.Depth, error) {
+	ctx, span := tracer.Start(ctx, "orderService.GetOrderBook")
+	span.SetAttributes(attribute.String("exchange", exchangeName), attribute.String("pair", pair))
 	c, cancel := context.WithCancel(ctx)
 	defer cancel()
 	depth, err := s.repository.GetOrderBook(c, exchangeName, pair)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, err
 	}
@@ -38,16 +46,26 @@ func (s orderService) GetOrderBook(ctx context.Context, exchangeName, pair strin
 }
 
 func (s orderService) SaveOrderBook(ctx context.Context, exchangeName, pair string, orderBook []orders.Depth) error {
+	ctx, span := tracer.Start(ctx, "orderService.SaveOrderBook")
+	span.SetAttributes(
+		attribute.String("exchange", exchangeName),
+		attribute.String("pair", pair),
+		attribute.Int("levels", len(orderBook)),
+	)
 	c, cancel := context.WithCancel(ctx)
 	defer cancel()
 	err := s.repository.CreateOrderBook(c, exchangeName, pair, orderBook)
+	tracing.End(span, err)
 	return err
 }
 
 func (s orderService) GetOrderHistory(ctx context.Context, client orders.Client) ([]*orders.History, error) {
+	ctx, span := tracer.Start(ctx, "orderService.GetOrderHistory")
+	span.SetAttributes(clientAttributes(client)...)
 	c, cancel := context.WithCancel(ctx)
 	defer cancel()
 	history, err := s.repository.GetOrderHistory(c, client)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, err
 	}
@@ -55,11 +73,23 @@ func (s orderService) GetOrderHistory(ctx context.Context, client orders.Client)
 }
 
 func (s orderService) SaveOrder(ctx context.Context, client orders.Client, order *orders.History) error {
+	ctx, span := tracer.Start(ctx, "orderService.SaveOrder")
+	span.SetAttributes(clientAttributes(client)...)
 	c, cancel := context.WithCancel(ctx)
 	defer cancel()
 	err := s.repository.CreateOrder(c, client, order)
+	tracing.End(span, err)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+func clientAttributes(client orders.Client) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("client", client.ClientName),
+		attribute.String("exchange", client.ExchangeName),
+		attribute.String("label", client.Label),
+		attribute.String("pair", client.Pair),
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"slices"
+	"sync"
+)
+
+// BookUpdate is an order book saved through a service returned by NewPublishing
+type BookUpdate struct {
+	ExchangeName string
+	Pair         string
+	Depth        []orders.Depth
+}
+
+type bookKey struct {
+	exchangeName string
+	pair         string
+}
+
+// BookUpdates delivers saved order books to subscribers of their exchange and pair.
+// Only books saved by this process are delivered
+type BookUpdates struct {
+	mu          sync.Mutex
+	subscribers map[bookKey]map[chan BookUpdate]struct{}
+	closed      bool
+}
+
+func NewBookUpdates() *BookUpdates {
+	return &BookUpdates{subscribers: make(map[bookKey]map[chan BookUpdate]struct{})}
+}
+
+// Subscribe returns channel receiving books of the pair and function that unsubscribes and closes the channel.
+// The channel keeps only the latest book, so a slow subscriber skips intermediate ones.
+// The channel is closed by Close as well, channels of subscriptions after Close are closed already
+func (u *BookUpdates) Subscribe(exchangeName, pair string) (<-chan BookUpdate, func()) {
+	key := bookKey{exchangeName: exchangeName, pair: pair}
+	updates := make(chan BookUpdate, 1)
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.closed {
+		close(updates)
+		return updates, func() {}
+	}
+	if u.subscribers[key] == nil {
+		u.subscribers[key] = make(map[chan BookUpdate]struct{})
+	}
+	u.subscribers[key][updates] = struct{}{}
+	return updates, func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		// the channel is closed already if it is unsubscribed or closed by Close
+		if _, ok := u.subscribers[key][updates]; !ok {
+			return
+		}
+		delete(u.subscribers[key], updates)
+		if len(u.subscribers[key]) == 0 {
+			delete(u.subscribers, key)
+		}
+		close(updates)
+	}
+}
+
+// Close closes channels of all subscribers, so they stop waiting for books on shutdown
+func (u *BookUpdates) Close() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.closed = true
+	for key, subscribers := range u.subscribers {
+		for updates := range subscribers {
+			close(updates)
+		}
+		delete(u.subscribers, key)
+	}
+}
+
+// Publish delivers the book to subscribers without waiting for them
+func (u *BookUpdates) Publish(update BookUpdate) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for updates := range u.subscribers[bookKey{exchangeName: update.ExchangeName, pair: update.Pair}] {
+		select {
+		case updates <- update:
+		default:
+			// the buffer holds a book the subscriber hasn't received yet, it is replaced with the latest one.
+			// Publishers hold the lock, so the buffer can't be filled again before the send
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- update
+		}
+	}
+}
+
+type publishingOrderService struct {
+	OrdersService
+	updates *BookUpdates
+}
+
+// NewPublishing wraps service to publish every saved order book to updates
+func NewPublishing(service OrdersService, updates *BookUpdates) OrdersService {
+	return publishingOrderService{OrdersService: service, updates: updates}
+}
+
+func (s publishingOrderService) SaveOrderBook(
+	ctx context.Context, exchangeName, pair string, orderBook []orders.Depth,
+) error {
+	if err := s.OrdersService.SaveOrderBook(ctx, exchangeName, pair, orderBook); err != nil {
+		return err
+	}
+	s.updates.Publish(BookUpdate{ExchangeName: exchangeName, Pair: pair, Depth: slices.Clone(orderBook)})
+	return nil
+}
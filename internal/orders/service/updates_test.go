@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBookUpdates(t *testing.T) {
+	updates := NewBookUpdates()
+	received, unsubscribe := updates.Subscribe("some-exchange", "A_B")
+	other, unsubscribeOther := updates.Subscribe("some-exchange", "C_D")
+	defer unsubscribeOther()
+
+	first := BookUpdate{ExchangeName: "some-exchange", Pair: "A_B", Depth: []orders.Depth{{Price: 1, BaseQty: 1}}}
+	latest := BookUpdate{ExchangeName: "some-exchange", Pair: "A_B", Depth: []orders.Depth{{Price: 2, BaseQty: 1}}}
+	updates.Publish(first)
+	updates.Publish(latest)
+	assert.Equal(t, latest, <-received, "slow subscriber gets the latest book")
+	assert.Empty(t, other)
+
+	unsubscribe()
+	unsubscribe()
+	_, ok := <-received
+	assert.False(t, ok, "channel is closed on unsubscribe")
+	updates.Publish(first)
+}
+
+type orderServiceStub struct {
+	OrdersService
+	err error
+}
+
+func (s orderServiceStub) SaveOrderBook(context.Context, string, string, []orders.Depth) error {
+	return s.err
+}
+
+func TestPublishingOrderService_SaveOrderBook(t *testing.T) {
+	updates := NewBookUpdates()
+	received, unsubscribe := updates.Subscribe("some-exchange", "A_B")
+	defer unsubscribe()
+	depth := []orders.Depth{{Price: 1, BaseQty: 1}}
+
+	err := NewPublishing(orderServiceStub{err: errors.New("db is down")}, updates).
+		SaveOrderBook(context.Background(), "some-exchange", "A_B", depth)
+	assert.Error(t, err)
+	assert.Empty(t, received, "failed saves are not published")
+
+	err = NewPublishing(orderServiceStub{}, updates).SaveOrderBook(context.Background(), "some-exchange", "A_B", depth)
+	assert.NoError(t, err)
+	assert.Equal(t, BookUpdate{ExchangeName: "some-exchange", Pair: "A_B", Depth: depth}, <-received)
+}
+
+func TestBookUpdates_Close(t *testing.T) {
+	updates := NewBookUpdates()
+	received, unsubscribe := updates.Subscribe("some-exchange", "A_B")
+
+	updates.Close()
+	_, ok := <-received
+	assert.False(t, ok, "channel is closed on close")
+	unsubscribe()
+
+	late, unsubscribeLate := updates.Subscribe("some-exchange", "A_B")
+	_, ok = <-late
+	assert.False(t, ok, "channel subscribed after close is closed")
+	unsubscribeLate()
+	updates.Publish(BookUpdate{ExchangeName: "some-exchange", Pair: "A_B"})
+}
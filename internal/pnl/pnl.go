@@ -0,0 +1,135 @@
+package pnl
+
+import (
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"math"
+	"strings"
+)
+
+type Method string
+
+const (
+	MethodFIFO        Method = "fifo"
+	MethodAverageCost Method = "average"
+)
+
+// epsilon is used to treat float residuals of closed positions as zero
+const epsilon = 1e-12
+
+var ErrUnknownMethod = errors.New("unknown pnl method, use fifo or average")
+
+func ParseMethod(method string) (Method, error) {
+	if m := Method(strings.ToLower(method)); m == MethodFIFO || m == MethodAverageCost {
+		return m, nil
+	}
+	return "", ErrUnknownMethod
+}
+
+type Position struct {
+	orders.Client
+	Method        Method  `json:"method"`
+	NetQty        float64 `json:"netQty"`
+	AvgEntryPrice float64 `json:"avgEntryPrice"`
+	RealizedPnL   float64 `json:"realizedPnl"`
+	// UnrealizedPnL is zero when there is no order book to mark the position against
+	UnrealizedPnL float64 `json:"unrealizedPnl"`
+	// MarkPrice is the latest order book mid, zero if not available
+	MarkPrice float64 `json:"markPrice"`
+	Fees      float64 `json:"fees"`
+	NetPnL    float64 `json:"netPnl"`
+	Trades    int     `json:"trades"`
+	// IgnoredOrders is the number of orders with side other than buy or sell
+	IgnoredOrders int `json:"ignoredOrders"`
+}
+
+// lot is an open part of position, qty is negative for short lots
+type lot struct {
+	qty   float64
+	price float64
+}
+
+// Compute builds position from the history which must be sorted by time placed
+func Compute(client orders.Client, history []*orders.History, method Method) Position {
+	position := Position{Client: client, Method: method}
+	var lots []lot
+	for _, order := range history {
+		var qty float64
+		switch {
+		case strings.EqualFold(order.Side, orders.SideBuy):
+			qty = order.BaseQty
+		case strings.EqualFold(order.Side, orders.SideSell):
+			qty = -order.BaseQty
+		default:
+			position.IgnoredOrders++
+			continue
+		}
+		position.Trades++
+		position.Fees += order.CommissionQuoteQty
+		if method == MethodAverageCost {
+			position.applyAverageCost(qty, order.Price)
+		} else {
+			lots = position.applyFIFO(lots, qty, order.Price)
+		}
+	}
+	if method != MethodAverageCost {
+		position.NetQty, position.AvgEntryPrice = 0, 0
+		var cost float64
+		for _, l := range lots {
+			position.NetQty += l.qty
+			cost += math.Abs(l.qty) * l.price
+		}
+		if math.Abs(position.NetQty) > epsilon {
+			position.AvgEntryPrice = cost / math.Abs(position.NetQty)
+		}
+	}
+	position.NetPnL = position.RealizedPnL - position.Fees
+	return position
+}
+
+// Mark sets mark price and unrealized pnl of the open position
+func (p *Position) Mark(price float64) {
+	p.MarkPrice = price
+	p.UnrealizedPnL = p.NetQty * (price - p.AvgEntryPrice)
+	p.NetPnL = p.RealizedPnL + p.UnrealizedPnL - p.Fees
+}
+
+func (p *Position) applyFIFO(lots []lot, qty, price float64) []lot {
+	for len(lots) > 0 && math.Abs(qty) > epsilon && (lots[0].qty > 0) != (qty > 0) {
+		matched := math.Min(math.Abs(qty), math.Abs(lots[0].qty))
+		direction := math.Copysign(1, lots[0].qty)
+		p.RealizedPnL += matched * (price - lots[0].price) * direction
+		lots[0].qty -= matched * direction
+		qty += matched * direction
+		if math.Abs(lots[0].qty) <= epsilon {
+			lots = lots[1:]
+		}
+	}
+	if math.Abs(qty) > epsilon {
+		lots = append(lots, lot{qty: qty, price: price})
+	}
+	return lots
+}
+
+func (p *Position) applyAverageCost(qty, price float64) {
+	if math.Abs(qty) <= epsilon {
+		return
+	}
+	if math.Abs(p.NetQty) <= epsilon || (p.NetQty > 0) == (qty > 0) {
+		total := math.Abs(p.NetQty) + math.Abs(qty)
+		p.AvgEntryPrice = (math.Abs(p.NetQty)*p.AvgEntryPrice + math.Abs(qty)*price) / total
+		p.NetQty += qty
+		return
+	}
+	closing := math.Min(math.Abs(qty), math.Abs(p.NetQty))
+	p.RealizedPnL += closing * (price - p.AvgEntryPrice) * math.Copysign(1, p.NetQty)
+	wasLong := p.NetQty > 0
+	p.NetQty += qty
+	switch {
+	case math.Abs(p.NetQty) <= epsilon:
+		p.NetQty, p.AvgEntryPrice = 0, 0
+	case (p.NetQty > 0) != wasLong:
+		// position flipped, the remainder is opened at the trade price
+		p.AvgEntryPrice = price
+	}
+}
@@ -0,0 +1,88 @@
+package pnl
+
+import (
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	client := orders.Client{ClientName: "client", ExchangeName: "exchange", Label: "label", Pair: "A_B"}
+	history := []*orders.History{
+		{Side: "buy", BaseQty: 1, Price: 10, CommissionQuoteQty: 0.1},
+		{Side: "BUY", BaseQty: 1, Price: 20, CommissionQuoteQty: 0.1},
+		{Side: "sell", BaseQty: 1.5, Price: 30, CommissionQuoteQty: 0.1},
+		{Side: "some-side", BaseQty: 100, Price: 1},
+	}
+	testTable := []struct {
+		name     string
+		method   Method
+		expected Position
+	}{
+		{
+			name:   "FIFO",
+			method: MethodFIFO,
+			expected: Position{
+				Client:        client,
+				Method:        MethodFIFO,
+				NetQty:        0.5,
+				AvgEntryPrice: 20,
+				RealizedPnL:   25,
+				Fees:          0.3,
+				NetPnL:        24.7,
+				Trades:        3,
+				IgnoredOrders: 1,
+			},
+		},
+		{
+			name:   "AVERAGE COST",
+			method: MethodAverageCost,
+			expected: Position{
+				Client:        client,
+				Method:        MethodAverageCost,
+				NetQty:        0.5,
+				AvgEntryPrice: 15,
+				RealizedPnL:   22.5,
+				Fees:          0.3,
+				NetPnL:        22.2,
+				Trades:        3,
+				IgnoredOrders: 1,
+			},
+		},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				position := Compute(client, history, test.method)
+				assert.Equal(t, test.expected.NetQty, position.NetQty)
+				assert.InDelta(t, test.expected.AvgEntryPrice, position.AvgEntryPrice, 1e-9)
+				assert.InDelta(t, test.expected.RealizedPnL, position.RealizedPnL, 1e-9)
+				assert.InDelta(t, test.expected.Fees, position.Fees, 1e-9)
+				assert.InDelta(t, test.expected.NetPnL, position.NetPnL, 1e-9)
+				assert.Equal(t, test.expected.Trades, position.Trades)
+				assert.Equal(t, test.expected.IgnoredOrders, position.IgnoredOrders)
+			},
+		)
+	}
+}
+
+func TestCompute_Flip(t *testing.T) {
+	history := []*orders.History{
+		{Side: "buy", BaseQty: 1, Price: 10},
+		{Side: "sell", BaseQty: 3, Price: 12},
+	}
+	for _, method := range []Method{MethodFIFO, MethodAverageCost} {
+		t.Run(
+			string(method), func(t *testing.T) {
+				position := Compute(orders.Client{}, history, method)
+				assert.Equal(t, -2.0, position.NetQty)
+				assert.InDelta(t, 12, position.AvgEntryPrice, 1e-9)
+				assert.InDelta(t, 2, position.RealizedPnL, 1e-9)
+
+				position.Mark(11)
+				assert.InDelta(t, 2, position.UnrealizedPnL, 1e-9)
+				assert.InDelta(t, 4, position.NetPnL, 1e-9)
+			},
+		)
+	}
+}
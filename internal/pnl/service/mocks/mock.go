@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pnl.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pnl.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	orders "github.com/plinkplenk/test-vortex/internal/orders"
+	pnl "github.com/plinkplenk/test-vortex/internal/pnl"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPnLService is a mock of PnLService interface.
+type MockPnLService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPnLServiceMockRecorder
+}
+
+// MockPnLServiceMockRecorder is the mock recorder for MockPnLService.
+type MockPnLServiceMockRecorder struct {
+	mock *MockPnLService
+}
+
+// NewMockPnLService creates a new mock instance.
+func NewMockPnLService(ctrl *gomock.Controller) *MockPnLService {
+	mock := &MockPnLService{ctrl: ctrl}
+	mock.recorder = &MockPnLServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPnLService) EXPECT() *MockPnLServiceMockRecorder {
+	return m.recorder
+}
+
+// GetPosition mocks base method.
+func (m *MockPnLService) GetPosition(ctx context.Context, client orders.Client, method pnl.Method) (pnl.Position, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPosition", ctx, client, method)
+	ret0, _ := ret[0].(pnl.Position)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPosition indicates an expected call of GetPosition.
+func (mr *MockPnLServiceMockRecorder) GetPosition(ctx, client, method any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPosition", reflect.TypeOf((*MockPnLService)(nil).GetPosition), ctx, client, method)
+}
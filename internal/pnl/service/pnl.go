@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	ordersRepository "github.com/plinkplenk/test-vortex/internal/orders/repository"
+	"github.com/plinkplenk/test-vortex/internal/pnl"
+	"slices"
+	"time"
+)
+
+//go:generate mockgen -source=pnl.go -destination=mocks/mock.go
+type PnLService interface {
+	GetPosition(ctx context.Context, client orders.Client, method pnl.Method) (pnl.Position, error)
+}
+
+type pnlService struct {
+	repository ordersRepository.Repository
+	timeout    time.Duration
+}
+
+func New(repository ordersRepository.Repository, timeout time.Duration) PnLService {
+	return pnlService{
+		repository: repository,
+		timeout:    timeout,
+	}
+}
+
+// GetPosition computes position of the client from its order history
+// and marks it against the latest stored order book mid if there is one
+func (s pnlService) GetPosition(ctx context.Context, client orders.Client, method pnl.Method) (
+	pnl.Position, error,
+) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	history, err := s.repository.GetOrderHistory(c, client)
+	if err != nil {
+		return pnl.Position{}, err
+	}
+	slices.SortStableFunc(
+		history, func(a, b *orders.History) int {
+			return a.TimePlaced.Compare(b.TimePlaced)
+		},
+	)
+	position := pnl.Compute(client, history, method)
+	mid, err := s.repository.GetOrderBookMid(c, client.ExchangeName, client.Pair)
+	if err != nil {
+		if errors.Is(err, ordersRepository.ErrOrderBookNotFound) {
+			return position, nil
+		}
+		return pnl.Position{}, err
+	}
+	position.Mark(mid)
+	return position, nil
+}
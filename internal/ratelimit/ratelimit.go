@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst tokens.
+// Zero Rate disables limiting
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Decision is the result of taking a token from a bucket
+type Decision struct {
+	Allowed bool
+	Limit   int
+	// Remaining is number of whole tokens left in the bucket
+	Remaining int
+	// RetryAfter is time until the next token is available, zero if request is allowed
+	RetryAfter time.Duration
+	// Reset is time until the bucket is full again
+	Reset time.Duration
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter keeps a token bucket per key, it is safe for concurrent use
+type Limiter struct {
+	mu        sync.Mutex
+	limit     Limit
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewLimiter(limit Limit) *Limiter {
+	return &Limiter{limit: limit, buckets: make(map[string]*bucket)}
+}
+
+// Enabled reports whether limiter limits anything
+func (l *Limiter) Enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit.Rate > 0
+}
+
+// SetLimit replaces the limit, buckets keep their tokens up to the new burst
+func (l *Limiter) SetLimit(limit Limit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	burst := l.burst()
+	for _, b := range l.buckets {
+		b.tokens = math.Min(burst, b.tokens)
+	}
+}
+
+// fillTime is how long an empty bucket takes to refill, buckets idle for longer are full and can be dropped
+func (l *Limiter) fillTime() time.Duration {
+	return l.duration(l.burst())
+}
+
+func (l *Limiter) burst() float64 {
+	return float64(max(l.limit.Burst, 1))
+}
+
+// Allow takes a token from the bucket of key
+func (l *Limiter) Allow(key string, now time.Time) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit.Rate <= 0 {
+		return Decision{Allowed: true}
+	}
+	burst := l.burst()
+	if fill := l.fillTime(); now.Sub(l.lastSweep) > fill {
+		for k, b := range l.buckets {
+			if now.Sub(b.updated) > fill {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*l.limit.Rate)
+		b.updated = now
+	}
+	decision := Decision{Limit: int(burst)}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = l.duration(1 - b.tokens)
+	}
+	decision.Remaining = int(b.tokens)
+	decision.Reset = l.duration(burst - b.tokens)
+	return decision
+}
+
+// duration is time needed to refill tokens
+func (l *Limiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.limit.Rate * float64(time.Second))
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(Limit{Rate: 2, Burst: 3})
+
+	for i := 2; i >= 0; i-- {
+		decision := limiter.Allow("a", now)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, i, decision.Remaining)
+		assert.Equal(t, 3, decision.Limit)
+	}
+	decision := limiter.Allow("a", now)
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, decision.Reset)
+
+	assert.True(t, limiter.Allow("b", now).Allowed, "buckets are per key")
+
+	decision = limiter.Allow("a", now.Add(500*time.Millisecond))
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 0, decision.Remaining)
+
+	decision = limiter.Allow("a", now.Add(time.Hour))
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, 2, decision.Remaining, "bucket is refilled up to burst")
+}
+
+func TestLimiter_SetLimit(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(Limit{})
+	assert.True(t, limiter.Allow("a", now).Allowed)
+
+	limiter.SetLimit(Limit{Rate: 1, Burst: 5})
+	assert.True(t, limiter.Enabled())
+	assert.Equal(t, 4, limiter.Allow("a", now).Remaining)
+
+	limiter.SetLimit(Limit{Rate: 1, Burst: 2})
+	decision := limiter.Allow("a", now)
+	assert.Equal(t, 2, decision.Limit)
+	assert.Equal(t, 1, decision.Remaining, "tokens are capped by the new burst")
+
+	limiter.SetLimit(Limit{})
+	assert.False(t, limiter.Enabled())
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	limiter := NewLimiter(Limit{})
+	assert.False(t, limiter.Enabled())
+	for range 100 {
+		assert.True(t, limiter.Allow("a", time.Now()).Allowed)
+	}
+}
+
+func TestLimiter_Concurrent(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(Limit{Rate: 1, Burst: 50})
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if limiter.Allow("a", now).Allowed {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(50), allowed.Load())
+}
@@ -0,0 +1,89 @@
+package replay
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"time"
+)
+
+const (
+	EventBook  = "book"
+	EventOrder = "order"
+)
+
+// Event is order book snapshot or order of the replayed pair, Time is the time it was stored or placed
+type Event struct {
+	Type  string          `json:"type"`
+	Time  time.Time       `json:"time"`
+	Book  *Book           `json:"book,omitempty"`
+	Order *orders.History `json:"order,omitempty"`
+}
+
+// Book is order book snapshot, levels are in the order they were stored
+type Book struct {
+	Asks []orders.Depth `json:"asks"`
+	Bids []orders.Depth `json:"bids"`
+}
+
+type Query struct {
+	ExchangeName string
+	Pair         string
+	// ClientName is optional filter of orders, order books are not filtered by it
+	ClientName string
+	// From is inclusive and To is exclusive
+	From time.Time
+	To   time.Time
+	// Speed divides time between events, 1 is real time. Events are emitted without waiting if it is 0
+	Speed float64
+}
+
+// Merge merges book and order events sorted by time, books go before orders of the same time
+// so orders see the book they were placed against
+func Merge(books, orders []Event) []Event {
+	merged := make([]Event, 0, len(books)+len(orders))
+	i, j := 0, 0
+	for i < len(books) && j < len(orders) {
+		if !orders[j].Time.Before(books[i].Time) {
+			merged = append(merged, books[i])
+			i++
+		} else {
+			merged = append(merged, orders[j])
+			j++
+		}
+	}
+	merged = append(merged, books[i:]...)
+	return append(merged, orders[j:]...)
+}
+
+// Pacer delays events so time between them is their original time divided by speed
+type Pacer struct {
+	speed float64
+	// origin is replayed time at start
+	origin time.Time
+	start  time.Time
+	now    func() time.Time
+	after  func(d time.Duration) <-chan time.Time
+}
+
+// NewPacer starts replay clock at origin
+func NewPacer(origin time.Time, speed float64) *Pacer {
+	return &Pacer{speed: speed, origin: origin, start: time.Now(), now: time.Now, after: time.After}
+}
+
+// Wait returns when event of the time is due or ctx is done
+func (p *Pacer) Wait(ctx context.Context, t time.Time) error {
+	if p.speed <= 0 {
+		return ctx.Err()
+	}
+	due := p.start.Add(time.Duration(float64(t.Sub(p.origin)) / p.speed))
+	wait := due.Sub(p.now())
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.after(wait):
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+package replay
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMerge(t *testing.T) {
+	at := func(seconds int) time.Time {
+		return time.Date(2024, 1, 2, 0, 0, seconds, 0, time.UTC)
+	}
+	books := []Event{{Type: EventBook, Time: at(1)}, {Type: EventBook, Time: at(3)}, {Type: EventBook, Time: at(5)}}
+	orders := []Event{{Type: EventOrder, Time: at(0)}, {Type: EventOrder, Time: at(3)}, {Type: EventOrder, Time: at(4)}}
+	assert.Equal(
+		t,
+		[]Event{orders[0], books[0], books[1], orders[1], orders[2], books[2]},
+		Merge(books, orders),
+		"books go before orders of the same time",
+	)
+	assert.Equal(t, orders, Merge(nil, orders))
+	assert.Empty(t, Merge(nil, nil))
+}
+
+func TestPacer_Wait(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	origin := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	testTable := []struct {
+		name         string
+		speed        float64
+		elapsed      time.Duration
+		event        time.Duration
+		expectedWait time.Duration
+	}{
+		{name: "REAL TIME", speed: 1, event: 2 * time.Second, expectedWait: 2 * time.Second},
+		{name: "ACCELERATED", speed: 4, event: 2 * time.Second, expectedWait: 500 * time.Millisecond},
+		{name: "ELAPSED", speed: 1, elapsed: time.Second, event: 2 * time.Second, expectedWait: time.Second},
+		{name: "LATE", speed: 1, elapsed: 3 * time.Second, event: 2 * time.Second},
+		{name: "NO WAITING", speed: 0, event: time.Hour},
+	}
+	for _, test := range testTable {
+		t.Run(
+			test.name, func(t *testing.T) {
+				var waited time.Duration
+				p := &Pacer{
+					speed:  test.speed,
+					origin: origin,
+					start:  start,
+					now:    func() time.Time { return start.Add(test.elapsed) },
+					after: func(d time.Duration) <-chan time.Time {
+						waited = d
+						c := make(chan time.Time, 1)
+						c <- start
+						return c
+					},
+				}
+				assert.NoError(t, p.Wait(context.Background(), origin.Add(test.event)))
+				assert.Equal(t, test.expectedWait, waited)
+			},
+		)
+	}
+}
+
+func TestPacer_WaitCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := NewPacer(time.Now(), 1)
+	assert.ErrorIs(t, p.Wait(ctx, time.Now().Add(time.Hour)), context.Canceled)
+}
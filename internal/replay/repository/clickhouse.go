@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/plinkplenk/test-vortex/internal/export"
+	"github.com/plinkplenk/test-vortex/internal/orders"
+	"github.com/plinkplenk/test-vortex/internal/replay"
+	"strings"
+	"time"
+)
+
+type clickHouseRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseRepository(db clickhouse.Conn) Repository {
+	return clickHouseRepository{
+		db: db,
+	}
+}
+
+func (r clickHouseRepository) GetEvents(ctx context.Context, query replay.Query) ([]replay.Event, error) {
+	books, err := r.getBooks(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	orderEvents, err := r.getOrders(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return replay.Merge(books, orderEvents), nil
+}
+
+func (r clickHouseRepository) getBooks(ctx context.Context, query replay.Query) (_ []replay.Event, err error) {
+	q := `
+		SELECT tupleElement(asks, 1), tupleElement(asks, 2), tupleElement(bids, 1), tupleElement(bids, 2), time_created
+		FROM order_book
+		WHERE exchange = ? AND pair = ? AND time_created >= ? AND time_created < ?
+		ORDER BY time_created`
+	rows, err := r.db.Query(ctx, q, query.ExchangeName, query.Pair, query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	var events []replay.Event
+	for rows.Next() {
+		var (
+			ask, bid    orders.Depth
+			timeCreated time.Time
+		)
+		if err := rows.Scan(&ask.Price, &ask.BaseQty, &bid.Price, &bid.BaseQty, &timeCreated); err != nil {
+			return nil, err
+		}
+		if len(events) == 0 || !events[len(events)-1].Time.Equal(timeCreated) {
+			events = append(
+				events,
+				replay.Event{
+					Type: replay.EventBook,
+					Time: timeCreated,
+					Book: &replay.Book{Asks: []orders.Depth{}, Bids: []orders.Depth{}},
+				},
+			)
+		}
+		// side of a level that is not set is stored as zero tuple
+		book := events[len(events)-1].Book
+		if ask.Price > 0 {
+			book.Asks = append(book.Asks, ask)
+		}
+		if bid.Price > 0 {
+			book.Bids = append(book.Bids, bid)
+		}
+	}
+	return events, rows.Err()
+}
+
+func (r clickHouseRepository) getOrders(ctx context.Context, query replay.Query) (_ []replay.Event, err error) {
+	columns := export.Columns()
+	q := "SELECT " + strings.Join(columns, ", ") + `
+		FROM order_history
+		WHERE exchange_name = ? AND pair = ? AND time_placed >= ? AND time_placed < ?`
+	args := []any{query.ExchangeName, query.Pair, query.From, query.To}
+	if query.ClientName != "" {
+		q += " AND client_name = ?"
+		args = append(args, query.ClientName)
+	}
+	q += " ORDER BY time_placed"
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	var events []replay.Event
+	for rows.Next() {
+		h := &orders.History{}
+		if err := rows.Scan(export.Fields(h, columns)...); err != nil {
+			return nil, err
+		}
+		events = append(events, replay.Event{Type: replay.EventOrder, Time: h.TimePlaced, Order: h})
+	}
+	return events, rows.Err()
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/replay"
+)
+
+type Repository interface {
+	// GetEvents returns order book snapshots and orders of the query in chronological order,
+	// levels of a snapshot are rows of order_book with the same time created
+	GetEvents(ctx context.Context, query replay.Query) ([]replay.Event, error)
+}
@@ -0,0 +1,55 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: replay.go
+//
+// Generated by this command:
+//
+//	mockgen -source=replay.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	replay "github.com/plinkplenk/test-vortex/internal/replay"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReplayService is a mock of ReplayService interface.
+type MockReplayService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReplayServiceMockRecorder
+}
+
+// MockReplayServiceMockRecorder is the mock recorder for MockReplayService.
+type MockReplayServiceMockRecorder struct {
+	mock *MockReplayService
+}
+
+// NewMockReplayService creates a new mock instance.
+func NewMockReplayService(ctrl *gomock.Controller) *MockReplayService {
+	mock := &MockReplayService{ctrl: ctrl}
+	mock.recorder = &MockReplayServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReplayService) EXPECT() *MockReplayServiceMockRecorder {
+	return m.recorder
+}
+
+// Replay mocks base method.
+func (m *MockReplayService) Replay(ctx context.Context, query replay.Query, fn func(replay.Event) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replay", ctx, query, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replay indicates an expected call of Replay.
+func (mr *MockReplayServiceMockRecorder) Replay(ctx, query, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replay", reflect.TypeOf((*MockReplayService)(nil).Replay), ctx, query, fn)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/replay"
+	replayRepository "github.com/plinkplenk/test-vortex/internal/replay/repository"
+	"time"
+)
+
+//go:generate mockgen -source=replay.go -destination=mocks/mock.go
+type ReplayService interface {
+	// Replay calls fn with events of the query in chronological order as they become due at the query speed.
+	// Events are loaded by chunks, so replays of long windows are not limited by timeout and memory
+	Replay(ctx context.Context, query replay.Query, fn func(event replay.Event) error) error
+}
+
+type replayService struct {
+	repository replayRepository.Repository
+	// timeout limits loading of one chunk
+	timeout time.Duration
+	chunk   time.Duration
+}
+
+func New(repository replayRepository.Repository, timeout time.Duration, chunk time.Duration) ReplayService {
+	return replayService{
+		repository: repository,
+		timeout:    timeout,
+		chunk:      chunk,
+	}
+}
+
+func (s replayService) Replay(ctx context.Context, query replay.Query, fn func(event replay.Event) error) error {
+	pacer := replay.NewPacer(query.From, query.Speed)
+	for from := query.From; from.Before(query.To); from = from.Add(s.chunk) {
+		chunk := query
+		chunk.From = from
+		chunk.To = from.Add(s.chunk)
+		if chunk.To.After(query.To) {
+			chunk.To = query.To
+		}
+		events, err := s.getEvents(ctx, chunk)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if err := pacer.Wait(ctx, event.Time); err != nil {
+				return err
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s replayService) getEvents(ctx context.Context, query replay.Query) ([]replay.Event, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.repository.GetEvents(c, query)
+}
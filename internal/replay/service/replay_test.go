@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"github.com/plinkplenk/test-vortex/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// repositoryStub returns events of the window from the stored ones and records queried windows
+type repositoryStub struct {
+	events  []replay.Event
+	queries []replay.Query
+	err     error
+}
+
+func (r *repositoryStub) GetEvents(_ context.Context, query replay.Query) ([]replay.Event, error) {
+	r.queries = append(r.queries, query)
+	var events []replay.Event
+	for _, event := range r.events {
+		if !event.Time.Before(query.From) && event.Time.Before(query.To) {
+			events = append(events, event)
+		}
+	}
+	return events, r.err
+}
+
+func TestReplayService_Replay(t *testing.T) {
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	repository := &repositoryStub{
+		events: []replay.Event{
+			{Type: replay.EventBook, Time: from},
+			{Type: replay.EventOrder, Time: from.Add(30 * time.Second)},
+			{Type: replay.EventBook, Time: from.Add(90 * time.Second)},
+			{Type: replay.EventOrder, Time: from.Add(150 * time.Second)},
+		},
+	}
+	query := replay.Query{ExchangeName: "exchange", Pair: "A_B", From: from, To: from.Add(150 * time.Second)}
+	var replayed []replay.Event
+	err := New(repository, time.Second, time.Minute).Replay(
+		context.Background(), query, func(event replay.Event) error {
+			replayed = append(replayed, event)
+			return nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, repository.events[:3], replayed)
+
+	assert.Len(t, repository.queries, 3, "window is loaded by chunks")
+	assert.Equal(t, from.Add(2*time.Minute), repository.queries[2].From)
+	assert.Equal(t, query.To, repository.queries[2].To, "the last chunk ends at the end of the window")
+	assert.Equal(t, "A_B", repository.queries[2].Pair)
+}
+
+func TestReplayService_ReplayError(t *testing.T) {
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	query := replay.Query{From: from, To: from.Add(time.Hour)}
+	repositoryErr := errors.New("db is down")
+	err := New(&repositoryStub{err: repositoryErr}, time.Second, time.Minute).Replay(
+		context.Background(), query, func(replay.Event) error { return nil },
+	)
+	assert.ErrorIs(t, err, repositoryErr)
+
+	stopErr := errors.New("stopped")
+	repository := &repositoryStub{events: []replay.Event{{Time: from}, {Time: from.Add(2 * time.Minute)}}}
+	err = New(repository, time.Second, time.Minute).Replay(
+		context.Background(), query, func(replay.Event) error { return stopErr },
+	)
+	assert.ErrorIs(t, err, stopErr)
+	assert.Len(t, repository.queries, 1, "replay stops on the first error")
+}
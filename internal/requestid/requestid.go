@@ -0,0 +1,66 @@
+package requestid
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+const Header = "X-Request-ID"
+
+// maxLength limits ids accepted from clients, longer ids are replaced with generated ones
+const maxLength = 128
+
+type idKey struct{}
+
+// New generates request id
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether id received from client can be used as is, it must be short printable ASCII
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, c := range []byte(id) {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// FromContext returns request id stored by request id middleware, empty string if there is none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(idKey{}).(string)
+	return id
+}
+
+type logHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler adds request_id from record context to every record
+func NewLogHandler(handler slog.Handler) slog.Handler {
+	return logHandler{Handler: handler}
+}
+
+func (h logHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return logHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h logHandler) WithGroup(name string) slog.Handler {
+	return logHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,21 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"testing"
+)
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil)))
+
+	logger.ErrorContext(WithID(context.Background(), "req-1"), "query failed")
+	assert.Contains(t, buf.String(), `msg="query failed" request_id=req-1`)
+
+	buf.Reset()
+	logger.Info("no request")
+	assert.NotContains(t, buf.String(), "request_id")
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/plinkplenk/test-vortex/internal/tca"
+)
+
+type clickHouseRepository struct {
+	db clickhouse.Conn
+}
+
+func NewClickHouseRepository(db clickhouse.Conn) Repository {
+	return clickHouseRepository{
+		db: db,
+	}
+}
+
+// GetReports aggregates order history by algorithm.
+// direction is 1 for buys and -1 for sells, touch is the best price on the opposite side at placement.
+// Orders without recorded best bid/ask count in volumes and commission, but not in slippage, spread capture
+// and maker/taker inference
+func (r clickHouseRepository) GetReports(ctx context.Context, query tca.Query) ([]tca.Report, error) {
+	q := `
+		SELECT algorithm_name_placed,
+		       count(),
+		       sum(base_qty),
+		       sum(quote_qty),
+		       ifNotFinite(sumIf(quote_qty * slippage_bps, touched) / sumIf(quote_qty, touched), 0),
+		       ifNotFinite(avgIf(spread_capture, touched AND spread > 0), 0),
+		       ifNotFinite(sum(commission_quote_qty) / sum(quote_qty) * 10000, 0),
+		       countIf(touched AND NOT taker),
+		       countIf(touched AND taker),
+		       countIf(NOT touched)
+		FROM (
+			SELECT algorithm_name_placed,
+			       base_qty,
+			       commission_quote_qty,
+			       base_qty * price AS quote_qty,
+			       lowest_sell_prc > 0 AND highest_buy_prc > 0 AS touched,
+			       if(lower(side) = 'buy', 1, -1) AS direction,
+			       if(direction = 1, lowest_sell_prc, highest_buy_prc) AS touch,
+			       direction * (price - touch) / touch * 10000 AS slippage_bps,
+			       lowest_sell_prc - highest_buy_prc AS spread,
+			       if(direction = 1, lowest_sell_prc - price, price - highest_buy_prc) / spread AS spread_capture,
+			       if(direction = 1, price >= lowest_sell_prc, price <= highest_buy_prc) AS taker
+			FROM order_history
+			WHERE time_placed >= ? AND time_placed < ?
+			  AND (? = '' OR algorithm_name_placed = ?)
+			  AND lower(side) IN ('buy', 'sell')
+		)
+		GROUP BY algorithm_name_placed
+		ORDER BY algorithm_name_placed`
+	rows, err := r.db.Query(ctx, q, query.From, query.To, query.Algorithm, query.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var reports []tca.Report
+	for rows.Next() {
+		var report tca.Report
+		if err := rows.Scan(
+			&report.Algorithm,
+			&report.Orders,
+			&report.BaseVolume,
+			&report.QuoteVolume,
+			&report.AvgSlippageBps,
+			&report.AvgSpreadCapture,
+			&report.CommissionBps,
+			&report.MakerOrders,
+			&report.TakerOrders,
+			&report.NoTouchOrders,
+		); err != nil {
+			return nil, err
+		}
+		if inferred := report.MakerOrders + report.TakerOrders; inferred > 0 {
+			report.MakerRatio = float64(report.MakerOrders) / float64(inferred)
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/tca"
+)
+
+type Repository interface {
+	GetReports(ctx context.Context, query tca.Query) ([]tca.Report, error)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: tca.go
+//
+// Generated by this command:
+//
+//	mockgen -source=tca.go -destination=mocks/mock.go
+//
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	reflect "reflect"
+
+	tca "github.com/plinkplenk/test-vortex/internal/tca"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockTCAService is a mock of TCAService interface.
+type MockTCAService struct {
+	ctrl     *gomock.Controller
+	recorder *MockTCAServiceMockRecorder
+}
+
+// MockTCAServiceMockRecorder is the mock recorder for MockTCAService.
+type MockTCAServiceMockRecorder struct {
+	mock *MockTCAService
+}
+
+// NewMockTCAService creates a new mock instance.
+func NewMockTCAService(ctrl *gomock.Controller) *MockTCAService {
+	mock := &MockTCAService{ctrl: ctrl}
+	mock.recorder = &MockTCAServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTCAService) EXPECT() *MockTCAServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReports mocks base method.
+func (m *MockTCAService) GetReports(ctx context.Context, query tca.Query) ([]tca.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReports", ctx, query)
+	ret0, _ := ret[0].([]tca.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReports indicates an expected call of GetReports.
+func (mr *MockTCAServiceMockRecorder) GetReports(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReports", reflect.TypeOf((*MockTCAService)(nil).GetReports), ctx, query)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"github.com/plinkplenk/test-vortex/internal/tca"
+	tcaRepository "github.com/plinkplenk/test-vortex/internal/tca/repository"
+	"time"
+)
+
+//go:generate mockgen -source=tca.go -destination=mocks/mock.go
+type TCAService interface {
+	GetReports(ctx context.Context, query tca.Query) ([]tca.Report, error)
+}
+
+type tcaService struct {
+	repository tcaRepository.Repository
+	timeout    time.Duration
+}
+
+func New(repository tcaRepository.Repository, timeout time.Duration) TCAService {
+	return tcaService{
+		repository: repository,
+		timeout:    timeout,
+	}
+}
+
+func (s tcaService) GetReports(ctx context.Context, query tca.Query) ([]tca.Report, error) {
+	c, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.repository.GetReports(c, query)
+}
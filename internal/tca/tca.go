@@ -0,0 +1,85 @@
+package tca
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+type Query struct {
+	From time.Time
+	To   time.Time
+	// Algorithm filters report by algorithm name, empty means all algorithms
+	Algorithm string
+}
+
+// Report is transaction cost analysis of one algorithm.
+// Only orders with side buy or sell are taken into account, orders without recorded best bid/ask
+// are left out of slippage, spread capture and maker/taker counts
+type Report struct {
+	Algorithm   string  `json:"algorithm"`
+	Orders      uint64  `json:"orders"`
+	BaseVolume  float64 `json:"baseVolume"`
+	QuoteVolume float64 `json:"quoteVolume"`
+	// AvgSlippageBps is quote volume weighted slippage versus best ask for buys
+	// and best bid for sells at placement, positive value is a cost
+	AvgSlippageBps float64 `json:"avgSlippageBps"`
+	// AvgSpreadCapture is the share of the spread captured, 1 means bought at bid or sold at ask
+	// and 0 means bought at ask or sold at bid
+	AvgSpreadCapture float64 `json:"avgSpreadCapture"`
+	CommissionBps    float64 `json:"commissionBps"`
+	// MakerOrders and TakerOrders are inferred from price, order is a taker if it crossed the touch
+	MakerOrders uint64 `json:"makerOrders"`
+	TakerOrders uint64 `json:"takerOrders"`
+	// MakerRatio is the share of makers among orders with recorded best bid/ask
+	MakerRatio float64 `json:"makerRatio"`
+	// NoTouchOrders have no recorded best bid/ask
+	NoTouchOrders uint64 `json:"noTouchOrders"`
+}
+
+var csvHeader = []string{
+	"algorithm",
+	"orders",
+	"base_volume",
+	"quote_volume",
+	"avg_slippage_bps",
+	"avg_spread_capture",
+	"commission_bps",
+	"maker_orders",
+	"taker_orders",
+	"maker_ratio",
+	"no_touch_orders",
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func WriteCSV(w io.Writer, reports []Report) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range reports {
+		if err := writer.Write(
+			[]string{
+				r.Algorithm,
+				strconv.FormatUint(r.Orders, 10),
+				formatFloat(r.BaseVolume),
+				formatFloat(r.QuoteVolume),
+				formatFloat(r.AvgSlippageBps),
+				formatFloat(r.AvgSpreadCapture),
+				formatFloat(r.CommissionBps),
+				strconv.FormatUint(r.MakerOrders, 10),
+				strconv.FormatUint(r.TakerOrders, 10),
+				formatFloat(r.MakerRatio),
+				strconv.FormatUint(r.NoTouchOrders, 10),
+			},
+		); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
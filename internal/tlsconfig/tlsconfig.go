@@ -0,0 +1,163 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+var (
+	ErrNoCertificates     = errors.New("no certificates found in CA file")
+	ErrNoPeerCertificates = errors.New("peer did not present a certificate")
+	ErrInvalidVersion     = errors.New(`invalid TLS version, expected "1.2" or "1.3"`)
+	ErrInvalidClientAuth  = errors.New(`invalid client auth, expected "none", "request", "verify_if_given" or "require"`)
+)
+
+var versions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseVersion parses TLS version like "1.2"
+func ParseVersion(s string) (uint16, error) {
+	version, ok := versions[s]
+	if !ok {
+		return 0, fmt.Errorf("%w, got %q", ErrInvalidVersion, s)
+	}
+	return version, nil
+}
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	"none":            tls.NoClientCert,
+	"request":         tls.RequestClientCert,
+	"verify_if_given": tls.VerifyClientCertIfGiven,
+	"require":         tls.RequireAndVerifyClientCert,
+}
+
+// ParseClientAuth parses server policy for client certificates, "request" doesn't verify them
+func ParseClientAuth(s string) (tls.ClientAuthType, error) {
+	clientAuth, ok := clientAuthTypes[s]
+	if !ok {
+		return 0, fmt.Errorf("%w, got %q", ErrInvalidClientAuth, s)
+	}
+	return clientAuth, nil
+}
+
+// Files are PEM files, every one of them is optional
+type Files struct {
+	CertFile string
+	KeyFile  string
+	// CAFile is bundle verifying peer certificates, system roots are used by clients if it is empty
+	CAFile string
+}
+
+type state struct {
+	// certificate is nil if there is no certificate file
+	certificate *tls.Certificate
+	// pool is nil if there is no CA file
+	pool *x509.CertPool
+}
+
+// Reloader keeps certificate and CA bundle read from files, configs it creates
+// use the last successfully loaded files for every new connection
+type Reloader struct {
+	files   Files
+	current *atomic.Pointer[state]
+}
+
+// New creates reloader and loads files
+func New(files Files) (Reloader, error) {
+	r := Reloader{files: files, current: &atomic.Pointer[state]{}}
+	if err := r.Refresh(context.Background()); err != nil {
+		return Reloader{}, err
+	}
+	return r, nil
+}
+
+// Refresh reads files again, previously loaded files are kept if reading fails
+func (r Reloader) Refresh(_ context.Context) error {
+	var s state
+	if r.files.CertFile != "" || r.files.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(r.files.CertFile, r.files.KeyFile)
+		if err != nil {
+			return fmt.Errorf("loading certificate: %w", err)
+		}
+		s.certificate = &certificate
+	}
+	if r.files.CAFile != "" {
+		content, err := os.ReadFile(r.files.CAFile)
+		if err != nil {
+			return fmt.Errorf("loading CA file: %w", err)
+		}
+		s.pool = x509.NewCertPool()
+		if !s.pool.AppendCertsFromPEM(content) {
+			return fmt.Errorf("%w %s", ErrNoCertificates, r.files.CAFile)
+		}
+	}
+	r.current.Store(&s)
+	return nil
+}
+
+// ServerConfig creates config of a listener, certificate is required.
+// Client certificates are verified against CA bundle unless clientAuth is tls.RequestClientCert
+func (r Reloader) ServerConfig(minVersion uint16, clientAuth tls.ClientAuthType) *tls.Config {
+	nextProtos := []string{"h2", "http/1.1"}
+	return &tls.Config{
+		MinVersion: minVersion,
+		NextProtos: nextProtos,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.current.Load().certificate, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			s := r.current.Load()
+			return &tls.Config{
+				MinVersion:   minVersion,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*s.certificate},
+				ClientAuth:   clientAuth,
+				ClientCAs:    s.pool,
+			}, nil
+		},
+	}
+}
+
+// ClientConfig creates config of a client connection, certificate is sent if server requests it.
+// Server certificate is verified for serverName, or the dialed host if it is empty
+func (r Reloader) ClientConfig(serverName string, minVersion uint16, insecureSkipVerify bool) *tls.Config {
+	return &tls.Config{
+		ServerName: serverName,
+		MinVersion: minVersion,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if certificate := r.current.Load().certificate; certificate != nil {
+				return certificate, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+		// default verification can't use reloaded CA bundle, it is done by VerifyConnection
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if insecureSkipVerify {
+				return nil
+			}
+			return verify(cs, r.current.Load().pool)
+		},
+	}
+}
+
+func verify(cs tls.ConnectionState, roots *x509.CertPool) error {
+	if len(cs.PeerCertificates) == 0 {
+		return ErrNoPeerCertificates
+	}
+	intermediates := x509.NewCertPool()
+	for _, certificate := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+	_, err := cs.PeerCertificates[0].Verify(
+		x509.VerifyOptions{DNSName: cs.ServerName, Roots: roots, Intermediates: intermediates},
+	)
+	return err
+}
@@ -0,0 +1,186 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type authority struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+}
+
+func newAuthority(t *testing.T, name string) authority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return authority{
+		certificate: certificate,
+		key:         key,
+		pem:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue writes certificate for localhost signed by the authority and its key to dir
+func (a authority) issue(t *testing.T, dir, name string, serial int64) Files {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, a.certificate, &key.PublicKey, a.key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	files := Files{
+		CertFile: filepath.Join(dir, name+".crt"),
+		KeyFile:  filepath.Join(dir, name+".key"),
+	}
+	assert.NoError(t, os.WriteFile(files.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	assert.NoError(t, os.WriteFile(files.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return files
+}
+
+func writeCA(t *testing.T, dir, name string, authorities ...authority) string {
+	path := filepath.Join(dir, name)
+	var content []byte
+	for _, a := range authorities {
+		content = append(content, a.pem...)
+	}
+	assert.NoError(t, os.WriteFile(path, content, 0o600))
+	return path
+}
+
+// handshake connects client to a server using the configs and returns server certificate serial
+// and subject of the client certificate verified by server. With TLS 1.3 client handshake completes
+// before server verifies client certificate, so rejection by server is returned as errRejected
+func handshake(t *testing.T, server, client *tls.Config) (int64, string, error) {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	assert.NoError(t, err)
+	defer listener.Close()
+	verified := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			verified <- ""
+			return
+		}
+		defer conn.Close()
+		tlsConn := conn.(*tls.Conn)
+		if tlsConn.Handshake() != nil || len(tlsConn.ConnectionState().VerifiedChains) == 0 {
+			verified <- ""
+			return
+		}
+		verified <- tlsConn.ConnectionState().VerifiedChains[0][0].Subject.CommonName
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	conn, err := tls.Dial("tcp", net.JoinHostPort("localhost", port), client)
+	if err != nil {
+		<-verified
+		return 0, "", err
+	}
+	defer conn.Close()
+	serial := conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	subject := <-verified
+	if subject == "" {
+		return 0, "", errRejected
+	}
+	return serial, subject, nil
+}
+
+var errRejected = errors.New("client certificate is rejected by server")
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	serverCA := newAuthority(t, "server-ca")
+	clientCA := newAuthority(t, "client-ca")
+	otherCA := newAuthority(t, "other-ca")
+
+	serverFiles := serverCA.issue(t, dir, "server", 1)
+	serverFiles.CAFile = writeCA(t, dir, "clients.pem", clientCA)
+	server, err := New(serverFiles)
+	assert.NoError(t, err)
+	serverConfig := server.ServerConfig(tls.VersionTLS12, tls.RequireAndVerifyClientCert)
+
+	clientFiles := clientCA.issue(t, dir, "gateway", 1)
+	clientFiles.CAFile = writeCA(t, dir, "servers.pem", serverCA)
+	client, err := New(clientFiles)
+	assert.NoError(t, err)
+
+	serial, subject, err := handshake(t, serverConfig, client.ClientConfig("", tls.VersionTLS12, false))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), serial)
+	assert.Equal(t, "gateway", subject)
+
+	untrusted, err := New(Files{CAFile: writeCA(t, dir, "other.pem", otherCA)})
+	assert.NoError(t, err)
+	_, _, err = handshake(t, serverConfig, untrusted.ClientConfig("", tls.VersionTLS12, false))
+	assert.Error(t, err, "server certificate is not signed by client CA bundle")
+
+	_, _, err = handshake(t, serverConfig, untrusted.ClientConfig("", tls.VersionTLS12, true))
+	assert.ErrorIs(t, err, errRejected, "client certificate is required")
+
+	// rotated server certificate and client CA bundle apply to new connections after refresh
+	serverCA.issue(t, dir, "server", 2)
+	writeCA(t, dir, "clients.pem", otherCA)
+	assert.NoError(t, server.Refresh(context.Background()))
+	_, _, err = handshake(t, serverConfig, client.ClientConfig("", tls.VersionTLS12, false))
+	assert.ErrorIs(t, err, errRejected, "client CA is no longer trusted")
+
+	writeCA(t, dir, "clients.pem", otherCA, clientCA)
+	assert.NoError(t, server.Refresh(context.Background()))
+	serial, _, err = handshake(t, serverConfig, client.ClientConfig("", tls.VersionTLS12, false))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), serial)
+
+	assert.NoError(t, os.WriteFile(serverFiles.CAFile, []byte("not a certificate"), 0o600))
+	assert.ErrorIs(t, server.Refresh(context.Background()), ErrNoCertificates)
+	_, _, err = handshake(t, serverConfig, client.ClientConfig("", tls.VersionTLS12, false))
+	assert.NoError(t, err, "previous files are kept if refresh fails")
+}
+
+func TestParse(t *testing.T) {
+	version, err := ParseVersion("1.3")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+	_, err = ParseVersion("1.0")
+	assert.ErrorIs(t, err, ErrInvalidVersion)
+
+	clientAuth, err := ParseClientAuth("verify_if_given")
+	assert.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, clientAuth)
+	_, err = ParseClientAuth("optional")
+	assert.ErrorIs(t, err, ErrInvalidClientAuth)
+}
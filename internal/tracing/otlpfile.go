@@ -0,0 +1,248 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"io"
+	"sync"
+	"time"
+)
+
+// otlpIDFields are bytes fields of OTLP messages that OTLP JSON encodes as hex instead of base64 of protojson
+var otlpIDFields = map[string]bool{"traceId": true, "spanId": true, "parentSpanId": true}
+
+// otlpFileExporter writes every batch of spans as one line of OTLP JSON ExportTraceServiceRequest,
+// the format of the OpenTelemetry Collector file exporter, so the file can be replayed by OTLP tools
+type otlpFileExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newOTLPFileExporter(w io.Writer) *otlpFileExporter {
+	return &otlpFileExporter{w: w}
+}
+
+func (e *otlpFileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	line, err := marshalOTLP(otlpRequest(spans))
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(line, '\n'))
+	return err
+}
+
+// Shutdown does nothing, the file is closed by Setup
+func (e *otlpFileExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// marshalOTLP encodes request as OTLP JSON, it is protojson with enums as numbers and ids as hex
+func marshalOTLP(request *collectorpb.ExportTraceServiceRequest) ([]byte, error) {
+	b, err := protojson.MarshalOptions{UseEnumNumbers: true}.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if err := hexIDs(value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// hexIDs replaces base64 ids of decoded protojson value with hex
+func hexIDs(value any) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if encoded, ok := field.(string); ok && otlpIDFields[key] {
+				id, err := base64.StdEncoding.DecodeString(encoded)
+				if err != nil {
+					return err
+				}
+				v[key] = hex.EncodeToString(id)
+				continue
+			}
+			if err := hexIDs(field); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := hexIDs(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// otlpRequest groups spans by resource and instrumentation scope
+func otlpRequest(spans []sdktrace.ReadOnlySpan) *collectorpb.ExportTraceServiceRequest {
+	request := &collectorpb.ExportTraceServiceRequest{}
+	resources := map[*resource.Resource]*tracepb.ResourceSpans{}
+	scopes := map[*resource.Resource]map[instrumentation.Scope]*tracepb.ScopeSpans{}
+	for _, span := range spans {
+		res := span.Resource()
+		resourceSpans, ok := resources[res]
+		if !ok {
+			resourceSpans = &tracepb.ResourceSpans{
+				Resource:  &resourcepb.Resource{Attributes: otlpAttributes(res.Attributes())},
+				SchemaUrl: res.SchemaURL(),
+			}
+			resources[res] = resourceSpans
+			scopes[res] = map[instrumentation.Scope]*tracepb.ScopeSpans{}
+			request.ResourceSpans = append(request.ResourceSpans, resourceSpans)
+		}
+		scope := span.InstrumentationScope()
+		scopeSpans, ok := scopes[res][scope]
+		if !ok {
+			scopeSpans = &tracepb.ScopeSpans{
+				Scope:     &commonpb.InstrumentationScope{Name: scope.Name, Version: scope.Version},
+				SchemaUrl: scope.SchemaURL,
+			}
+			scopes[res][scope] = scopeSpans
+			resourceSpans.ScopeSpans = append(resourceSpans.ScopeSpans, scopeSpans)
+		}
+		scopeSpans.Spans = append(scopeSpans.Spans, otlpSpan(span))
+	}
+	return request
+}
+
+func otlpSpan(span sdktrace.ReadOnlySpan) *tracepb.Span {
+	spanContext := span.SpanContext()
+	traceID, spanID := spanContext.TraceID(), spanContext.SpanID()
+	result := &tracepb.Span{
+		TraceId:                traceID[:],
+		SpanId:                 spanID[:],
+		TraceState:             spanContext.TraceState().String(),
+		Flags:                  otlpFlags(spanContext.TraceFlags(), span.Parent()),
+		Name:                   span.Name(),
+		Kind:                   tracepb.Span_SpanKind(span.SpanKind()),
+		StartTimeUnixNano:      unixNano(span.StartTime()),
+		EndTimeUnixNano:        unixNano(span.EndTime()),
+		Attributes:             otlpAttributes(span.Attributes()),
+		DroppedAttributesCount: uint32(span.DroppedAttributes()),
+		DroppedEventsCount:     uint32(span.DroppedEvents()),
+		DroppedLinksCount:      uint32(span.DroppedLinks()),
+		Status:                 &tracepb.Status{Message: span.Status().Description},
+	}
+	if parent := span.Parent(); parent.IsValid() {
+		parentID := parent.SpanID()
+		result.ParentSpanId = parentID[:]
+	}
+	switch span.Status().Code {
+	case codes.Ok:
+		result.Status.Code = tracepb.Status_STATUS_CODE_OK
+	case codes.Error:
+		result.Status.Code = tracepb.Status_STATUS_CODE_ERROR
+	}
+	for _, event := range span.Events() {
+		result.Events = append(
+			result.Events,
+			&tracepb.Span_Event{
+				TimeUnixNano:           unixNano(event.Time),
+				Name:                   event.Name,
+				Attributes:             otlpAttributes(event.Attributes),
+				DroppedAttributesCount: uint32(event.DroppedAttributeCount),
+			},
+		)
+	}
+	for _, link := range span.Links() {
+		linkTraceID, linkSpanID := link.SpanContext.TraceID(), link.SpanContext.SpanID()
+		result.Links = append(
+			result.Links,
+			&tracepb.Span_Link{
+				TraceId:                linkTraceID[:],
+				SpanId:                 linkSpanID[:],
+				TraceState:             link.SpanContext.TraceState().String(),
+				Attributes:             otlpAttributes(link.Attributes),
+				DroppedAttributesCount: uint32(link.DroppedAttributeCount),
+				Flags:                  otlpFlags(link.SpanContext.TraceFlags(), link.SpanContext),
+			},
+		)
+	}
+	return result
+}
+
+// otlpFlags has W3C trace flags in the lower 8 bits and whether the parent or the linked span is remote
+func otlpFlags(flags trace.TraceFlags, context trace.SpanContext) uint32 {
+	result := uint32(flags) | uint32(tracepb.SpanFlags_SPAN_FLAGS_CONTEXT_HAS_IS_REMOTE_MASK)
+	if context.IsRemote() {
+		result |= uint32(tracepb.SpanFlags_SPAN_FLAGS_CONTEXT_IS_REMOTE_MASK)
+	}
+	return result
+}
+
+func unixNano(t time.Time) uint64 {
+	if t.IsZero() {
+		return 0
+	}
+	return uint64(t.UnixNano())
+}
+
+func otlpAttributes(attributes []attribute.KeyValue) []*commonpb.KeyValue {
+	if len(attributes) == 0 {
+		return nil
+	}
+	result := make([]*commonpb.KeyValue, 0, len(attributes))
+	for _, kv := range attributes {
+		result = append(result, &commonpb.KeyValue{Key: string(kv.Key), Value: otlpValue(kv.Value)})
+	}
+	return result
+}
+
+func otlpValue(value attribute.Value) *commonpb.AnyValue {
+	switch value.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: value.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: value.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: value.AsFloat64()}}
+	case attribute.BOOLSLICE:
+		return otlpArray(value.AsBoolSlice(), attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return otlpArray(value.AsInt64Slice(), attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return otlpArray(value.AsFloat64Slice(), attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return otlpArray(value.AsStringSlice(), attribute.StringValue)
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value.Emit()}}
+	}
+}
+
+func otlpArray[T any](values []T, toValue func(T) attribute.Value) *commonpb.AnyValue {
+	array := &commonpb.ArrayValue{Values: make([]*commonpb.AnyValue, 0, len(values))}
+	for _, v := range values {
+		array.Values = append(array.Values, otlpValue(toValue(v)))
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: array}}
+}
@@ -0,0 +1,148 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"testing"
+)
+
+func TestOTLPFileExporter(t *testing.T) {
+	var buf bytes.Buffer
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(newOTLPFileExporter(&buf)),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	tracer := provider.Tracer("orders")
+	ctx, parent := tracer.Start(context.Background(), "parent", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tracer.Start(
+		ctx, "child", trace.WithAttributes(attribute.Int("rows", 3), attribute.StringSlice("pairs", []string{"A_B"})),
+	)
+	End(child, errors.New("db is down"))
+	parent.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2, "every batch is a line")
+	var request map[string]any
+	if err := json.Unmarshal(lines[0], &request); err != nil {
+		t.Fatal(err)
+	}
+	resourceSpans := request["resourceSpans"].([]any)[0].(map[string]any)
+	assert.Equal(
+		t,
+		[]any{map[string]any{"key": "service.name", "value": map[string]any{"stringValue": serviceName}}},
+		resourceSpans["resource"].(map[string]any)["attributes"],
+	)
+	scopeSpans := resourceSpans["scopeSpans"].([]any)[0].(map[string]any)
+	assert.Equal(t, map[string]any{"name": "orders"}, scopeSpans["scope"])
+
+	span := scopeSpans["spans"].([]any)[0].(map[string]any)
+	assert.Equal(t, "child", span["name"])
+	assert.Equal(t, child.SpanContext().TraceID().String(), span["traceId"], "ids are hex")
+	assert.Equal(t, parent.SpanContext().SpanID().String(), span["parentSpanId"])
+	assert.Equal(t, float64(trace.SpanKindInternal), span["kind"])
+	assert.IsType(t, "", span["startTimeUnixNano"], "64-bit integers are strings")
+	assert.Equal(t, map[string]any{"message": "db is down", "code": float64(2)}, span["status"])
+	assert.Equal(
+		t,
+		[]any{
+			map[string]any{"key": "rows", "value": map[string]any{"intValue": "3"}},
+			map[string]any{
+				"key":   "pairs",
+				"value": map[string]any{"arrayValue": map[string]any{"values": []any{map[string]any{"stringValue": "A_B"}}}},
+			},
+		},
+		span["attributes"],
+	)
+	assert.Equal(t, "exception", span["events"].([]any)[0].(map[string]any)["name"])
+}
+
+func TestOTLPFileExporter_Proto(t *testing.T) {
+	var buf bytes.Buffer
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(newOTLPFileExporter(&buf)))
+	remote := trace.NewSpanContext(
+		trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1, 2, 3},
+			SpanID:     trace.SpanID{4, 5, 6},
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		},
+	)
+	_, span := provider.Tracer("orders").Start(
+		context.Background(), "link", trace.WithSpanKind(trace.SpanKindClient), trace.WithLinks(trace.Link{SpanContext: remote}),
+	)
+	span.End()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// protojson expects ids as base64, OTLP JSON has them as hex
+	var line map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &line); err != nil {
+		t.Fatal(err)
+	}
+	if err := base64IDs(line); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(line)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var request collectorpb.ExportTraceServiceRequest
+	if err := protojson.Unmarshal(b, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := request.GetResourceSpans()[0].GetScopeSpans()[0].GetSpans()
+	assert.Len(t, spans, 1)
+	traceID, spanID := span.SpanContext().TraceID(), span.SpanContext().SpanID()
+	assert.Equal(t, traceID[:], spans[0].GetTraceId())
+	assert.Equal(t, spanID[:], spans[0].GetSpanId())
+	assert.Equal(t, tracepb.Span_SPAN_KIND_CLIENT, spans[0].GetKind())
+	assert.Equal(t, uint32(0x101), spans[0].GetFlags(), "sampled, parent is known to be not remote")
+	links := spans[0].GetLinks()
+	assert.Len(t, links, 1)
+	assert.Equal(t, []byte{1, 2, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, links[0].GetTraceId())
+	assert.Equal(t, []byte{4, 5, 6, 0, 0, 0, 0, 0}, links[0].GetSpanId())
+	assert.Equal(t, uint32(0x301), links[0].GetFlags(), "sampled, linked span is remote")
+}
+
+func base64IDs(value any) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if encoded, ok := field.(string); ok && otlpIDFields[key] {
+				id, err := hex.DecodeString(encoded)
+				if err != nil {
+					return err
+				}
+				v[key] = base64.StdEncoding.EncodeToString(id)
+				continue
+			}
+			if err := base64IDs(field); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := base64IDs(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"os"
+)
+
+// Exporters
+const (
+	// ExporterNone doesn't export spans, trace ids are still propagated and logged
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	// ExporterFile appends spans to a file as OTLP JSON lines, like the OpenTelemetry Collector file exporter
+	ExporterFile = "file"
+)
+
+var ErrUnknownExporter = errors.New("unknown tracing exporter")
+
+const serviceName = "order-service"
+
+type Params struct {
+	Exporter string
+	// File is used by ExporterFile
+	File string
+	// SampleRatio is fraction of root spans sampled, child spans follow the decision of the parent
+	SampleRatio float64
+}
+
+// Setup installs global tracer provider and W3C trace context propagator.
+// Returned function flushes spans and closes the exporter
+func Setup(params Params) (func(ctx context.Context) error, error) {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(params.SampleRatio))),
+		sdktrace.WithResource(
+			resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)),
+		),
+	}
+	var closer io.Closer
+	switch params.Exporter {
+	case ExporterNone, "":
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case ExporterFile:
+		file, err := os.OpenFile(params.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(newOTLPFileExporter(file)))
+		closer = file
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExporter, params.Exporter)
+	}
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// End records err on span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
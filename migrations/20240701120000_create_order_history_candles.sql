@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS order_history_candles_1m
+(
+    exchange_name String,
+    pair          String,
+    bucket        DateTime,
+    open          AggregateFunction(argMin, Float64, DateTime),
+    high          SimpleAggregateFunction(max, Float64),
+    low           SimpleAggregateFunction(min, Float64),
+    close         AggregateFunction(argMax, Float64, DateTime),
+    base_volume   SimpleAggregateFunction(sum, Float64),
+    quote_volume  SimpleAggregateFunction(sum, Float64),
+    trades        SimpleAggregateFunction(sum, UInt64)
+)
+    ENGINE = AggregatingMergeTree
+    ORDER BY (exchange_name, pair, bucket);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE MATERIALIZED VIEW IF NOT EXISTS order_history_candles_1m_mv TO order_history_candles_1m AS
+SELECT exchange_name,
+       pair,
+       toStartOfMinute(time_placed)    AS bucket,
+       argMinState(price, time_placed) AS open,
+       max(price)                      AS high,
+       min(price)                      AS low,
+       argMaxState(price, time_placed) AS close,
+       sum(base_qty)                   AS base_volume,
+       sum(base_qty * price)           AS quote_volume,
+       count()                         AS trades
+FROM order_history
+GROUP BY exchange_name, pair, bucket;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO order_history_candles_1m
+SELECT exchange_name,
+       pair,
+       toStartOfMinute(time_placed)    AS bucket,
+       argMinState(price, time_placed) AS open,
+       max(price)                      AS high,
+       min(price)                      AS low,
+       argMaxState(price, time_placed) AS close,
+       sum(base_qty)                   AS base_volume,
+       sum(base_qty * price)           AS quote_volume,
+       count()                         AS trades
+FROM order_history
+GROUP BY exchange_name, pair, bucket;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW IF EXISTS order_history_candles_1m_mv;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_history_candles_1m;
+-- +goose StatementEnd
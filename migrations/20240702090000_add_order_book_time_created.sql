@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE order_book ADD COLUMN IF NOT EXISTS time_created DateTime64(3) DEFAULT now64(3);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE order_book MATERIALIZE COLUMN time_created;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE order_book DROP COLUMN IF EXISTS time_created;
+-- +goose StatementEnd
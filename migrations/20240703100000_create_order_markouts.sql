@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS order_markouts
+(
+    client_name           String,
+    exchange_name         String,
+    label                 String,
+    pair                  String,
+    algorithm_name_placed String,
+    side                  String,
+    base_qty              Float64,
+    price                 Float64,
+    time_placed           DateTime,
+    horizon_seconds       UInt32,
+    mid                   Float64,
+    markout_bps           Float64,
+    markout_quote         Float64,
+    time_computed         DateTime DEFAULT now()
+)
+    ENGINE = ReplacingMergeTree(time_computed)
+    ORDER BY (exchange_name, pair, time_placed, client_name, label, algorithm_name_placed, side, price, base_qty,
+              horizon_seconds);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_markouts;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS order_lifecycle
+(
+    order_id      UUID,
+    client_name   String,
+    exchange_name String,
+    label         String,
+    pair          String,
+    side          String,
+    type          String,
+    base_qty      Float64,
+    price         Float64,
+    time_created  DateTime64(3) DEFAULT now64(3)
+)
+    ENGINE = ReplacingMergeTree
+    ORDER BY order_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS order_events
+(
+    order_id UUID,
+    status   LowCardinality(String),
+    reason   String,
+    time     DateTime64(3) DEFAULT now64(3)
+)
+    ENGINE = MergeTree
+    ORDER BY (order_id, time);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS order_fills
+(
+    fill_id              UUID,
+    order_id             UUID,
+    base_qty             Float64,
+    price                Float64,
+    commission_quote_qty Float64,
+    time                 DateTime64(3) DEFAULT now64(3)
+)
+    ENGINE = ReplacingMergeTree
+    ORDER BY (order_id, fill_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_fills;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_events;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_lifecycle;
+-- +goose StatementEnd
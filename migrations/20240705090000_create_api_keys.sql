@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    name         String,
+    key_hash     String,
+    clients      Array(String),
+    exchanges    Array(String),
+    disabled     Bool     DEFAULT false,
+    time_updated DateTime DEFAULT now()
+)
+    ENGINE = ReplacingMergeTree(time_updated)
+    ORDER BY key_hash;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+// Package migrations embeds goose migrations, so the binary knows the schema version it expects
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+var ErrNoMigrations = errors.New("no migrations found")
+
+// Version parses version from migration file name of the form <version>_<name>.sql
+func Version(name string) (int64, error) {
+	version, _, _ := strings.Cut(path.Base(name), "_")
+	return strconv.ParseInt(version, 10, 64)
+}
+
+// LatestVersion returns version of the newest embedded migration
+func LatestVersion() (int64, error) {
+	names, err := fs.Glob(FS, "*.sql")
+	if err != nil {
+		return 0, err
+	}
+	var latest int64
+	for _, name := range names {
+		version, err := Version(name)
+		if err != nil {
+			return 0, err
+		}
+		latest = max(latest, version)
+	}
+	if latest == 0 {
+		return 0, ErrNoMigrations
+	}
+	return latest, nil
+}